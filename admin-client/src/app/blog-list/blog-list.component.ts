@@ -28,7 +28,7 @@ export class BlogListComponent implements OnInit {
 
         const res = await this.api.Blogs.List();
         if (res.ok) {
-            this.blogs = res.data;
+            this.blogs = res.data.items;
         } else {
             this.error = res.error;
         }
@@ -229,7 +229,9 @@ export class CreateBlogComponent implements OnInit {
 
         const res = await this.api.Blogs.Create(this.model);
         if (res.ok) {
-            this.router.navigateByUrl("blogs/" + res.data);
+            this.router.navigateByUrl("blogs/" + res.data.id, {
+                state: { strippedContent: res.data.strippedContent },
+            });
         } else {
             this.error = res.error;
         }
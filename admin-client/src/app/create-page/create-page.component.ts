@@ -229,7 +229,9 @@ export class CreatePageComponent implements OnInit {
 
         const res = await this.api.Pages.Create(this.model);
         if (res.ok) {
-            this.router.navigateByUrl("pages/" + res.data);
+            this.router.navigateByUrl("pages/" + res.data.id, {
+                state: { strippedContent: res.data.strippedContent },
+            });
         } else {
             this.error = res.error;
         }
@@ -18,6 +18,7 @@ export class EditPageComponent implements OnInit {
     model: Page;
     contentEditor = ClassicEditor;
     error: string = null;
+    strippedContent: string[] = null;
     loading: boolean = false;
     fileInputLabel: string = "Choose file";
 
@@ -35,6 +36,7 @@ export class EditPageComponent implements OnInit {
 
     ngOnInit(): void {
         this.titleService.setTitle("Edit Page - Distro Blog Admin");
+        this.strippedContent = history.state?.strippedContent || null;
 
         this.route.paramMap.subscribe(
             async (params) => await this.fetchPage(params.get("id"))
@@ -197,6 +199,7 @@ export class EditPageComponent implements OnInit {
             this.error = res.error;
         } else {
             this.error = null;
+            this.strippedContent = res.data?.strippedContent || null;
             await this.fetchPage(this.model.id);
         }
 
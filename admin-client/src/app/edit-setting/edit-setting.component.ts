@@ -78,6 +78,8 @@ export class EditSettingComponent implements OnInit {
         switch (this.model?.key) {
             case "TITLE_FORMAT":
                 return 'The format of page titles. Use replacements "{TITLE}" for a page\'s title and "{SITE_NAME}" for the site name.';
+            case "ROBOTS_DISALLOW":
+                return 'Paths search engines should not crawl, separated by commas, i.e. "/admin, /private".';
             default:
                 return null;
         }
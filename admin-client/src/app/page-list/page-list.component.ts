@@ -27,7 +27,7 @@ export class PageListComponent implements OnInit {
 
         const res = await this.api.Pages.List();
         if (res.ok) {
-            this.pages = res.data;
+            this.pages = res.data.items;
         } else {
             this.error = res.error;
         }
@@ -13,7 +13,7 @@ export class ResetPasswordComponent implements OnInit {
     loading: boolean = false;
     error: string = null;
 
-    newPassword: string = null;
+    linkSent: boolean = false;
 
     constructor(private api: ApiService) {}
 
@@ -29,9 +29,9 @@ export class ResetPasswordComponent implements OnInit {
         const res = await this.api.Users.ResetPassword(this.userId);
         if (res.ok) {
             this.error = null;
-            this.newPassword = res.data;
+            this.linkSent = true;
         } else {
-            this.newPassword = null;
+            this.linkSent = false;
             this.error = res.error;
         }
 
@@ -5,6 +5,15 @@ import "time"
 type AccessToken struct {
 	Token string `json:"token"`
 	Expires int64 `json:"expires"`
+
+	// RefreshToken can be used once to get a new access token,
+	// along with a new refresh token which replaces it.
+	RefreshToken string `json:"refreshToken,omitempty"`
+	RefreshTokenExpires int64 `json:"refreshTokenExpires,omitempty"`
+
+	// MFAEnrollmentRequired is set if some of the user's scopes were withheld,
+	// as they require two-factor authentication, which the user hasn't enabled.
+	MFAEnrollmentRequired bool `json:"mfaEnrollmentRequired,omitempty"`
 }
 
 func NewAccessToken(tkn Token, exp time.Time) *AccessToken {
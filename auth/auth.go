@@ -3,26 +3,20 @@ package auth
 import (
 	"bytes"
 	"context"
-	"crypto"
-	"crypto/rsa"
-	"crypto/x509"
 	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"log"
+	"os"
 	"strings"
 	"time"
 
-	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/session"
-	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/google/uuid"
 
-	"github.com/reecerussell/distro-blog/libraries/contextkey"
 	"github.com/reecerussell/distro-blog/libraries/logging"
 )
 
 var encoding = base64.RawURLEncoding
-const signingAlgorithm = "RSASSA_PKCS1_V1_5_SHA_512"
 
 // Common errors.
 var (
@@ -33,9 +27,24 @@ var (
 
 // Registered claims
 const (
+	ClaimTypeIssuer = "iss"
+	ClaimTypeSubject = "sub"
+	ClaimTypeAudience = "aud"
 	ClaimTypeExpiry = "exp"
 	ClaimTypeNotBefore = "nbf"
 	ClaimTypeIssuedAt = "iat"
+	ClaimTypeTokenID = "jti"
+	ClaimTypeAuthMethods = "amr"
+)
+
+// Environment variables used to configure the issuer and audience of tokens,
+// and the defaults used if they're not set.
+const (
+	EnvIssuer = "JWT_ISSUER"
+	EnvAudience = "JWT_AUDIENCE"
+
+	DefaultIssuer = "distro-blog"
+	DefaultAudience = "distro-blog"
 )
 
 // Custom claim types.
@@ -50,69 +59,151 @@ const (
 	ScopeUserWrite = "users:write"
 	ScopePageRead = "pages:read"
 	ScopePageWrite = "pages:write"
+
+	// ScopeMFAEnroll is given to users who must enable two-factor authentication
+	// before they're given the rest of their scopes.
+	ScopeMFAEnroll = "mfa:enroll"
 )
 
+// RevocationStore is used to check whether a token has been revoked before it expired,
+// either by its id, or because all of the tokens issued to the user have been revoked.
+type RevocationStore interface {
+	IsRevoked(ctx context.Context, tokenID, userID string, issuedAt time.Time) (bool, error)
+}
+
 // Service is used to handle authentication and authorization of users
 // using JSON-Web-Tokens.
 type Service struct {
-	svc *kms.KMS
+	signer Signer
+	revocations RevocationStore
+	issuer string
+	audience string
 }
 
-func New() *Service {
-	sess, _ := session.NewSession()
-	svc := kms.New(sess)
-
+// New returns a new instance of Service, which signs and verifies tokens
+// using the signer. If revocations is nil, tokens are valid until they
+// expire, without checking if they've been revoked.
+//
+// Tokens are issued for, and must be intended for, the issuer and audience
+// set by the JWT_ISSUER and JWT_AUDIENCE environment variables.
+func New(signer Signer, revocations RevocationStore) *Service {
 	return &Service{
-		svc: svc,
+		signer: signer,
+		revocations: revocations,
+		issuer: envOrDefault(EnvIssuer, DefaultIssuer),
+		audience: envOrDefault(EnvAudience, DefaultAudience),
 	}
 }
 
+func envOrDefault(name, def string) string {
+	if v := os.Getenv(name); v != "" {
+		return v
+	}
+
+	return def
+}
+
+// tokenHeader is the JOSE header of a token.
+type tokenHeader struct {
+	Algorithm string `json:"alg"`
+	Type string `json:"typ"`
+	KeyID string `json:"kid,omitempty"`
+}
+
+// Audience returns the audience access tokens are issued for.
+func (as *Service) Audience() string {
+	return as.audience
+}
+
+// VerifyToken verifies that the token is a valid access token.
 func (as *Service) VerifyToken(ctx context.Context, data []byte) bool {
+	return as.VerifyTokenForAudience(ctx, data, as.audience)
+}
+
+// VerifyTokenForAudience verifies the token, which must be intended for the given
+// audience. This is used for tokens which aren't access tokens, such as those used
+// between the steps of signing in.
+func (as *Service) VerifyTokenForAudience(ctx context.Context, data []byte, audience string) bool {
 	token := Token(data)
 	ld, sig, err := token.scan()
 	if err != nil {
 		return false
 	}
 
-	digest := crypto.SHA512.New()
-	digest.Write(data[:ld])
+	header, err := token.header()
+	if err != nil {
+		logging.Debugf("Token header is not valid: %v\n", err)
+		return false
+	}
 
-	keyIDValue := ctx.Value(contextkey.ContextKey("JWT_KEY_ID"))
-	if keyIDValue == nil {
-		logging.Errorf("key id value is not set\n")
+	keyID := header.KeyID
+	if header.Type != "JWT" || keyID == "" {
+		logging.Debugf("Token header is not valid.\n")
 		return false
 	}
 
-	logging.Debugf("Attempting to verify token with KMS Key: %v\n", keyIDValue)
+	logging.Debugf("Attempting to verify token with key: %s\n", keyID)
 
-	res, err := as.svc.Verify(&kms.VerifyInput{
-		Signature: sig,
-		SigningAlgorithm: aws.String(signingAlgorithm),
-		Message: digest.Sum(sig[len(sig):]),
-		MessageType: aws.String("DIGEST"),
-		KeyId: aws.String(keyIDValue.(string)),
-	})
+	alg, err := as.signer.Algorithm(ctx, keyID)
 	if err != nil {
-		logging.Errorf("failed to verify: %v", err)
+		logging.Errorf("failed to verify: %v\n", err)
+		return false
+	}
+
+	if header.Algorithm != alg {
+		logging.Debugf("Token algorithm '%s' does not match the key.\n", header.Algorithm)
 		return false
 	}
 
-	if !*res.SignatureValid {
-		logging.Debugf("Token signature is not valid.\n")
+	err = as.signer.Verify(ctx, keyID, data[:ld], sig)
+	if err != nil {
+		logging.Debugf("Token signature is not valid: %v\n", err)
 		return false
 	}
 
 	exp := token.Number(ClaimTypeExpiry)
 	nbf := token.Number(ClaimTypeNotBefore)
-	n := convertToMilliseconds(time.Now().UTC())
+	n := numericDate(time.Now().UTC())
 
 	// ensure token expiry is within bounds
 	ok := (exp == nil || *exp > n) && (nbf == nil || *nbf <= n)
 	if !ok {
 		logging.Debugf("Token is out of time bounds.\n")
+		return false
+	}
+
+	if token.StringValue(ClaimTypeIssuer) != as.issuer || !token.hasAudience(audience) {
+		logging.Debugf("Token was not issued by '%s' for '%s'.\n", as.issuer, audience)
+		return false
 	}
 
-	return ok
+	return !as.isRevoked(ctx, token)
+}
+
+// isRevoked checks the token against the revocation store, if there is one. Tokens
+// are treated as revoked if the store can't be read, so they can't be used while
+// it's unavailable.
+func (as *Service) isRevoked(ctx context.Context, token Token) bool {
+	if as.revocations == nil {
+		return false
+	}
+
+	var issuedAt time.Time
+	if iat := token.Number(ClaimTypeIssuedAt); iat != nil {
+		issuedAt = time.Unix(int64(*iat), 0).UTC()
+	}
+
+	revoked, err := as.revocations.IsRevoked(ctx, token.StringValue(ClaimTypeTokenID), token.StringValue(ClaimTypeUserId), issuedAt)
+	if err != nil {
+		logging.Errorf("failed to check if the token has been revoked: %v\n", err)
+		return true
+	}
+
+	if revoked {
+		logging.Debugf("Token has been revoked.\n")
+	}
+
+	return revoked
 }
 
 // TokenBuilder is an extension type to the Service struct, used to
@@ -154,109 +245,102 @@ func (tb *TokenBuilder) AddClaims(claims map[string]interface{}) *TokenBuilder {
 	return tb
 }
 
+// SetSubject sets the "Subject" claim, which is the id of the user
+// the token was issued to.
+func (tb *TokenBuilder) SetSubject(sub string) *TokenBuilder {
+	tb.AddClaim(ClaimTypeSubject, sub)
+	return tb
+}
+
+// SetAudience sets the "Audience" claim, which is the recipient the token
+// is intended for. By default, tokens are intended for the service's audience.
+func (tb *TokenBuilder) SetAudience(aud string) *TokenBuilder {
+	tb.AddClaim(ClaimTypeAudience, aud)
+	return tb
+}
+
 // SetExpiry sets the "Expiry" claim to the given time, in the form
-// of the number of milliseconds since 1970-01-01T00:00:00Z UTC,
+// of the number of seconds since 1970-01-01T00:00:00Z UTC,
 // ignoring leap seconds.
 func (tb *TokenBuilder) SetExpiry(t time.Time) *TokenBuilder {
-	ms := convertToMilliseconds(t)
-	tb.AddClaim(ClaimTypeExpiry, ms)
+	tb.AddClaim(ClaimTypeExpiry, numericDate(t))
 	return tb
 }
 
 // SetIssuedAt sets the "Issued At" claim to the given time, in the form
-// of the number of milliseconds since 1970-01-01T00:00:00Z UTC,
+// of the number of seconds since 1970-01-01T00:00:00Z UTC,
 // ignoring leap seconds.
 func (tb *TokenBuilder) SetIssuedAt(t time.Time) *TokenBuilder {
-	ms := convertToMilliseconds(t)
-	tb.AddClaim(ClaimTypeIssuedAt, ms)
+	tb.AddClaim(ClaimTypeIssuedAt, numericDate(t))
 	return tb
 }
 
 // SetNotBefore sets the "Not Before" claim to the given time, in the form
-// of the number of milliseconds since 1970-01-01T00:00:00Z UTC,
+// of the number of seconds since 1970-01-01T00:00:00Z UTC,
 // ignoring leap seconds.
 func (tb *TokenBuilder) SetNotBefore(t time.Time) *TokenBuilder {
-	ms := convertToMilliseconds(t)
-	tb.AddClaim(ClaimTypeNotBefore, ms)
+	tb.AddClaim(ClaimTypeNotBefore, numericDate(t))
 	return tb
 }
 
-func convertToMilliseconds(t time.Time) float64 {
-	return float64(t.UnixNano() / 1e9)
+// numericDate returns t as an RFC 7519 NumericDate, which is
+// the number of whole seconds since the epoch.
+func numericDate(t time.Time) float64 {
+	return float64(t.Unix())
 }
 
-// Build constructs the token using the data from the TokenBuilder.
+// Build constructs the token using the data from the TokenBuilder, signing
+// it with the signer's current key, which is identified by the "kid" header.
+// The issuer, audience and a unique token id are added, if they're not set.
 func (tb *TokenBuilder) Build() Token {
-	// encode the header
-	headerPayload := map[string]string {
-		"alg": "RSA256",
-		"typ": "JWT",
-	}
-	headerData, _ := json.Marshal(headerPayload)
-	header := make([]byte, encoding.EncodedLen(len(headerData)))
-	encoding.Encode(header, headerData)
+	signer := tb.as.signer
 
-	keyIDValue := tb.ctx.Value(contextkey.ContextKey("JWT_KEY_ID"))
-	if keyIDValue == nil {
-		log.Printf("[ERROR]: key id value is not set")
-		return nil
+	defaults := map[string]interface{}{
+		ClaimTypeIssuer: tb.as.issuer,
+		ClaimTypeAudience: tb.as.audience,
+		ClaimTypeTokenID: uuid.New().String(),
+	}
+	for k, v := range defaults {
+		if _, ok := tb.claims[k]; !ok {
+			tb.claims[k] = v
+		}
 	}
 
-	keySize, err := tb.getPublicKeySize(keyIDValue.(string))
+	keyID, err := signer.KeyID(tb.ctx)
 	if err != nil {
 		log.Printf("[ERROR]: %v", err)
 		return nil
 	}
 
-	// construct token
-	payload, _ := json.Marshal(tb.claims)
-	l := len(header) + 1 + encoding.EncodedLen(len(payload))
-	token := make([]byte, l, l+1+encoding.EncodedLen(keySize))
-
-	i := copy(token, header)
-	token[i] = '.'
-	i++
-	encoding.Encode(token[i:], payload)
-	token = token[:l]
-
-	digest := crypto.SHA512.New()
-	digest.Write(token)
-
-	// use signature space as a buffer while it's not set
-	buf := token[len(token):]
-	res, err := tb.as.svc.Sign(&kms.SignInput{
-		KeyId: aws.String(keyIDValue.(string)),
-		SigningAlgorithm: aws.String(signingAlgorithm),
-		MessageType: aws.String("DIGEST"),
-		Message: digest.Sum(buf),
-	})
+	alg, err := signer.Algorithm(tb.ctx, keyID)
 	if err != nil {
 		log.Printf("[ERROR]: %v", err)
 		return nil
 	}
 
-	i = len(token)
-	token = token[:cap(token)]
-	token[i] = '.'
-	encoding.Encode(token[i+1:], res.Signature)
+	// encode the header and payload
+	headerData, _ := json.Marshal(&tokenHeader{
+		Algorithm: alg,
+		Type: "JWT",
+		KeyID: keyID,
+	})
+	payload, _ := json.Marshal(tb.claims)
 
-	return token
-}
+	token := make([]byte, 0, encoding.EncodedLen(len(headerData))+1+encoding.EncodedLen(len(payload)))
+	token = append(token, encoding.EncodeToString(headerData)...)
+	token = append(token, '.')
+	token = append(token, encoding.EncodeToString(payload)...)
 
-func (tb *TokenBuilder) getPublicKeySize(keyID string) (int, error) {
-	res, err := tb.as.svc.GetPublicKey(&kms.GetPublicKeyInput{
-		KeyId: aws.String(keyID),
-	})
+	sig, err := signer.Sign(tb.ctx, keyID, token)
 	if err != nil {
-		return 0, err
+		log.Printf("[ERROR]: %v", err)
+		return nil
 	}
 
-	key, err := x509.ParsePKIXPublicKey(res.PublicKey)
-	if err != nil {
-		return 0, err
-	}
+	token = append(token, '.')
+	token = append(token, encoding.EncodeToString(sig)...)
 
-	return key.(*rsa.PublicKey).Size(), nil
+	return token
 }
 
 // Number returns a number for the given claim from the Token payload.
@@ -271,6 +355,13 @@ func (t *Token) Number(name string) *float64 {
 	return &f
 }
 
+// StringValue returns the value of a string claim from the payload. If the
+// claim doesn't exist, or isn't a string, an empty string is returned.
+func (t *Token) StringValue(name string) string {
+	v, _ := t.getPayload()[name].(string)
+	return v
+}
+
 // Strings returns a []string from the payload for the given claim. If the
 // claim doesn't exist, a nil-slice will be returned. However, if the claim
 // exists but isn't a []string, it will panic.
@@ -291,6 +382,23 @@ func (t *Token) Strings(name string) []string {
 	return out
 }
 
+// hasAudience determines whether the audience is one of the token's audiences.
+// The "aud" claim can either be a single string, or an array of them.
+func (t *Token) hasAudience(audience string) bool {
+	switch v := t.getPayload()[ClaimTypeAudience].(type) {
+	case string:
+		return v == audience
+	case []interface{}:
+		for _, a := range v {
+			if a == audience {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
 func (t *Token) getPayload() (payload map[string]interface{}) {
 	encodedData := strings.Split(string(*t), ".")[1]
 	rawData, _ := encoding.DecodeString(encodedData)
@@ -298,6 +406,26 @@ func (t *Token) getPayload() (payload map[string]interface{}) {
 	return
 }
 
+func (t Token) header() (*tokenHeader, error) {
+	fd := bytes.IndexByte(t, '.')
+	if fd < 0 {
+		return nil, ErrMalformedStructure
+	}
+
+	data, err := encoding.DecodeString(string(t[:fd]))
+	if err != nil {
+		return nil, ErrMalformedHeader
+	}
+
+	var header tokenHeader
+	err = json.Unmarshal(data, &header)
+	if err != nil {
+		return nil, ErrMalformedHeader
+	}
+
+	return &header, nil
+}
+
 func (t Token) scan() (int, []byte, error) {
 	fd := bytes.IndexByte(t, '.')
 	ld := bytes.LastIndexByte(t, '.')
@@ -3,6 +3,8 @@ package auth
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"testing"
 	"time"
 
@@ -15,7 +17,8 @@ var (
 )
 
 func init() {
-	testService = New()
+	signer, _ := NewKMSSigner("")
+	testService = New(signer, nil)
 }
 
 func TestAuthService_GenerateToken(t *testing.T) {
@@ -175,7 +178,7 @@ func TestTokenBuilder_SetExpiry(t *testing.T) {
 	exp := time.Now().UTC().Add(1 * time.Minute)
 	tkn := testService.NewToken(ctx).SetExpiry(exp).Build()
 
-	expected := convertToMilliseconds(exp)
+	expected := numericDate(exp)
 	actual := tkn.Number(ClaimTypeExpiry)
 	if actual == nil {
 		t.Errorf("expected a non-nil pointer")
@@ -194,7 +197,7 @@ func TestTokenBuilder_SetNotBefore(t *testing.T) {
 	exp := time.Now().UTC()
 	tkn := testService.NewToken(ctx).SetNotBefore(exp).Build()
 
-	expected := convertToMilliseconds(exp)
+	expected := numericDate(exp)
 	actual := tkn.Number(ClaimTypeNotBefore)
 	if actual == nil {
 		t.Errorf("expected a non-nil pointer")
@@ -213,7 +216,7 @@ func TestTokenBuilder_SetIssuedAt(t *testing.T) {
 	exp := time.Now().UTC()
 	tkn := testService.NewToken(ctx).SetIssuedAt(exp).Build()
 
-	expected := convertToMilliseconds(exp)
+	expected := numericDate(exp)
 	actual := tkn.Number(ClaimTypeIssuedAt)
 	if actual == nil {
 		t.Errorf("expected a non-nil pointer")
@@ -225,4 +228,44 @@ func TestTokenBuilder_SetIssuedAt(t *testing.T) {
 	}
 }
 
+type testRevocationStore struct {
+	revoked bool
+	err error
+	tokenID, userID string
+	issuedAt time.Time
+}
+
+func (s *testRevocationStore) IsRevoked(ctx context.Context, tokenID, userID string, issuedAt time.Time) (bool, error) {
+	s.tokenID, s.userID, s.issuedAt = tokenID, userID, issuedAt
+	return s.revoked, s.err
+}
+
+func TestAuthService_IsRevoked(t *testing.T) {
+	iat := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+	payload, _ := json.Marshal(map[string]interface{}{
+		ClaimTypeTokenID: "4d1c7a52",
+		ClaimTypeUserId: "139721",
+		ClaimTypeIssuedAt: numericDate(iat),
+	})
+	tkn := Token("e30." + encoding.EncodeToString(payload) + ".c2ln")
+
+	if New(nil, nil).isRevoked(context.Background(), tkn) {
+		t.Errorf("expected tokens not to be revoked without a store")
+	}
+
+	store := &testRevocationStore{revoked: true}
+	if !New(nil, store).isRevoked(context.Background(), tkn) {
+		t.Errorf("expected the token to be revoked")
+	}
+
+	if store.tokenID != "4d1c7a52" || store.userID != "139721" || !store.issuedAt.Equal(iat) {
+		t.Errorf("unexpected claims passed to the store: '%s', '%s', '%v'", store.tokenID, store.userID, store.issuedAt)
+	}
+
+	store = &testRevocationStore{err: errors.New("connection refused")}
+	if !New(nil, store).isRevoked(context.Background(), tkn) {
+		t.Errorf("expected the token to be treated as revoked when the store fails")
+	}
+}
+
 // TODO: add test for ensuring payload is valid
\ No newline at end of file
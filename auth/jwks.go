@@ -0,0 +1,131 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"math/big"
+)
+
+// JSONWebKey is the RFC 7517 representation of a public key, used to verify tokens.
+type JSONWebKey struct {
+	KeyType string `json:"kty"`
+	Use string `json:"use,omitempty"`
+	Algorithm string `json:"alg,omitempty"`
+	KeyID string `json:"kid,omitempty"`
+
+	// RSA keys.
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+
+	// ECDSA keys.
+	Curve string `json:"crv,omitempty"`
+	X string `json:"x,omitempty"`
+	Y string `json:"y,omitempty"`
+}
+
+// JSONWebKeySet is a set of keys, as published at /.well-known/jwks.json.
+type JSONWebKeySet struct {
+	Keys []*JSONWebKey `json:"keys"`
+}
+
+// NewJSONWebKey returns a JSONWebKey for the RSA or ECDSA public key.
+func NewJSONWebKey(keyID, alg string, pub crypto.PublicKey) (*JSONWebKey, error) {
+	k := &JSONWebKey{
+		Use: "sig",
+		Algorithm: alg,
+		KeyID: keyID,
+	}
+
+	switch key := pub.(type) {
+	case *rsa.PublicKey:
+		k.KeyType = "RSA"
+		k.N = encoding.EncodeToString(key.N.Bytes())
+		k.E = encoding.EncodeToString(big.NewInt(int64(key.E)).Bytes())
+	case *ecdsa.PublicKey:
+		size := (key.Curve.Params().BitSize + 7) / 8
+		k.KeyType = "EC"
+		k.Curve = key.Curve.Params().Name
+		k.X = encoding.EncodeToString(padLeft(key.X.Bytes(), size))
+		k.Y = encoding.EncodeToString(padLeft(key.Y.Bytes(), size))
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", pub)
+	}
+
+	return k, nil
+}
+
+// Thumbprint returns the RFC 7638 thumbprint of the key, which is the
+// hash of its required members, in lexicographic order.
+func (k *JSONWebKey) Thumbprint() string {
+	var members interface{}
+
+	switch k.KeyType {
+	case "RSA":
+		members = struct {
+			E string `json:"e"`
+			Kty string `json:"kty"`
+			N string `json:"n"`
+		}{k.E, k.KeyType, k.N}
+	case "EC":
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X string `json:"x"`
+			Y string `json:"y"`
+		}{k.Curve, k.KeyType, k.X, k.Y}
+	}
+
+	data, _ := json.Marshal(members)
+	sum := sha256.Sum256(data)
+
+	return encoding.EncodeToString(sum[:])
+}
+
+// KeySet returns the public keys which tokens can be verified with, so
+// they can be published for other services to verify tokens with.
+func (as *Service) KeySet(ctx context.Context) (*JSONWebKeySet, error) {
+	ids, err := as.signer.KeyIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	set := &JSONWebKeySet{
+		Keys: make([]*JSONWebKey, len(ids)),
+	}
+
+	for i, id := range ids {
+		alg, err := as.signer.Algorithm(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		pub, err := as.signer.PublicKey(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		set.Keys[i], err = NewJSONWebKey(id, alg, pub)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return set, nil
+}
+
+// padLeft pads b with leading zeros, to the given size.
+func padLeft(b []byte, size int) []byte {
+	if len(b) >= size {
+		return b
+	}
+
+	out := make([]byte, size)
+	copy(out[size-len(b):], b)
+
+	return out
+}
@@ -0,0 +1,231 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// LocalSigner is an implementation of Signer, which signs tokens in-process, using
+// RSA or ECDSA private keys loaded from PEM data. Tokens are signed with the first
+// key, and can be verified using any of them, so keys can be rotated by adding the
+// new key before the old one.
+type LocalSigner struct {
+	keyIDs []string
+	keys map[string]*localKey
+}
+
+type localKey struct {
+	alg string
+	hash crypto.Hash
+	key crypto.Signer
+}
+
+// NewLocalSigner returns a new LocalSigner, with the private keys in the PEM data.
+// Keys can be in PKCS #1, PKCS #8 or SEC 1 form. The id of each key is its "kid"
+// PEM header, if it has one, otherwise its RFC 7638 JWK thumbprint. RSA keys are
+// used with RS512, unless their "alg" PEM header is "PS256".
+func NewLocalSigner(data []byte) (*LocalSigner, error) {
+	s := &LocalSigner{
+		keys: make(map[string]*localKey),
+	}
+
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+
+		k, err := parsePrivateKey(block)
+		if err != nil {
+			return nil, err
+		}
+
+		kid := block.Headers["kid"]
+		if kid == "" {
+			jwk, _ := NewJSONWebKey("", k.alg, k.key.Public())
+			kid = jwk.Thumbprint()
+		}
+
+		if _, ok := s.keys[kid]; ok {
+			return nil, fmt.Errorf("duplicate key id '%s'", kid)
+		}
+
+		s.keyIDs = append(s.keyIDs, kid)
+		s.keys[kid] = k
+	}
+
+	if len(s.keyIDs) < 1 {
+		return nil, errors.New("no private keys found")
+	}
+
+	return s, nil
+}
+
+func parsePrivateKey(block *pem.Block) (*localKey, error) {
+	var key interface{}
+	var err error
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block type '%s'", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		if k.N.BitLen() < 2048 {
+			return nil, errors.New("RSA keys must be at least 2048 bits")
+		}
+
+		switch block.Headers["alg"] {
+		case "", "RS512":
+			return &localKey{alg: "RS512", hash: crypto.SHA512, key: k}, nil
+		case "PS256":
+			return &localKey{alg: "PS256", hash: crypto.SHA256, key: k}, nil
+		}
+
+		return nil, fmt.Errorf("unsupported algorithm '%s'", block.Headers["alg"])
+	case *ecdsa.PrivateKey:
+		switch k.Curve {
+		case elliptic.P256():
+			return &localKey{alg: "ES256", hash: crypto.SHA256, key: k}, nil
+		case elliptic.P384():
+			return &localKey{alg: "ES384", hash: crypto.SHA384, key: k}, nil
+		case elliptic.P521():
+			return &localKey{alg: "ES512", hash: crypto.SHA512, key: k}, nil
+		}
+
+		return nil, fmt.Errorf("unsupported curve '%s'", k.Curve.Params().Name)
+	}
+
+	return nil, fmt.Errorf("unsupported key type %T", key)
+}
+
+// signerOpts returns the options used to sign with the key. PSS signatures
+// use a salt the same length as the hash, as required by JWS.
+func (k *localKey) signerOpts() crypto.SignerOpts {
+	if k.alg == "PS256" {
+		return &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash, Hash: k.hash}
+	}
+
+	return k.hash
+}
+
+func (s *LocalSigner) key(keyID string) (*localKey, error) {
+	k, ok := s.keys[keyID]
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+
+	return k, nil
+}
+
+// KeyID returns the id of the first key, which is used to sign new tokens.
+func (s *LocalSigner) KeyID(ctx context.Context) (string, error) {
+	return s.keyIDs[0], nil
+}
+
+// KeyIDs returns the ids of all of the keys, in the order they were loaded.
+func (s *LocalSigner) KeyIDs(ctx context.Context) ([]string, error) {
+	return s.keyIDs, nil
+}
+
+// Algorithm returns the JWS algorithm of the key.
+func (s *LocalSigner) Algorithm(ctx context.Context, keyID string) (string, error) {
+	k, err := s.key(keyID)
+	if err != nil {
+		return "", err
+	}
+
+	return k.alg, nil
+}
+
+// Sign returns a signature of the message. ECDSA signatures are returned as the
+// concatenated R and S values, as used by JWS, rather than ASN.1.
+func (s *LocalSigner) Sign(ctx context.Context, keyID string, message []byte) ([]byte, error) {
+	k, err := s.key(keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	digest := k.hash.New()
+	digest.Write(message)
+
+	switch key := k.key.(type) {
+	case *ecdsa.PrivateKey:
+		r, sig, err := ecdsa.Sign(rand.Reader, key, digest.Sum(nil))
+		if err != nil {
+			return nil, err
+		}
+
+		size := (key.Curve.Params().BitSize + 7) / 8
+		return append(padLeft(r.Bytes(), size), padLeft(sig.Bytes(), size)...), nil
+	default:
+		return k.key.Sign(rand.Reader, digest.Sum(nil), k.signerOpts())
+	}
+}
+
+// Verify verifies the signature of the message, using the key.
+func (s *LocalSigner) Verify(ctx context.Context, keyID string, message, signature []byte) error {
+	k, err := s.key(keyID)
+	if err != nil {
+		return err
+	}
+
+	digest := k.hash.New()
+	digest.Write(message)
+
+	switch key := k.key.(type) {
+	case *rsa.PrivateKey:
+		if opts, ok := k.signerOpts().(*rsa.PSSOptions); ok {
+			err = rsa.VerifyPSS(&key.PublicKey, k.hash, digest.Sum(nil), signature, opts)
+		} else {
+			err = rsa.VerifyPKCS1v15(&key.PublicKey, k.hash, digest.Sum(nil), signature)
+		}
+
+		if err != nil {
+			return ErrInvalidSignature
+		}
+	case *ecdsa.PrivateKey:
+		size := (key.Curve.Params().BitSize + 7) / 8
+		if len(signature) != size*2 {
+			return ErrInvalidSignature
+		}
+
+		r := new(big.Int).SetBytes(signature[:size])
+		sig := new(big.Int).SetBytes(signature[size:])
+		if !ecdsa.Verify(&key.PublicKey, digest.Sum(nil), r, sig) {
+			return ErrInvalidSignature
+		}
+	}
+
+	return nil
+}
+
+// PublicKey returns the public half of the key.
+func (s *LocalSigner) PublicKey(ctx context.Context, keyID string) (crypto.PublicKey, error) {
+	k, err := s.key(keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	return k.key.Public(), nil
+}
@@ -0,0 +1,184 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+	"time"
+)
+
+func testRSAKeyPEM(t *testing.T, headers map[string]string) []byte {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{
+		Type: "RSA PRIVATE KEY",
+		Headers: headers,
+		Bytes: x509.MarshalPKCS1PrivateKey(key),
+	})
+}
+
+func testECDSAKeyPEM(t *testing.T, curve elliptic.Curve) []byte {
+	key, err := ecdsa.GenerateKey(curve, rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	data, _ := x509.MarshalPKCS8PrivateKey(key)
+	return pem.EncodeToMemory(&pem.Block{
+		Type: "PRIVATE KEY",
+		Bytes: data,
+	})
+}
+
+func TestLocalSigner(t *testing.T) {
+	keys := map[string][]byte{
+		"RS512": testRSAKeyPEM(t, nil),
+		"PS256": testRSAKeyPEM(t, map[string]string{"alg": "PS256"}),
+		"ES256": testECDSAKeyPEM(t, elliptic.P256()),
+		"ES384": testECDSAKeyPEM(t, elliptic.P384()),
+		"ES512": testECDSAKeyPEM(t, elliptic.P521()),
+	}
+
+	for alg, data := range keys {
+		t.Run(alg, func(t *testing.T) {
+			signer, err := NewLocalSigner(data)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			ctx := context.Background()
+			keyID, _ := signer.KeyID(ctx)
+			if a, _ := signer.Algorithm(ctx, keyID); a != alg {
+				t.Errorf("expected '%s' but got '%s'", alg, a)
+			}
+
+			serv := New(signer, nil)
+			tkn := serv.NewToken(ctx).
+				SetExpiry(time.Now().UTC().Add(time.Minute)).
+				AddClaim(ClaimTypeUserId, "139721").
+				Build()
+			if tkn == nil {
+				t.Fatalf("expected a token")
+			}
+
+			header, _ := tkn.header()
+			if header.Algorithm != alg || header.KeyID != keyID || header.Type != "JWT" {
+				t.Errorf("expected header '%s' '%s' but got '%s' '%s'", alg, keyID, header.Algorithm, header.KeyID)
+			}
+
+			if tkn.StringValue(ClaimTypeIssuer) != DefaultIssuer || !tkn.hasAudience(DefaultAudience) || tkn.StringValue(ClaimTypeTokenID) == "" {
+				t.Errorf("expected the registered claims to be set")
+			}
+
+			if !serv.VerifyToken(ctx, tkn) {
+				t.Errorf("expected token to be valid")
+			}
+
+			tampered := Token(string(tkn[:len(tkn)-4]) + "AAAA")
+			if serv.VerifyToken(ctx, tampered) {
+				t.Errorf("expected a tampered token to be invalid")
+			}
+		})
+	}
+}
+
+func TestLocalSigner_KeySelection(t *testing.T) {
+	current := testRSAKeyPEM(t, map[string]string{"kid": "2020-06"})
+	previous := testRSAKeyPEM(t, map[string]string{"kid": "2020-01"})
+	ctx := context.Background()
+
+	old, _ := NewLocalSigner(previous)
+	tkn := New(old, nil).NewToken(ctx).AddClaim(ClaimTypeUserId, "139721").Build()
+
+	signer, err := NewLocalSigner(append(current, previous...))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if keyID, _ := signer.KeyID(ctx); keyID != "2020-06" {
+		t.Errorf("expected the first key to be used but got '%s'", keyID)
+	}
+
+	if !New(signer, nil).VerifyToken(ctx, tkn) {
+		t.Errorf("expected a token signed with the previous key to be valid")
+	}
+
+	signer, _ = NewLocalSigner(current)
+	if New(signer, nil).VerifyToken(ctx, tkn) {
+		t.Errorf("expected a token signed with an unknown key to be invalid")
+	}
+}
+
+func TestNewLocalSigner_Invalid(t *testing.T) {
+	small, _ := rsa.GenerateKey(rand.Reader, 1024)
+	tests := map[string][]byte{
+		"Empty": []byte("not a key"),
+		"Small RSA Key": pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(small)}),
+		"Public Key": pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: []byte{}}),
+	}
+
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewLocalSigner(data); err == nil {
+				t.Errorf("expected an error")
+			}
+		})
+	}
+}
+
+func TestAuthService_VerifyToken_IssuerAndAudience(t *testing.T) {
+	signer, _ := NewLocalSigner(testRSAKeyPEM(t, nil))
+	serv := New(signer, nil)
+	ctx := context.Background()
+
+	tkn := serv.NewToken(ctx).AddClaim(ClaimTypeAudience, []string{"other", DefaultAudience}).Build()
+	if !serv.VerifyToken(ctx, tkn) {
+		t.Errorf("expected a token with multiple audiences to be valid")
+	}
+
+	tkn = serv.NewToken(ctx).AddClaim(ClaimTypeAudience, "other").Build()
+	if serv.VerifyToken(ctx, tkn) {
+		t.Errorf("expected a token for another audience to be invalid")
+	}
+
+	tkn = serv.NewToken(ctx).AddClaim(ClaimTypeIssuer, "other").Build()
+	if serv.VerifyToken(ctx, tkn) {
+		t.Errorf("expected a token from another issuer to be invalid")
+	}
+}
+
+func TestAuthService_KeySet(t *testing.T) {
+	data := append(testECDSAKeyPEM(t, elliptic.P256()), testRSAKeyPEM(t, map[string]string{"kid": "previous"})...)
+	signer, _ := NewLocalSigner(data)
+
+	set, err := New(signer, nil).KeySet(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(set.Keys) != 2 {
+		t.Fatalf("expected 2 keys but got %d", len(set.Keys))
+	}
+
+	ec, rk := set.Keys[0], set.Keys[1]
+	if ec.KeyType != "EC" || ec.Curve != "P-256" || ec.Algorithm != "ES256" || ec.Use != "sig" || len(ec.X) != 43 || len(ec.Y) != 43 {
+		t.Errorf("unexpected EC key: %+v", ec)
+	}
+
+	// keys without a kid header are identified by their thumbprint.
+	if ec.KeyID != ec.Thumbprint() {
+		t.Errorf("expected kid '%s' but got '%s'", ec.Thumbprint(), ec.KeyID)
+	}
+
+	if rk.KeyType != "RSA" || rk.KeyID != "previous" || rk.Algorithm != "RS512" || rk.E != "AQAB" || rk.N == "" {
+		t.Errorf("unexpected RSA key: %+v", rk)
+	}
+}
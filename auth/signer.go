@@ -0,0 +1,195 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/kms"
+
+	"github.com/reecerussell/distro-blog/libraries/contextkey"
+)
+
+// kmsAlgorithms maps the supported JWS algorithms to their KMS signing
+// algorithm and hash function.
+var kmsAlgorithms = map[string]struct {
+	name string
+	hash crypto.Hash
+}{
+	"RS512": {name: "RSASSA_PKCS1_V1_5_SHA_512", hash: crypto.SHA512},
+	"PS256": {name: "RSASSA_PSS_SHA_256", hash: crypto.SHA256},
+}
+
+// Signer errors.
+var (
+	ErrKeyIDNotSet = errors.New("key id value is not set")
+	ErrUnknownKey = errors.New("unknown signing key")
+	ErrInvalidSignature = errors.New("invalid signature")
+)
+
+// Environment variables used to configure a Signer.
+const (
+	EnvPrivateKey = "JWT_PRIVATE_KEY"
+	EnvPrivateKeyFile = "JWT_PRIVATE_KEY_FILE"
+	EnvAlgorithm = "JWT_ALGORITHM"
+)
+
+// Signer is used to sign and verify tokens, using keys identified by an id,
+// which is written to the "kid" header of each token.
+type Signer interface {
+	// KeyID returns the id of the key used to sign new tokens.
+	KeyID(ctx context.Context) (string, error)
+
+	// KeyIDs returns the ids of all of the keys tokens can be verified with.
+	KeyIDs(ctx context.Context) ([]string, error)
+
+	// Algorithm returns the JWS algorithm of the key, such as "RS512".
+	Algorithm(ctx context.Context, keyID string) (string, error)
+
+	// Sign returns a signature of the message, using the key.
+	Sign(ctx context.Context, keyID string, message []byte) ([]byte, error)
+
+	// Verify returns ErrInvalidSignature if the signature of the message
+	// wasn't made using the key, or ErrUnknownKey if the key isn't trusted.
+	Verify(ctx context.Context, keyID string, message, signature []byte) error
+
+	// PublicKey returns the public half of the key.
+	PublicKey(ctx context.Context, keyID string) (crypto.PublicKey, error)
+}
+
+// NewSignerFromEnv returns a LocalSigner, if a private key has been configured using
+// either the JWT_PRIVATE_KEY or JWT_PRIVATE_KEY_FILE environment variables, allowing
+// tokens to be issued without AWS. Otherwise, a Signer using AWS KMS is returned, with
+// the algorithm set by JWT_ALGORITHM.
+func NewSignerFromEnv() (Signer, error) {
+	if v := os.Getenv(EnvPrivateKey); v != "" {
+		return NewLocalSigner([]byte(v))
+	}
+
+	if name := os.Getenv(EnvPrivateKeyFile); name != "" {
+		data, err := ioutil.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+
+		return NewLocalSigner(data)
+	}
+
+	return NewKMSSigner(os.Getenv(EnvAlgorithm))
+}
+
+// kmsSigner is an implementation of Signer, which uses an RSA key stored in
+// AWS KMS. The id of the key is read from the context's "JWT_KEY_ID" value.
+type kmsSigner struct {
+	svc *kms.KMS
+	alg string
+}
+
+// NewKMSSigner returns a new Signer which uses AWS KMS, signing tokens with
+// the given algorithm, which can either be "RS512", the default, or "PS256".
+func NewKMSSigner(alg string) (Signer, error) {
+	if alg == "" {
+		alg = "RS512"
+	}
+
+	if _, ok := kmsAlgorithms[alg]; !ok {
+		return nil, fmt.Errorf("unsupported algorithm '%s'", alg)
+	}
+
+	sess, _ := session.NewSession()
+
+	return &kmsSigner{
+		svc: kms.New(sess),
+		alg: alg,
+	}, nil
+}
+
+func (s *kmsSigner) KeyID(ctx context.Context) (string, error) {
+	keyIDValue := ctx.Value(contextkey.ContextKey("JWT_KEY_ID"))
+	if keyIDValue == nil {
+		return "", ErrKeyIDNotSet
+	}
+
+	return keyIDValue.(string), nil
+}
+
+func (s *kmsSigner) KeyIDs(ctx context.Context) ([]string, error) {
+	keyID, err := s.KeyID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return []string{keyID}, nil
+}
+
+func (s *kmsSigner) Algorithm(ctx context.Context, keyID string) (string, error) {
+	return s.alg, nil
+}
+
+func (s *kmsSigner) Sign(ctx context.Context, keyID string, message []byte) ([]byte, error) {
+	alg := kmsAlgorithms[s.alg]
+	digest := alg.hash.New()
+	digest.Write(message)
+
+	res, err := s.svc.Sign(&kms.SignInput{
+		KeyId: aws.String(keyID),
+		SigningAlgorithm: aws.String(alg.name),
+		MessageType: aws.String("DIGEST"),
+		Message: digest.Sum(nil),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Signature, nil
+}
+
+func (s *kmsSigner) Verify(ctx context.Context, keyID string, message, signature []byte) error {
+	// only the configured key is trusted, as the account may have other keys.
+	trusted, err := s.KeyID(ctx)
+	if err != nil {
+		return err
+	}
+
+	if keyID != trusted {
+		return ErrUnknownKey
+	}
+
+	alg := kmsAlgorithms[s.alg]
+	digest := alg.hash.New()
+	digest.Write(message)
+
+	res, err := s.svc.Verify(&kms.VerifyInput{
+		Signature: signature,
+		SigningAlgorithm: aws.String(alg.name),
+		Message: digest.Sum(nil),
+		MessageType: aws.String("DIGEST"),
+		KeyId: aws.String(keyID),
+	})
+	if err != nil {
+		return err
+	}
+
+	if !*res.SignatureValid {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+func (s *kmsSigner) PublicKey(ctx context.Context, keyID string) (crypto.PublicKey, error) {
+	res, err := s.svc.GetPublicKey(&kms.GetPublicKeyInput{
+		KeyId: aws.String(keyID),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return x509.ParsePKIXPublicKey(res.PublicKey)
+}
@@ -0,0 +1,18 @@
+package datamodel
+
+import "database/sql"
+
+// MFA is a data model used to read and write a user's two-factor authentication from a data source.
+type MFA struct {
+	UserID string
+	Secret string
+	ConfirmedAt sql.NullTime
+	LastUsedStep int64
+	RecoveryCodes []*MFARecoveryCode
+}
+
+// MFARecoveryCode is a data model for the hash of one of a user's recovery codes.
+type MFARecoveryCode struct {
+	CodeHash string
+	UsedAt sql.NullTime
+}
@@ -10,9 +10,22 @@ type Page struct {
 	Description string
 	ImageID sql.NullString
 	Content sql.NullString
+	ContentFormat string
+	ContentSource sql.NullString
+	Outline sql.NullString
+	WordCount int
+	ReadingTime int
+	Excerpt sql.NullString
 	IsBlog bool
 	IsActive bool
 	URL string
+	ParentID sql.NullString
+	Path string
+	PublishAt sql.NullTime
+	UnpublishAt sql.NullTime
+	PublishedAt sql.NullTime
+	DeletedAt sql.NullTime
 
 	Seo *SEO
+	Draft *PageDraft
 }
\ No newline at end of file
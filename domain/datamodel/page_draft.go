@@ -0,0 +1,22 @@
+package datamodel
+
+import (
+	"database/sql"
+	"time"
+)
+
+// PageDraft is a data model used to read and write a page's
+// working draft from a data source.
+type PageDraft struct {
+	PageID string
+	UserID sql.NullString
+	UserFullname sql.NullString
+	Date time.Time
+	Title string
+	Description string
+	Content sql.NullString
+	ContentFormat string
+	URL string
+
+	Seo *SEO
+}
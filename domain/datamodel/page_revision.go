@@ -0,0 +1,25 @@
+package datamodel
+
+import (
+	"database/sql"
+	"time"
+)
+
+// PageRevision is a data model used to read and write snapshots
+// of a page's content, from a data source.
+type PageRevision struct {
+	ID string
+	PageID string
+	Number int
+	UserID sql.NullString
+	UserFullname sql.NullString
+	Date time.Time
+	Title string
+	Description string
+	Content sql.NullString
+	ContentFormat string
+	URL string
+	IsDraft bool
+
+	Seo *SEO
+}
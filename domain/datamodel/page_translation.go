@@ -0,0 +1,23 @@
+package datamodel
+
+import "database/sql"
+
+// PageTranslation is a data model used to read and write
+// a page's translations from a data source.
+type PageTranslation struct {
+	PageID string
+	Locale string
+	Title string
+	Description string
+	Content sql.NullString
+	ContentFormat string
+	ContentSource sql.NullString
+	Outline sql.NullString
+	WordCount int
+	ReadingTime int
+	Excerpt sql.NullString
+	URL sql.NullString
+	Path string
+	SeoTitle sql.NullString
+	SeoDescription sql.NullString
+}
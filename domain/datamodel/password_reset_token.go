@@ -0,0 +1,15 @@
+package datamodel
+
+import (
+	"database/sql"
+	"time"
+)
+
+// PasswordResetToken is a data model used to read and write password reset tokens from a data source.
+type PasswordResetToken struct {
+	ID string
+	UserID string
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt sql.NullTime
+}
@@ -0,0 +1,17 @@
+package datamodel
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Redirect is a data model used to read and write
+// redirects from a data source.
+type Redirect struct {
+	ID string
+	SourcePath string
+	TargetPath string
+	StatusCode int
+	PageID sql.NullString
+	CreatedAt time.Time
+}
@@ -0,0 +1,18 @@
+package datamodel
+
+import (
+	"database/sql"
+	"time"
+)
+
+// RefreshToken is a data model used to read and write refresh tokens from a data source.
+type RefreshToken struct {
+	ID string
+	UserID string
+	FamilyID string
+	TokenHash string
+	MFAVerified bool
+	ExpiresAt time.Time
+	UsedAt sql.NullTime
+	RevokedAt sql.NullTime
+}
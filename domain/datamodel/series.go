@@ -0,0 +1,21 @@
+package datamodel
+
+import "database/sql"
+
+// Series is a data model used to read and write series of blogs from a data source.
+type Series struct {
+	ID string
+	Title string
+	Description sql.NullString
+
+	Pages []*SeriesPage
+}
+
+// SeriesPage is a data model for a blog in a series. The title, path
+// and active state are read from the page, and are never written.
+type SeriesPage struct {
+	PageID string
+	Title string
+	Path string
+	IsActive bool
+}
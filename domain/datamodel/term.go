@@ -0,0 +1,10 @@
+package datamodel
+
+// Term is a data model used to read and write tags
+// and categories from a data source.
+type Term struct {
+	ID string
+	Type string
+	Name string
+	Slug string
+}
@@ -1,5 +1,7 @@
 package datamodel
 
+import "database/sql"
+
 // User is a datamodel for the User domain.
 type User struct {
 	ID              string
@@ -8,4 +10,8 @@ type User struct {
 	Email           string
 	NormalizedEmail string
 	PasswordHash    string
+	DisplayName     sql.NullString
+	Bio             sql.NullString
+	AvatarImageID   sql.NullString
+	SocialLinks     sql.NullString
 }
@@ -0,0 +1,34 @@
+package dto
+
+// Author is a data-transfer object used to hold the public
+// profile of a user who writes pages and blogs.
+type Author struct {
+	ID string `json:"id"`
+	Name string `json:"name"`
+	Bio *string `json:"bio"`
+	AvatarImageID *string `json:"avatarImageId"`
+	SocialLinks []*SocialLink `json:"socialLinks"`
+}
+
+// SocialLink is a link to one of a user's social media profiles
+// or websites, such as "Twitter" or "GitHub".
+type SocialLink struct {
+	Name string `json:"name"`
+	URL string `json:"url"`
+}
+
+// UpdateUserProfile is a data-transfer object used to update the
+// public profile of a user, which is shown on their posts.
+type UpdateUserProfile struct {
+	ID string `json:"id"`
+	DisplayName *string `json:"displayName"`
+	Bio *string `json:"bio"`
+	AvatarImageID *string `json:"avatarImageId"`
+	SocialLinks []*SocialLink `json:"socialLinks"`
+}
+
+// PageAuthors is a data-transfer object used to set the authors
+// of a page, in the order they're shown in its byline.
+type PageAuthors struct {
+	UserIDs []string `json:"userIds"`
+}
@@ -0,0 +1,25 @@
+package dto
+
+import "time"
+
+// BlogListItem is a data transfer object used to list
+// published blogs on the public facing site.
+type BlogListItem struct {
+	ID string `json:"id"`
+	Title string `json:"title"`
+	Description string `json:"description"`
+	URL string `json:"url"`
+	ImageID *string `json:"imageId"`
+	Excerpt string `json:"excerpt"`
+	PublishedAt time.Time `json:"publishedAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	Author *BlogAuthor `json:"author"`
+	Authors []*BlogAuthor `json:"authors"`
+}
+
+// BlogAuthor is a data transfer object used to hold
+// the public details of the author of a blog.
+type BlogAuthor struct {
+	ID string `json:"id"`
+	Name string `json:"name"`
+}
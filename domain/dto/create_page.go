@@ -6,6 +6,8 @@ type CreatePage struct {
 	Title string `json:"title"`
 	Description string `json:"description"`
 	Content *string `json:"content"`
+	ContentFormat string `json:"contentFormat"`
 	URL string `json:"url"`
+	ParentID *string `json:"parentId"`
 	SEO *SEO `json:"seo"`
 }
@@ -0,0 +1,11 @@
+package dto
+
+import "time"
+
+// Document is a data transfer object used to hold a rendered,
+// non-JSON, document, such as a feed or sitemap.
+type Document struct {
+	ContentType string `json:"contentType"`
+	Body []byte `json:"body"`
+	LastModified time.Time `json:"lastModified"`
+}
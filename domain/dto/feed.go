@@ -0,0 +1,6 @@
+package dto
+
+// FeedQuery is a data transfer object used to request a blog feed.
+type FeedQuery struct {
+	Format string `query:"format"`
+}
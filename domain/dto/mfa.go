@@ -0,0 +1,34 @@
+package dto
+
+// MFAChallenge is returned instead of an access token when a user, who has two-factor
+// authentication enabled, signs in with their password. The MFA token must be sent,
+// along with a code from their authenticator app, to get an access token.
+type MFAChallenge struct {
+	MFARequired bool `json:"mfaRequired"`
+	MFAToken string `json:"mfaToken"`
+	Expires int64 `json:"expires"`
+}
+
+// VerifyMFA is a data-transfer object used to complete the second step of signing in.
+// The code can either be from the user's authenticator app, or one of their recovery codes.
+type VerifyMFA struct {
+	MFAToken string `json:"mfaToken"`
+	Code string `json:"code"`
+}
+
+// MFAEnrollment contains the secret of a new authenticator, and an otpauth:// URI
+// for it, which can be shown as a QR code.
+type MFAEnrollment struct {
+	Secret string `json:"secret"`
+	URI string `json:"uri"`
+}
+
+// MFACode is a data-transfer object containing a code from the user's authenticator app.
+type MFACode struct {
+	Code string `json:"code"`
+}
+
+// MFARecoveryCodes contains a user's new recovery codes, which are only shown once.
+type MFARecoveryCodes struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
@@ -0,0 +1,7 @@
+package dto
+
+// MovePage is a data-transfer object used to move a page beneath
+// another page. A nil ParentID moves the page to the top-level.
+type MovePage struct {
+	ParentID *string `json:"parentId"`
+}
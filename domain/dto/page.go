@@ -1,16 +1,43 @@
 package dto
 
+import "time"
+
 // Page is a data transfer object for the page domain.
 type Page struct {
 	ID string `json:"id"`
 	Title string `json:"title"`
 	Description string `json:"description"`
+
+	// Content is the page's HTML, which is rendered from ContentSource
+	// when the ContentFormat is Markdown.
 	Content *string `json:"content"`
+	ContentFormat string `json:"contentFormat"`
+	ContentSource *string `json:"contentSource"`
+	Outline []*PageHeading `json:"outline"`
+	WordCount int `json:"wordCount"`
+	ReadingTime int `json:"readingTime"`
+	Excerpt *string `json:"excerpt"`
+
 	IsBlog bool `json:"isBlog"`
 	IsActive bool `json:"isActive"`
 	ImageID *string `json:"imageId"`
 	URL string `json:"url"`
+	ParentID *string `json:"parentId"`
+	Path string `json:"path"`
+	PublishAt *time.Time `json:"publishAt"`
+	UnpublishAt *time.Time `json:"unpublishAt"`
+	PublishedAt *time.Time `json:"publishedAt"`
+	DeletedAt *time.Time `json:"deletedAt"`
 
 	Audit []*PageAudit `json:"audit,omitempty"`
 	SEO *SEO `json:"seo,omitempty"`
+	Draft *PageDraft `json:"draft,omitempty"`
+}
+
+// PageHeading is a heading in a page's content, which is
+// used to build a table of contents, linking to its id.
+type PageHeading struct {
+	Level int `json:"level"`
+	ID string `json:"id"`
+	Text string `json:"text"`
 }
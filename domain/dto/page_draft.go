@@ -0,0 +1,17 @@
+package dto
+
+import "time"
+
+// PageDraft is a data transfer object for a page's working draft,
+// which contains changes that have not yet been published.
+type PageDraft struct {
+	UserID *string `json:"userId"`
+	UserFullname *string `json:"userFullname"`
+	Date time.Time `json:"date"`
+	Title string `json:"title"`
+	Description string `json:"description"`
+	Content *string `json:"content"`
+	ContentFormat string `json:"contentFormat"`
+	URL string `json:"url"`
+	SEO *SEO `json:"seo,omitempty"`
+}
@@ -1,9 +1,15 @@
 package dto
 
+import "time"
+
 // PageListItem is a data transfer object used for retrieving
 // page records for a list, from a data source.
 type PageListItem struct {
 	ID string `json:"id"`
 	Title string `json:"title"`
 	Description string `json:"description"`
+	URL string `json:"url"`
+	IsActive bool `json:"isActive"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
 }
@@ -0,0 +1,24 @@
+package dto
+
+import "time"
+
+// Values used to sort page lists by.
+const (
+	PageSortTitle = "title"
+	PageSortCreated = "created"
+	PageSortUpdated = "updated"
+)
+
+// PageListQuery is a data-transfer object used to read the filter,
+// sort and paging options for page and blog lists from a query string.
+type PageListQuery struct {
+	IsActive *bool `query:"active"`
+	Search string `query:"search"`
+	From *time.Time `query:"from"`
+	To *time.Time `query:"to"`
+	SortBy string `query:"sort"`
+	SortDesc bool `query:"desc"`
+	Limit int `query:"limit"`
+	Offset int `query:"offset"`
+	Cursor string `query:"cursor"`
+}
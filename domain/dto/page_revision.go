@@ -0,0 +1,38 @@
+package dto
+
+import "time"
+
+// PageRevision is a data transfer object for a snapshot of a page's content.
+type PageRevision struct {
+	ID string `json:"id"`
+	PageID string `json:"pageId"`
+	Number int `json:"number"`
+	UserID *string `json:"userId"`
+	UserFullname *string `json:"userFullname"`
+	Date time.Time `json:"date"`
+	Title string `json:"title"`
+	Description string `json:"description"`
+	Content *string `json:"content,omitempty"`
+	ContentFormat string `json:"contentFormat"`
+	URL string `json:"url"`
+	IsDraft bool `json:"isDraft"`
+	SEO *SEO `json:"seo,omitempty"`
+}
+
+// PageRevisionDiff is used to return the differences
+// between two revisions of a page.
+type PageRevisionDiff struct {
+	From *PageRevision `json:"from"`
+	To *PageRevision `json:"to"`
+	Fields []*PageRevisionFieldDiff `json:"fields"`
+}
+
+// PageRevisionFieldDiff contains the before and after values of a single
+// field. HTML is only populated when a HTML diff has been requested.
+type PageRevisionFieldDiff struct {
+	Field string `json:"field"`
+	Changed bool `json:"changed"`
+	Before interface{} `json:"before"`
+	After interface{} `json:"after"`
+	HTML *string `json:"html,omitempty"`
+}
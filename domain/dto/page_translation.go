@@ -0,0 +1,33 @@
+package dto
+
+// PageTranslation is a data-transfer object for a page's content in another
+// locale. Any content which isn't translated falls back to the page's own.
+type PageTranslation struct {
+	PageID string `json:"pageId"`
+	Locale string `json:"locale"`
+	Title string `json:"title"`
+	Description string `json:"description"`
+	Content *string `json:"content"`
+	ContentFormat string `json:"contentFormat"`
+	ContentSource *string `json:"contentSource"`
+	Outline []*PageHeading `json:"outline"`
+	WordCount int `json:"wordCount"`
+	ReadingTime int `json:"readingTime"`
+	Excerpt *string `json:"excerpt"`
+	URL *string `json:"url"`
+	Path string `json:"path"`
+	SeoTitle *string `json:"seoTitle"`
+	SeoDescription *string `json:"seoDescription"`
+}
+
+// SetPageTranslation is a data-transfer object used to create or replace
+// a page's translation. The url is optional, and defaults to the page's url.
+type SetPageTranslation struct {
+	Title string `json:"title"`
+	Description string `json:"description"`
+	Content *string `json:"content"`
+	ContentFormat string `json:"contentFormat"`
+	URL *string `json:"url"`
+	SeoTitle *string `json:"seoTitle"`
+	SeoDescription *string `json:"seoDescription"`
+}
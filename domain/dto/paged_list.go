@@ -0,0 +1,11 @@
+package dto
+
+// PagedList is a data transfer object used to return a single page
+// of a list, along with the information needed to get the next page.
+type PagedList struct {
+	Items interface{} `json:"items"`
+	Total int64 `json:"total"`
+	Limit int `json:"limit"`
+	Offset int `json:"offset"`
+	NextCursor *string `json:"nextCursor"`
+}
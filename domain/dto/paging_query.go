@@ -0,0 +1,9 @@
+package dto
+
+// PagingQuery is a data-transfer object used to read paging
+// options for a list from a query string.
+type PagingQuery struct {
+	Limit int `query:"limit"`
+	Offset int `query:"offset"`
+	Cursor string `query:"cursor"`
+}
@@ -0,0 +1,14 @@
+package dto
+
+// RequestPasswordReset is a data-transfer object used by users who have
+// forgotten their password, to be emailed a password reset link.
+type RequestPasswordReset struct {
+	Email string `json:"email"`
+}
+
+// ResetPassword is a data-transfer object used to set a new password,
+// using the token from a password reset email.
+type ResetPassword struct {
+	Token string `json:"token"`
+	NewPassword string `json:"newPassword"`
+}
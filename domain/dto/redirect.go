@@ -0,0 +1,30 @@
+package dto
+
+import "time"
+
+// Redirect is a data transfer object used to hold a redirect from
+// one path on the public site to another.
+type Redirect struct {
+	ID string `json:"id"`
+	Source string `json:"source"`
+	Target string `json:"target"`
+	StatusCode int `json:"statusCode"`
+	PageID *string `json:"pageId"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// CreateRedirect is a data-transfer object used to read data
+// from request bodies, then create redirects.
+type CreateRedirect struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+	StatusCode int `json:"statusCode"`
+}
+
+// UpdateRedirect is a data-transfer object used to change the
+// target and status code of an existing redirect.
+type UpdateRedirect struct {
+	ID string `json:"id"`
+	Target string `json:"target"`
+	StatusCode int `json:"statusCode"`
+}
@@ -0,0 +1,13 @@
+package dto
+
+// RefreshToken is a data-transfer object used to exchange a
+// refresh token for a new access token and refresh token.
+type RefreshToken struct {
+	RefreshToken string `json:"refreshToken"`
+}
+
+// RevokeToken is a data-transfer object used to revoke either an access
+// token, or a refresh token, along with the tokens which replaced it.
+type RevokeToken struct {
+	Token string `json:"token"`
+}
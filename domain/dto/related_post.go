@@ -0,0 +1,25 @@
+package dto
+
+// RelatedPost is a data-transfer object used to store how related a blog is
+// to another. Position is the related blog's place in the list, from 0.
+type RelatedPost struct {
+	PageID string
+	RelatedPageID string
+	Score float64
+	Position int
+}
+
+// RelatedDocument is a data-transfer object used to hold the text of
+// an active blog, which is compared with others to find related posts.
+type RelatedDocument struct {
+	ID string
+	Title string
+	Description string
+	Content *string
+}
+
+// RelatedQuery is a data-transfer object used to read the
+// options for a list of related posts from a query string.
+type RelatedQuery struct {
+	Limit int `query:"limit"`
+}
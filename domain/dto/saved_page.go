@@ -0,0 +1,9 @@
+package dto
+
+// SavedPage is a data-transfer object returned when a page is created or updated.
+// StrippedContent lists the elements and attributes removed from the page's content,
+// so authors can be told what wasn't allowed.
+type SavedPage struct {
+	ID string `json:"id"`
+	StrippedContent []string `json:"strippedContent,omitempty"`
+}
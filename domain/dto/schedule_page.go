@@ -0,0 +1,10 @@
+package dto
+
+import "time"
+
+// SchedulePage is a data-transfer object used to schedule a page to be
+// activated or deactivated at a specific time. A nil date clears it.
+type SchedulePage struct {
+	PublishAt *time.Time `json:"publishAt"`
+	UnpublishAt *time.Time `json:"unpublishAt"`
+}
@@ -0,0 +1,36 @@
+package dto
+
+// SearchQuery is a data-transfer object used to read a search
+// query and its paging options from a query string.
+type SearchQuery struct {
+	Query string `query:"q"`
+	Limit int `query:"limit"`
+	Offset int `query:"offset"`
+	Cursor string `query:"cursor"`
+}
+
+// SearchHit is a page matched by a search index, ordered by its score.
+type SearchHit struct {
+	ID string
+	Title string
+	Description string
+	Path string
+	Content *string
+	IsBlog bool
+	IsActive bool
+	Score float64
+}
+
+// SearchResult is a data transfer object for a page matched by a search. The
+// highlight fields contain HTML, with the matched terms wrapped in <mark> elements.
+type SearchResult struct {
+	ID string `json:"id"`
+	Title string `json:"title"`
+	TitleHighlight string `json:"titleHighlight"`
+	Description string `json:"description"`
+	Snippet string `json:"snippet"`
+	Path string `json:"path"`
+	IsBlog bool `json:"isBlog"`
+	IsActive bool `json:"isActive"`
+	Score float64 `json:"score"`
+}
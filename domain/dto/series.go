@@ -0,0 +1,52 @@
+package dto
+
+// Series is a data-transfer object for the series domain, which holds
+// an ordered sequence of blogs, such as the parts of a tutorial.
+type Series struct {
+	ID string `json:"id"`
+	Title string `json:"title"`
+	Description *string `json:"description"`
+	Pages []*SeriesPage `json:"pages,omitempty"`
+}
+
+// SeriesPage is a data-transfer object for a blog in a series.
+// Position is the blog's place in the series, starting from 1.
+type SeriesPage struct {
+	ID string `json:"id"`
+	Title string `json:"title"`
+	Path string `json:"path"`
+	IsActive bool `json:"isActive"`
+	Position int `json:"position"`
+}
+
+// SeriesListItem is a data-transfer object used to list each series.
+type SeriesListItem struct {
+	ID string `json:"id"`
+	Title string `json:"title"`
+	Description *string `json:"description"`
+	PageCount int `json:"pageCount"`
+}
+
+// CreateSeries is a data-transfer object used to create a series.
+type CreateSeries struct {
+	Title string `json:"title"`
+	Description *string `json:"description"`
+}
+
+// UpdateSeries is a data-transfer object used to update the details of a series.
+type UpdateSeries struct {
+	ID string `json:"id"`
+	Title string `json:"title"`
+	Description *string `json:"description"`
+}
+
+// AddSeriesPage is a data-transfer object used to add a blog to the end of a series.
+type AddSeriesPage struct {
+	PageID string `json:"pageId"`
+}
+
+// ReorderSeries is a data-transfer object used to reorder the blogs in a
+// series. It must contain the id of every blog in the series, in order.
+type ReorderSeries struct {
+	PageIDs []string `json:"pageIds"`
+}
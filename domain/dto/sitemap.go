@@ -0,0 +1,16 @@
+package dto
+
+import "time"
+
+// SitemapQuery is a data transfer object used to request a sitemap. Page
+// is only used when the sitemap has been split, and is one-based.
+type SitemapQuery struct {
+	Page int `query:"page"`
+}
+
+// SitemapEntry is a data transfer object used to hold
+// a page which should be included in the sitemap.
+type SitemapEntry struct {
+	Path string `json:"path"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
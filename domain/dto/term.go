@@ -0,0 +1,36 @@
+package dto
+
+// Term is a data transfer object for the taxonomy domain, which
+// is used to hold a tag or category.
+type Term struct {
+	ID string `json:"id"`
+	Type string `json:"type"`
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+	PageCount int `json:"pageCount"`
+}
+
+// CreateTerm is a data-transfer object used to read data
+// from request bodies, then create tags and categories.
+type CreateTerm struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+}
+
+// RenameTerm is a data-transfer object used to rename a tag or category.
+type RenameTerm struct {
+	ID string `json:"id"`
+	Name string `json:"name"`
+}
+
+// MergeTerms is a data-transfer object used to merge a tag or category
+// into another, moving all of its pages to the target term.
+type MergeTerms struct {
+	TargetID string `json:"targetId"`
+}
+
+// PageTerms is a data-transfer object used to set the
+// tags and categories a page is assigned to.
+type PageTerms struct {
+	TermIDs []string `json:"termIds"`
+}
@@ -0,0 +1,13 @@
+package dto
+
+import "time"
+
+// TrashedPage is a data transfer object used to list
+// pages and blogs which have been moved to the trash.
+type TrashedPage struct {
+	ID string `json:"id"`
+	Title string `json:"title"`
+	Path string `json:"path"`
+	IsBlog bool `json:"isBlog"`
+	DeletedAt time.Time `json:"deletedAt"`
+}
@@ -1,5 +1,7 @@
 package dto
 
+import "time"
+
 // UpdatePage is a data-transfer object used to transfer and
 // hold data required to update a page record.
 type UpdatePage struct {
@@ -7,6 +9,13 @@ type UpdatePage struct {
 	Title string `json:"title"`
 	Description string `json:"description"`
 	Content *string `json:"content"`
+	ContentFormat string `json:"contentFormat"`
 	URL string `json:"url"`
 	SEO *SEO `json:"seo"`
+
+	// PublishAt and UnpublishAt are optional dates used to schedule the page
+	// to be activated or deactivated at a specific time. A nil date leaves the
+	// page's schedule unchanged, dates can only be cleared using SchedulePage.
+	PublishAt *time.Time `json:"publishAt"`
+	UnpublishAt *time.Time `json:"unpublishAt"`
 }
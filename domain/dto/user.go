@@ -8,6 +8,10 @@ type User struct {
 	Lastname string `json:"lastname"`
 	Email string `json:"email"`
 	NormalizedEmail string `json:"normalizedEmail"`
+	DisplayName *string `json:"displayName"`
+	Bio *string `json:"bio"`
+	AvatarImageID *string `json:"avatarImageId"`
+	SocialLinks []*SocialLink `json:"socialLinks"`
 
 	Audit []*UserAudit `json:"audit,omitempty"`
 }
@@ -0,0 +1,9 @@
+package event
+
+// AddPageAuthor is a domain event raised to add an author to a page,
+// such as when the user who created the page is set as its author.
+type AddPageAuthor struct {
+	PageID string
+	UserID string
+	Position int
+}
@@ -0,0 +1,9 @@
+package event
+
+import "github.com/reecerussell/distro-blog/domain/datamodel"
+
+// AddPageRedirect is a domain event raised when a published page's url
+// changes, to redirect requests for its old url to the new one.
+type AddPageRedirect struct {
+	Redirect *datamodel.Redirect
+}
@@ -0,0 +1,11 @@
+package event
+
+import (
+	"github.com/reecerussell/distro-blog/domain/datamodel"
+)
+
+// AddPageRevision is a domain event object used to store
+// a snapshot of a page's content, each time it is saved.
+type AddPageRevision struct {
+	Revision *datamodel.PageRevision
+}
@@ -0,0 +1,7 @@
+package event
+
+// DeletePageDraft is a domain event raised when a page's
+// working draft is published, or is no longer needed.
+type DeletePageDraft struct {
+	PageID string
+}
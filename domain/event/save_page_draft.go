@@ -0,0 +1,8 @@
+package event
+
+import "github.com/reecerussell/distro-blog/domain/datamodel"
+
+// SavePageDraft is a domain event raised when a page's working draft is saved.
+type SavePageDraft struct {
+	Draft *datamodel.PageDraft
+}
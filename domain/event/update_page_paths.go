@@ -0,0 +1,7 @@
+package event
+
+// UpdatePagePaths is a domain event raised when a page's path changes,
+// to rebuild the paths of each of its descendants.
+type UpdatePagePaths struct {
+	PageID string
+}
@@ -0,0 +1,25 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/reecerussell/distro-blog/domain/event"
+	"github.com/reecerussell/distro-blog/libraries/database"
+	"github.com/reecerussell/distro-blog/libraries/result"
+)
+
+// AddPageAuthor is a domain event handler used to add an author to a page.
+type AddPageAuthor struct {}
+
+// Invoke invokes the handler for event.AddPageAuthor domain events.
+func (*AddPageAuthor) Invoke(ctx context.Context, tx *database.Transaction, e interface{}) result.Result {
+	const query string = "CALL `add_page_author`(?,?,?);"
+	evt := e.(*event.AddPageAuthor)
+
+	err := tx.Execute(ctx, query, evt.PageID, evt.UserID, evt.Position)
+	if err != nil {
+		return result.Failure(err)
+	}
+
+	return result.Ok()
+}
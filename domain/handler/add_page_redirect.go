@@ -0,0 +1,32 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/reecerussell/distro-blog/domain/event"
+	"github.com/reecerussell/distro-blog/libraries/database"
+	"github.com/reecerussell/distro-blog/libraries/result"
+)
+
+// AddPageRedirect is a domain event handler used to redirect a page's old url to its new one.
+type AddPageRedirect struct {}
+
+// Invoke invokes the handler for event.AddPageRedirect domain events.
+func (*AddPageRedirect) Invoke(ctx context.Context, tx *database.Transaction, e interface{}) result.Result {
+	const query string = "CALL `create_redirect`(?,?,?,?,?);"
+	dm := e.(*event.AddPageRedirect).Redirect
+	args := []interface{}{
+		dm.ID,
+		dm.SourcePath,
+		dm.TargetPath,
+		dm.StatusCode,
+		dm.PageID,
+	}
+
+	err := tx.Execute(ctx, query, args...)
+	if err != nil {
+		return result.Failure(err)
+	}
+
+	return result.Ok()
+}
@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/reecerussell/distro-blog/domain/event"
+	"github.com/reecerussell/distro-blog/libraries/database"
+	"github.com/reecerussell/distro-blog/libraries/result"
+)
+
+// AddPageRevision is a domain event handler used to store page revisions.
+type AddPageRevision struct {}
+
+// Invoke invokes the handler for event.AddPageRevision domain events and
+// executes a stored procedure to add a revision record.
+func (*AddPageRevision) Invoke(ctx context.Context, tx *database.Transaction, e interface{}) result.Result {
+	const query string = "CALL `add_page_revision`(?,?,?,?,?,?,?,?,?,?,?,?,?,?);"
+	evt := e.(*event.AddPageRevision)
+	r := evt.Revision
+
+	// a page may not have any SEO data, in which case the
+	// revision's SEO columns are left as NULL.
+	var seoTitle, seoDescription, seoIndex, seoFollow interface{}
+	if r.Seo != nil {
+		seoTitle = r.Seo.Title
+		seoDescription = r.Seo.Description
+		seoIndex = r.Seo.Index
+		seoFollow = r.Seo.Follow
+	}
+
+	args := []interface{}{
+		r.ID,
+		r.PageID,
+		r.UserID,
+		r.Date,
+		r.Title,
+		r.Description,
+		r.Content,
+		r.ContentFormat,
+		r.URL,
+		r.IsDraft,
+		seoTitle,
+		seoDescription,
+		seoIndex,
+		seoFollow,
+	}
+
+	err := tx.Execute(ctx, query, args...)
+	if err != nil {
+		return result.Failure(err)
+	}
+
+	return result.Ok()
+}
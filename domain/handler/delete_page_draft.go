@@ -0,0 +1,25 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/reecerussell/distro-blog/domain/event"
+	"github.com/reecerussell/distro-blog/libraries/database"
+	"github.com/reecerussell/distro-blog/libraries/result"
+)
+
+// DeletePageDraft is a domain event handler used to remove a page's working draft.
+type DeletePageDraft struct {}
+
+// Invoke invokes the handler for event.DeletePageDraft domain events.
+func (*DeletePageDraft) Invoke(ctx context.Context, tx *database.Transaction, e interface{}) result.Result {
+	const query string = "CALL `delete_page_draft`(?);"
+	evt := e.(*event.DeletePageDraft)
+
+	err := tx.Execute(ctx, query, evt.PageID)
+	if err != nil {
+		return result.Failure(err)
+	}
+
+	return result.Ok()
+}
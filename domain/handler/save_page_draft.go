@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/reecerussell/distro-blog/domain/event"
+	"github.com/reecerussell/distro-blog/libraries/database"
+	"github.com/reecerussell/distro-blog/libraries/result"
+)
+
+// SavePageDraft is a domain event handler used to store a page's working draft.
+type SavePageDraft struct {}
+
+// Invoke invokes the handler for event.SavePageDraft domain events and executes
+// a stored procedure to insert or replace the page's draft record.
+func (*SavePageDraft) Invoke(ctx context.Context, tx *database.Transaction, e interface{}) result.Result {
+	const query string = "CALL `save_page_draft`(?,?,?,?,?,?,?,?,?,?,?,?);"
+	evt := e.(*event.SavePageDraft)
+	d := evt.Draft
+
+	var seoTitle, seoDescription, seoIndex, seoFollow interface{}
+	if d.Seo != nil {
+		seoTitle = d.Seo.Title
+		seoDescription = d.Seo.Description
+		seoIndex = d.Seo.Index
+		seoFollow = d.Seo.Follow
+	}
+
+	args := []interface{}{
+		d.PageID,
+		d.UserID,
+		d.Date,
+		d.Title,
+		d.Description,
+		d.Content,
+		d.ContentFormat,
+		d.URL,
+		seoTitle,
+		seoDescription,
+		seoIndex,
+		seoFollow,
+	}
+
+	err := tx.Execute(ctx, query, args...)
+	if err != nil {
+		return result.Failure(err)
+	}
+
+	return result.Ok()
+}
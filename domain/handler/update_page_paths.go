@@ -0,0 +1,25 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/reecerussell/distro-blog/domain/event"
+	"github.com/reecerussell/distro-blog/libraries/database"
+	"github.com/reecerussell/distro-blog/libraries/result"
+)
+
+// UpdatePagePaths is a domain event handler used to rebuild the paths of a page's descendants.
+type UpdatePagePaths struct {}
+
+// Invoke invokes the handler for event.UpdatePagePaths domain events.
+func (*UpdatePagePaths) Invoke(ctx context.Context, tx *database.Transaction, e interface{}) result.Result {
+	const query string = "CALL `update_page_paths`(?);"
+	evt := e.(*event.UpdatePagePaths)
+
+	err := tx.Execute(ctx, query, evt.PageID)
+	if err != nil {
+		return result.Failure(err)
+	}
+
+	return result.Ok()
+}
@@ -23,6 +23,11 @@ func (i *Image) GetID() string {
 	return i.id
 }
 
+// GetAlternativeText returns the image's alternative text, if it has any.
+func (i *Image) GetAlternativeText() *string {
+	return i.alternativeText
+}
+
 func (i *Image) DataModel() *datamodel.Image {
 	dm := &datamodel.Image{
 		ID:              i.id,
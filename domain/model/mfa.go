@@ -0,0 +1,244 @@
+package model
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/reecerussell/distro-blog/domain/datamodel"
+	"github.com/reecerussell/distro-blog/libraries/totp"
+)
+
+// RecoveryCodeCount is the number of recovery codes generated for a user.
+const RecoveryCodeCount = 10
+
+// MFAMaxAttempts is the number of codes which can be entered, without one being
+// valid, before the authenticator is locked for MFALockoutDuration.
+const MFAMaxAttempts = 5
+
+// MFALockoutDuration is how long an authenticator is locked for, once too many
+// invalid codes have been entered.
+const MFALockoutDuration = time.Minute * 15
+
+// Errors returned when managing or using two-factor authentication.
+var (
+	ErrMFAAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+	ErrMFANotEnabled = errors.New("two-factor authentication is not enabled")
+	ErrInvalidMFACode = errors.New("the code is invalid or has already been used")
+	ErrMFALocked = errors.New("too many invalid codes have been entered, please try again later")
+)
+
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// MFA is a user's TOTP two-factor authentication. A new authenticator must be
+// confirmed, using a code from it, before it's used to sign in. Once confirmed,
+// the user is given single-use recovery codes, in case they lose the authenticator.
+//
+// Only hashes of the recovery codes are stored.
+type MFA struct {
+	userID string
+	secret string
+	confirmedAt *time.Time
+	lastUsedStep int64
+	recoveryCodes []*recoveryCode
+}
+
+type recoveryCode struct {
+	hash string
+	usedAt *time.Time
+}
+
+// NewMFA returns a new, unconfirmed, authenticator for the user, with a random secret.
+func NewMFA(userID string) (*MFA, error) {
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	return &MFA{
+		userID: userID,
+		secret: secret,
+	}, nil
+}
+
+// UserID returns the id of the user the authenticator belongs to.
+func (m *MFA) UserID() string {
+	return m.userID
+}
+
+// Secret returns the authenticator's base32 encoded secret.
+func (m *MFA) Secret() string {
+	return m.secret
+}
+
+// URI returns an otpauth:// URI for the authenticator, to be shown as a QR code.
+func (m *MFA) URI(issuer, account string) string {
+	return totp.URI(issuer, account, m.secret)
+}
+
+// IsEnabled determines whether the authenticator has been confirmed, so
+// is required to sign in.
+func (m *MFA) IsEnabled() bool {
+	return m.confirmedAt != nil
+}
+
+// RemainingRecoveryCodes returns the number of recovery codes which haven't been used.
+func (m *MFA) RemainingRecoveryCodes() int {
+	var n int
+	for _, c := range m.recoveryCodes {
+		if c.usedAt == nil {
+			n++
+		}
+	}
+
+	return n
+}
+
+// Confirm enables the authenticator, using a code from it to ensure it has been
+// set up correctly, returning the user's recovery codes.
+func (m *MFA) Confirm(code string, now time.Time) ([]string, error) {
+	if m.IsEnabled() {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	err := m.verifyCode(code, now)
+	if err != nil {
+		return nil, err
+	}
+
+	m.confirmedAt = &now
+
+	return m.RegenerateRecoveryCodes()
+}
+
+// Verify verifies a code from the authenticator, or one of the recovery codes.
+// Each code can only be used once.
+func (m *MFA) Verify(code string, now time.Time) error {
+	if !m.IsEnabled() {
+		return ErrMFANotEnabled
+	}
+
+	code = normalizeCode(code)
+	if len(code) == totp.Digits {
+		return m.verifyCode(code, now)
+	}
+
+	hash := hashRecoveryCode(code)
+	for _, c := range m.recoveryCodes {
+		if c.usedAt == nil && subtle.ConstantTimeCompare([]byte(c.hash), []byte(hash)) == 1 {
+			c.usedAt = &now
+			return nil
+		}
+	}
+
+	return ErrInvalidMFACode
+}
+
+// verifyCode verifies a code from the authenticator, ensuring it hasn't already been used.
+func (m *MFA) verifyCode(code string, now time.Time) error {
+	step, ok := totp.Validate(m.secret, normalizeCode(code), now, m.lastUsedStep)
+	if !ok {
+		return ErrInvalidMFACode
+	}
+
+	m.lastUsedStep = step
+
+	return nil
+}
+
+// RegenerateRecoveryCodes replaces the user's recovery codes with new ones, returning them.
+func (m *MFA) RegenerateRecoveryCodes() ([]string, error) {
+	if !m.IsEnabled() {
+		return nil, ErrMFANotEnabled
+	}
+
+	codes := make([]string, RecoveryCodeCount)
+	hashes := make([]*recoveryCode, RecoveryCodeCount)
+
+	for i := range codes {
+		b := make([]byte, 5)
+		_, err := rand.Read(b)
+		if err != nil {
+			return nil, err
+		}
+
+		c := strings.ToLower(recoveryCodeEncoding.EncodeToString(b))
+		codes[i] = c[:4] + "-" + c[4:]
+		hashes[i] = &recoveryCode{hash: hashRecoveryCode(c)}
+	}
+
+	m.recoveryCodes = hashes
+
+	return codes, nil
+}
+
+// normalizeCode removes spaces and dashes which users may type, or copy, with a code.
+func normalizeCode(code string) string {
+	code = strings.NewReplacer(" ", "", "-", "").Replace(code)
+	return strings.ToLower(code)
+}
+
+func hashRecoveryCode(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
+
+// DataModel returns a data model object for the authenticator.
+func (m *MFA) DataModel() *datamodel.MFA {
+	dm := &datamodel.MFA{
+		UserID: m.userID,
+		Secret: m.secret,
+		LastUsedStep: m.lastUsedStep,
+		RecoveryCodes: make([]*datamodel.MFARecoveryCode, len(m.recoveryCodes)),
+	}
+
+	if m.confirmedAt != nil {
+		dm.ConfirmedAt.Valid = true
+		dm.ConfirmedAt.Time = *m.confirmedAt
+	}
+
+	for i, c := range m.recoveryCodes {
+		dm.RecoveryCodes[i] = &datamodel.MFARecoveryCode{
+			CodeHash: c.hash,
+		}
+
+		if c.usedAt != nil {
+			dm.RecoveryCodes[i].UsedAt.Valid = true
+			dm.RecoveryCodes[i].UsedAt.Time = *c.usedAt
+		}
+	}
+
+	return dm
+}
+
+// MFAFromDataModel returns a new instance of MFA, populated with the
+// data from the data model. This should only be used by repositories.
+func MFAFromDataModel(dm *datamodel.MFA) *MFA {
+	m := &MFA{
+		userID: dm.UserID,
+		secret: dm.Secret,
+		lastUsedStep: dm.LastUsedStep,
+		recoveryCodes: make([]*recoveryCode, len(dm.RecoveryCodes)),
+	}
+
+	if dm.ConfirmedAt.Valid {
+		m.confirmedAt = &dm.ConfirmedAt.Time
+	}
+
+	for i, c := range dm.RecoveryCodes {
+		m.recoveryCodes[i] = &recoveryCode{
+			hash: c.CodeHash,
+		}
+
+		if c.UsedAt.Valid {
+			m.recoveryCodes[i].usedAt = &c.UsedAt.Time
+		}
+	}
+
+	return m
+}
@@ -0,0 +1,89 @@
+package model
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/reecerussell/distro-blog/libraries/totp"
+)
+
+func TestMFA_Confirm(t *testing.T) {
+	m, err := NewMFA("user-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	now := time.Now().UTC()
+	if m.IsEnabled() {
+		t.Errorf("expected a new authenticator not to be enabled")
+	}
+
+	if _, err = m.Confirm("000000", now); err != ErrInvalidMFACode {
+		t.Errorf("expected '%v' but got: %v", ErrInvalidMFACode, err)
+	}
+
+	code, _ := totp.Code(m.Secret(), now)
+	codes, err := m.Confirm(code, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !m.IsEnabled() || len(codes) != RecoveryCodeCount || m.RemainingRecoveryCodes() != RecoveryCodeCount {
+		t.Errorf("expected the authenticator to be enabled, with %d recovery codes", RecoveryCodeCount)
+	}
+
+	for _, c := range m.DataModel().RecoveryCodes {
+		if strings.Contains(strings.Join(codes, ","), c.CodeHash) {
+			t.Errorf("expected only the hashes of the recovery codes to be stored")
+		}
+	}
+
+	if _, err = m.Confirm(code, now); err != ErrMFAAlreadyEnabled {
+		t.Errorf("expected '%v' but got: %v", ErrMFAAlreadyEnabled, err)
+	}
+}
+
+func TestMFA_Verify(t *testing.T) {
+	m, _ := NewMFA("user-1")
+	now := time.Now().UTC()
+
+	code, _ := totp.Code(m.Secret(), now)
+	if err := m.Verify(code, now); err != ErrMFANotEnabled {
+		t.Errorf("expected '%v' but got: %v", ErrMFANotEnabled, err)
+	}
+
+	codes, _ := m.Confirm(code, now)
+
+	// the code used to confirm the authenticator can't be used again.
+	if err := m.Verify(code, now); err != ErrInvalidMFACode {
+		t.Errorf("expected a used code to be invalid but got: %v", err)
+	}
+
+	later := now.Add(totp.Period * 2 * time.Second)
+	code, _ = totp.Code(m.Secret(), later)
+	if err := m.Verify(code[:3]+" "+code[3:], later); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if err := m.Verify(strings.ToUpper(codes[0]), now); err != nil {
+		t.Errorf("unexpected error using a recovery code: %v", err)
+	}
+
+	if err := m.Verify(codes[0], now); err != ErrInvalidMFACode {
+		t.Errorf("expected a used recovery code to be invalid but got: %v", err)
+	}
+
+	if m.RemainingRecoveryCodes() != RecoveryCodeCount-1 {
+		t.Errorf("expected %d remaining recovery codes but got %d", RecoveryCodeCount-1, m.RemainingRecoveryCodes())
+	}
+
+	m = MFAFromDataModel(m.DataModel())
+	if err := m.Verify(codes[0], now); err != ErrInvalidMFACode {
+		t.Errorf("expected the recovery code to still be used after reading it back but got: %v", err)
+	}
+
+	if err := m.Verify(codes[1], now); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -3,7 +3,9 @@ package model
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"os"
 	"strings"
 	"time"
 
@@ -13,15 +15,23 @@ import (
 	"github.com/reecerussell/distro-blog/domain/dto"
 	"github.com/reecerussell/distro-blog/domain/event"
 	"github.com/reecerussell/distro-blog/domain/handler"
+	"github.com/reecerussell/distro-blog/libraries/content"
 	"github.com/reecerussell/distro-blog/libraries/contextkey"
 	"github.com/reecerussell/distro-blog/libraries/domainevents"
 	"github.com/reecerussell/distro-blog/libraries/logging"
+	"github.com/reecerussell/distro-blog/libraries/sanitize"
 )
 
 func init() {
 	domainevents.RegisterEventHandler(&event.AddPageAudit{}, &handler.AddPageAudit{})
 	domainevents.RegisterEventHandler(&event.RemovePageImage{}, handler.NewRemovePageImageHandler())
 	domainevents.RegisterEventHandler(&event.UpdatePageSEO{}, &handler.UpdatePageSEO{})
+	domainevents.RegisterEventHandler(&event.AddPageRevision{}, &handler.AddPageRevision{})
+	domainevents.RegisterEventHandler(&event.SavePageDraft{}, &handler.SavePageDraft{})
+	domainevents.RegisterEventHandler(&event.DeletePageDraft{}, &handler.DeletePageDraft{})
+	domainevents.RegisterEventHandler(&event.AddPageRedirect{}, &handler.AddPageRedirect{})
+	domainevents.RegisterEventHandler(&event.UpdatePagePaths{}, &handler.UpdatePagePaths{})
+	domainevents.RegisterEventHandler(&event.AddPageAuthor{}, &handler.AddPageAuthor{})
 }
 
 const (
@@ -30,12 +40,61 @@ const (
 	AuditPageDeactivated = "PAGE_DEACTIVATED"
 	AuditPageActivated = "PAGE_ACTIVATED"
 	AuditPageImageUpdated = "PAGE_IMAGE_UPDATED"
+	AuditPageRevisionRestored = "PAGE_REVISION_RESTORED"
+	AuditPageScheduled = "PAGE_SCHEDULED"
+	AuditPageDraftSaved = "PAGE_DRAFT_SAVED"
+	AuditPageDraftPublished = "PAGE_DRAFT_PUBLISHED"
+	AuditPageMoved = "PAGE_MOVED"
+	AuditPageTrashed = "PAGE_TRASHED"
+	AuditPageRestored = "PAGE_RESTORED"
+	AuditPagePurged = "PAGE_PURGED"
 )
 
-// These tags are tags which are not allowed to be used
-// in the page content. Page content can contain HTML, which
-// therefore is not allowed to contain these HTML tags.
-var disallowedContentTags = [...]string{"script","link","body","html","head"}
+// Formats which page content can be written in. Markdown content is
+// stored as the source, and rendered to HTML each time it is saved.
+const (
+	ContentFormatHTML = "html"
+	ContentFormatMarkdown = "markdown"
+)
+
+// blogPathPrefix is the section of the public site blogs are served from.
+const blogPathPrefix = "blog"
+
+// errPageTrashed is returned when attempting to change a page which is in the trash.
+var errPageTrashed = fmt.Errorf("page is in the trash and must be restored before it can be changed")
+
+// defaultEmbedHosts are the hosts iframes can be embedded from in page
+// content, if the CONTENT_EMBED_HOSTS environment variable is not set.
+const defaultEmbedHosts = "www.youtube.com,www.youtube-nocookie.com"
+
+// contentPolicy is the allowlist used to sanitize page content. The hosts which
+// can be embedded and any additional URL schemes can be configured using the
+// CONTENT_EMBED_HOSTS and CONTENT_URL_SCHEMES environment variables.
+var contentPolicy = newContentPolicy(os.Getenv("CONTENT_EMBED_HOSTS"), os.Getenv("CONTENT_URL_SCHEMES"))
+
+func newContentPolicy(embedHosts, urlSchemes string) *sanitize.Policy {
+	if embedHosts == "" {
+		embedHosts = defaultEmbedHosts
+	}
+
+	p := sanitize.DefaultPolicy()
+	p.AllowEmbeds(splitList(embedHosts)...)
+	p.AllowURLSchemes(splitList(urlSchemes)...)
+
+	return p
+}
+
+// splitList splits a comma-separated list, ignoring empty items.
+func splitList(s string) []string {
+	var items []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			items = append(items, v)
+		}
+	}
+
+	return items
+}
 
 type Page struct {
 	domainevents.Aggregate
@@ -45,12 +104,26 @@ type Page struct {
 	description string
 	imageID *string
 	content *string
+	contentFormat string
+	contentSource *string
+	strippedContent []string
+	outline []*content.Heading
+	wordCount int
+	readingTime int
+	excerpt *string
 	isBlog bool
 	isActive bool
 	url string
+	parentID *string
+	path string
 	seoID *string
+	publishAt *time.Time
+	unpublishAt *time.Time
+	publishedAt *time.Time
+	deletedAt *time.Time
 
 	seo *SEO
+	draft *PageDraft
 }
 
 // NewPage creates a new page domain object with the given date.
@@ -62,12 +135,14 @@ func NewPage(ctx context.Context, d *dto.CreatePage) (*Page, error) {
 		isBlog: false,
 	}
 
-	err := p.updateContent(d.Title, d.Description, d.Content, d.URL, d.SEO)
+	err := p.updateContent(d.Title, d.Description, d.ContentFormat, d.Content, d.URL, d.SEO)
 	if err != nil {
 		return nil, err
 	}
 
 	p.addAudit(ctx, AuditPageCreated)
+	p.addCreatorAsAuthor(ctx)
+	p.addRevision(ctx)
 
 	return p, nil
 }
@@ -81,12 +156,14 @@ func NewBlogPage(ctx context.Context, d *dto.CreatePage) (*Page, error) {
 		isBlog: true,
 	}
 
-	err := p.updateContent(d.Title, d.Description, d.Content, d.URL, d.SEO)
+	err := p.updateContent(d.Title, d.Description, d.ContentFormat, d.Content, d.URL, d.SEO)
 	if err != nil {
 		return nil, err
 	}
 
 	p.addAudit(ctx, AuditPageCreated)
+	p.addCreatorAsAuthor(ctx)
+	p.addRevision(ctx)
 
 	return p, nil
 }
@@ -101,26 +178,185 @@ func (p *Page) GetImageID() *string {
 	return p.imageID
 }
 
+// Title returns the page's title.
+func (p *Page) Title() string {
+	return p.title
+}
+
 // URL returns the page's url value.
 func (p *Page) URL() string {
 	return p.url
 }
 
+// Path returns the page's path on the public site, relative to the site's root.
+// A page's path is made up of its parent's path followed by its own url.
+func (p *Page) Path() string {
+	return "/" + p.path
+}
+
+// PublicPath returns the path of a top-level page on the public site, for the
+// given url. Blogs are served under the "blog/" prefix.
+func PublicPath(url string, isBlog bool) string {
+	if isBlog {
+		return "/" + blogPathPrefix + "/" + url
+	}
+
+	return "/" + url
+}
+
+// ParentID returns the id of the page's parent, or nil for a top-level page.
+func (p *Page) ParentID() *string {
+	return p.parentID
+}
+
+// HasDraft returns true if the page has unpublished changes.
+func (p *Page) HasDraft() bool {
+	return p.draft != nil
+}
+
 // Update updates the page's data, including; title, description and content.
+//
+// If the page is active, or already has a draft, the changes are saved to the
+// page's draft instead, leaving the live page untouched until PublishDraft is called.
+// Either way, the changes are recorded as a revision. The page's schedule is changed straight away, but only for the dates given.
 func (p *Page) Update(ctx context.Context, d *dto.UpdatePage) error {
-	err := p.updateContent(d.Title, d.Description, d.Content, d.URL, d.SEO)
+	if p.deletedAt != nil {
+		return errPageTrashed
+	}
+
+	err := p.updateSchedule(ctx, d.PublishAt, d.UnpublishAt)
+	if err != nil {
+		return err
+	}
+
+	if p.isActive || p.draft != nil {
+		err := p.saveDraft(ctx, d)
+		if err != nil {
+			return err
+		}
+
+		p.addAudit(ctx, AuditPageDraftSaved)
+
+		return nil
+	}
+
+	err = p.updateContent(d.Title, d.Description, d.ContentFormat, d.Content, d.URL, d.SEO)
 	if err != nil {
 		return err
 	}
 
 	p.addAudit(ctx, AuditPageUpdated)
+	p.addRevision(ctx)
+
+	return nil
+}
+
+// Restore updates the page's content with the content from the given revision.
+// The restore is saved as a new revision, so it can also be undone.
+func (p *Page) Restore(ctx context.Context, r *PageRevision) error {
+	if p.deletedAt != nil {
+		return errPageTrashed
+	}
+
+	if r.PageID() != p.id {
+		return fmt.Errorf("revision does not belong to this page")
+	}
+
+	d := r.UpdatePage()
+	if p.isActive || p.draft != nil {
+		err := p.saveDraft(ctx, d)
+		if err != nil {
+			return err
+		}
+
+		p.addAudit(ctx, AuditPageRevisionRestored)
+
+		return nil
+	}
+
+	err := p.updateContent(d.Title, d.Description, d.ContentFormat, d.Content, d.URL, d.SEO)
+	if err != nil {
+		return err
+	}
+
+	p.addAudit(ctx, AuditPageRevisionRestored)
+	p.addRevision(ctx)
+
+	return nil
+}
+
+// PublishDraft applies the page's draft to the live page, then removes the draft.
+// An error is returned if the page does not have a draft.
+func (p *Page) PublishDraft(ctx context.Context) error {
+	if p.deletedAt != nil {
+		return errPageTrashed
+	}
+
+	if p.draft == nil {
+		return fmt.Errorf("page does not have a draft to publish")
+	}
+
+	d := p.draft.UpdatePage()
+	err := p.updateContent(d.Title, d.Description, d.ContentFormat, d.Content, d.URL, d.SEO)
+	if err != nil {
+		return err
+	}
+
+	p.draft = nil
+	p.RaiseEvent(&event.DeletePageDraft{
+		PageID: p.id,
+	})
+
+	p.addAudit(ctx, AuditPageDraftPublished)
+	p.addRevision(ctx)
+
+	return nil
+}
+
+// saveDraft validates the changes, using the same rules as the live page,
+// then stores them as the page's draft, replacing any existing draft.
+func (p *Page) saveDraft(ctx context.Context, d *dto.UpdatePage) error {
+	v := &Page{id: p.id}
+	err := v.updateContent(d.Title, d.Description, d.ContentFormat, d.Content, d.URL, d.SEO)
+	if err != nil {
+		return err
+	}
+
+	p.strippedContent = v.strippedContent
+
+	// a draft without SEO data keeps the page's current SEO when published.
+	seo := v.seo
+	if seo == nil {
+		seo = p.seo
+	}
+
+	p.draft = &PageDraft{
+		pageID: p.id,
+		date: time.Now().UTC(),
+		title: v.title,
+		description: v.description,
+		content: v.source(),
+		contentFormat: v.contentFormat,
+		url: v.url,
+		seo: seo,
+	}
+
+	if uid := ctx.Value(contextkey.ContextKey("user_id")); uid != nil {
+		userID := uid.(string)
+		p.draft.userID = &userID
+	}
+
+	p.RaiseEvent(&event.SavePageDraft{
+		Draft: p.draft.DataModel(),
+	})
+	p.addDraftRevision()
 
 	return nil
 }
 
 // updateContent moves the core update logic to a separate functions to avoid
 // code duplication.
-func (p *Page) updateContent(title, description string, content *string, url string, seo *dto.SEO) error {
+func (p *Page) updateContent(title, description, format string, content *string, url string, seo *dto.SEO) error {
 	err := p.UpdateTitle(title)
 	if err != nil {
 		return err
@@ -131,7 +367,7 @@ func (p *Page) updateContent(title, description string, content *string, url str
 		return err
 	}
 
-	err = p.UpdateContent(content)
+	err = p.UpdateContent(format, content)
 	if err != nil {
 		return err
 	}
@@ -149,6 +385,7 @@ func (p *Page) updateContent(title, description string, content *string, url str
 				return err
 			}
 
+			p.seo = s
 			sdm = s.DataModel()
 		} else {
 			err = p.seo.Update(seo)
@@ -208,26 +445,129 @@ func (p *Page) UpdateDescription(description string) error {
 	return nil
 }
 
-// UpdateContent updates the page's HTML content. The given content
-// cannot contain any HTML tags that are in disallowedContentTags.
-func (p *Page) UpdateContent(content *string) error {
+// UpdateContent updates the page's content, which is written in the given format. If
+// the format is empty, the content is treated as HTML. Markdown content is kept as the
+// page's source and rendered to HTML. The resulting HTML is sanitized using contentPolicy,
+// removing anything which isn't allowed, which can be checked using StrippedContent.
+func (p *Page) UpdateContent(format string, content *string) error {
+	rc, err := renderContent(format, content)
+	if err != nil {
+		return err
+	}
+
+	p.strippedContent = rc.stripped
+	if len(p.strippedContent) > 0 {
+		logging.Debugf("[PAGE:%s]: stripped from content: %s\n", p.id, strings.Join(p.strippedContent, ", "))
+	}
+
+	p.content = rc.html
+	p.contentFormat = rc.format
+	p.contentSource = rc.source
+
+	p.updateMetadata()
+
+	return nil
+}
+
+// renderedContent is content which has been rendered to HTML and sanitized.
+type renderedContent struct {
+	html *string
+	format string
+	source *string
+	stripped []string
+}
+
+// renderContent renders content, written in the given format, to HTML and sanitizes it
+// using contentPolicy. The source is only kept for content written in Markdown.
+func renderContent(format string, content *string) (*renderedContent, error) {
+	if format == "" {
+		format = ContentFormatHTML
+	}
+
+	if format != ContentFormatHTML && format != ContentFormatMarkdown {
+		return nil, fmt.Errorf("content format '%s' is not supported", format)
+	}
+
 	if content != nil && len(*content) < 1 {
 		content = nil
 	}
 
-	if content != nil {
-		nc := strings.ToLower(*content)
+	rc := &renderedContent{
+		format: format,
+	}
 
-		for _, s := range disallowedContentTags {
-			if strings.Contains(nc, fmt.Sprintf("<%s", s)) {
-				return fmt.Errorf("%s tags are not allowed in page content", s)
-			}
+	if content != nil && format == ContentFormatMarkdown {
+		rc.source = content
+
+		h, err := renderMarkdown(*content)
+		if err != nil {
+			return nil, err
 		}
+
+		content = &h
 	}
 
-	p.content = content
+	if content != nil {
+		res := contentPolicy.Sanitize(*content)
+		for _, r := range res.Removed {
+			rc.stripped = append(rc.stripped, r.String())
+		}
+
+		rc.html = &res.HTML
+	}
 
-	return nil
+	return rc, nil
+}
+
+// updateMetadata gives each of the headings in the content an id, then derives the
+// page's outline, word count, reading time and excerpt from the content.
+func (p *Page) updateMetadata() {
+	p.content, p.outline, p.wordCount, p.readingTime, p.excerpt = contentMetadata(p.content)
+}
+
+// contentMetadata returns the HTML with an id added to each heading, along with
+// the outline, word count, reading time and excerpt derived from it.
+func contentMetadata(html *string) (*string, []*content.Heading, int, int, *string) {
+	if html == nil {
+		return nil, nil, 0, 0, nil
+	}
+
+	c, outline := content.AnchorHeadings(*html)
+	wordCount := content.WordCount(c)
+
+	var excerpt *string
+	if e := content.Excerpt(c, content.DefaultExcerptLength); e != "" {
+		excerpt = &e
+	}
+
+	return &c, outline, wordCount, content.ReadingTime(wordCount), excerpt
+}
+
+// StrippedContent returns the elements and attributes which were removed from the
+// content when it was last updated, as they aren't allowed. For example, "<script>".
+func (p *Page) StrippedContent() []string {
+	return p.strippedContent
+}
+
+// renderMarkdown renders the Markdown source to HTML.
+func renderMarkdown(src string) (string, error) {
+	h, err := content.RenderMarkdown(src)
+	if err != nil {
+		logging.Errorf("Failed to render markdown: %v\n", err)
+		return "", fmt.Errorf("content could not be rendered from markdown")
+	}
+
+	return h, nil
+}
+
+// source returns the content the author wrote, which is the Markdown source
+// for Markdown pages, otherwise the page's HTML content.
+func (p *Page) source() *string {
+	if p.contentFormat == ContentFormatMarkdown {
+		return p.contentSource
+	}
+
+	return p.content
 }
 
 // A whitelist of characters that are allowed in page urls.
@@ -273,7 +613,28 @@ var urlSafeCharMap = map[string]bool {
 
 // UpdateURL updates the page's url. The url can only contain
 // whitelisted characters and cannot be greater than 255 chars long.
+//
+// The url is a single segment of the page's path, which is rebuilt from its
+// parent's path, so changing it also changes the paths of any child pages.
 func (p *Page) UpdateURL(url string) error {
+	err := validateURL(url)
+	if err != nil {
+		return err
+	}
+
+	path := p.parentPath() + url
+	if len(path) > 255 {
+		return fmt.Errorf("page path cannot be greater than 255 characters long")
+	}
+
+	p.url = url
+	p.setPath(path)
+
+	return nil
+}
+
+// validateURL ensures the url only contains whitelisted characters and isn't too long.
+func validateURL(url string) error {
 	if len(url) > 255 {
 		return fmt.Errorf("page url cannot be greater than 255 characters long")
 	}
@@ -288,7 +649,250 @@ func (p *Page) UpdateURL(url string) error {
 		}
 	}
 
-	p.url = url
+	return nil
+}
+
+// NewChildPage creates a new page, with the given data, beneath parent.
+func NewChildPage(ctx context.Context, d *dto.CreatePage, parent *Page) (*Page, error) {
+	p, err := NewPage(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	err = p.setParent(parent)
+	if err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// Move moves the page beneath parent, or to the top-level if parent is nil, updating the
+// page's path and the paths of all of its child pages. Blogs cannot be nested, and pages
+// cannot be moved beneath themselves, their own children or the home page.
+func (p *Page) Move(ctx context.Context, parent *Page) error {
+	if p.deletedAt != nil {
+		return errPageTrashed
+	}
+
+	path := p.path
+
+	err := p.setParent(parent)
+	if err != nil {
+		return err
+	}
+
+	if p.path != path {
+		p.addAudit(ctx, AuditPageMoved)
+	}
+
+	return nil
+}
+
+func (p *Page) setParent(parent *Page) error {
+	if p.isBlog {
+		return fmt.Errorf("blogs cannot be moved beneath another page")
+	}
+
+	if p.url == "" {
+		return fmt.Errorf("the home page cannot be moved")
+	}
+
+	path := p.url
+	if parent != nil {
+		switch true {
+		case parent.isBlog:
+			return fmt.Errorf("pages cannot be moved beneath a blog")
+		case parent.id == p.id || strings.HasPrefix(parent.path + "/", p.path + "/"):
+			return fmt.Errorf("a page cannot be moved beneath itself or one of its child pages")
+		case parent.path == "":
+			return fmt.Errorf("pages cannot be moved beneath the home page")
+		case parent.path == blogPathPrefix:
+			return fmt.Errorf("pages cannot be moved beneath the blog")
+		}
+
+		path = parent.path + "/" + p.url
+	}
+
+	if len(path) > 255 {
+		return fmt.Errorf("page path cannot be greater than 255 characters long")
+	}
+
+	p.parentID = nil
+	if parent != nil {
+		p.parentID = &parent.id
+	}
+
+	p.setPath(path)
+
+	return nil
+}
+
+// parentPath returns the path the page's url is appended to, including the trailing slash.
+func (p *Page) parentPath() string {
+	if p.isBlog {
+		return blogPathPrefix + "/"
+	}
+
+	if p.parentID == nil {
+		return ""
+	}
+
+	return p.path[:strings.LastIndex(p.path, "/") + 1]
+}
+
+// setPath changes the page's path. If the page is, or has been, published, a permanent
+// redirect from the old path is added, so existing links to the page continue to work.
+func (p *Page) setPath(path string) {
+	if p.path == "" || path == p.path {
+		p.path = path
+		return
+	}
+
+	if p.isActive || p.publishedAt != nil {
+		r := newPageRedirect(p.id, "/" + p.path, "/" + path)
+		p.RaiseEvent(&event.AddPageRedirect{
+			Redirect: r.DataModel(),
+		})
+	}
+
+	if !p.isBlog {
+		p.RaiseEvent(&event.UpdatePagePaths{
+			PageID: p.id,
+		})
+	}
+
+	p.path = path
+}
+
+// Schedule sets the dates the page should be automatically activated
+// and deactivated at. Either date can be nil, to clear the schedule. If both
+// dates are given, the page must be published before it is unpublished.
+func (p *Page) Schedule(ctx context.Context, publishAt, unpublishAt *time.Time) error {
+	if p.deletedAt != nil {
+		return errPageTrashed
+	}
+
+	if publishAt != nil && unpublishAt != nil && !unpublishAt.After(*publishAt) {
+		return fmt.Errorf("the unpublish date must be after the publish date")
+	}
+
+	if timeEqual(p.publishAt, publishAt) && timeEqual(p.unpublishAt, unpublishAt) {
+		return nil
+	}
+
+	p.publishAt = utcTime(publishAt)
+	p.unpublishAt = utcTime(unpublishAt)
+	p.addAudit(ctx, AuditPageScheduled)
+
+	return nil
+}
+
+// updateSchedule schedules the page using the given dates, keeping the
+// page's current dates for any which are nil.
+func (p *Page) updateSchedule(ctx context.Context, publishAt, unpublishAt *time.Time) error {
+	if publishAt == nil {
+		publishAt = p.publishAt
+	}
+
+	if unpublishAt == nil {
+		unpublishAt = p.unpublishAt
+	}
+
+	return p.Schedule(ctx, publishAt, unpublishAt)
+}
+
+// ApplySchedule activates or deactivates the page if its publish or unpublish date
+// is on or before now. Each date is cleared once it has been applied, but is kept if
+// the page couldn't be activated or deactivated, so it's tried again. Returns true
+// if the page has been changed and needs to be saved.
+func (p *Page) ApplySchedule(ctx context.Context, now time.Time) bool {
+	changed := false
+
+	if p.publishAt != nil && !p.publishAt.After(now) {
+		if p.isActive {
+			p.publishAt = nil
+			changed = true
+		} else if err := p.Activate(ctx); err != nil {
+			logging.Errorf("[PAGE:%s]: failed to publish on schedule: %v\n", p.id, err)
+		} else {
+			changed = true
+		}
+	}
+
+	if p.unpublishAt != nil && !p.unpublishAt.After(now) {
+		if !p.isActive {
+			p.unpublishAt = nil
+			changed = true
+		} else if err := p.Deactivate(ctx); err != nil {
+			logging.Errorf("[PAGE:%s]: failed to unpublish on schedule: %v\n", p.id, err)
+		} else {
+			changed = true
+		}
+	}
+
+	return changed
+}
+
+// timeEqual returns true if both times are nil, or are the same instant.
+func timeEqual(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return a.Equal(*b)
+}
+
+// utcTime returns a copy of t in UTC, as dates are stored without a time zone.
+func utcTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+
+	u := t.UTC()
+	return &u
+}
+
+// IsActive returns true if the page is published on the public site.
+func (p *Page) IsActive() bool {
+	return p.isActive
+}
+
+// IsBlog returns true if the page is a blog.
+func (p *Page) IsBlog() bool {
+	return p.isBlog
+}
+
+// IsTrashed returns true if the page has been moved to the trash.
+func (p *Page) IsTrashed() bool {
+	return p.deletedAt != nil
+}
+
+// Trash moves the page to the trash, where it can be restored until it is purged. The
+// page is deactivated and any schedule is cleared, so it can't be published while trashed.
+func (p *Page) Trash(ctx context.Context) error {
+	if p.deletedAt != nil {
+		return fmt.Errorf("page is already in the trash")
+	}
+
+	now := time.Now().UTC()
+	p.deletedAt = &now
+	p.isActive = false
+	p.publishAt = nil
+	p.unpublishAt = nil
+	p.addAudit(ctx, AuditPageTrashed)
+
+	return nil
+}
+
+// RestoreFromTrash takes the page out of the trash. The page remains inactive.
+func (p *Page) RestoreFromTrash(ctx context.Context) error {
+	if p.deletedAt == nil {
+		return fmt.Errorf("page is not in the trash")
+	}
+
+	p.deletedAt = nil
+	p.addAudit(ctx, AuditPageRestored)
 
 	return nil
 }
@@ -301,19 +905,31 @@ func (p *Page) Deactivate(ctx context.Context) error {
 	}
 
 	p.isActive = false
+	p.unpublishAt = nil
 	p.addAudit(ctx, AuditPageDeactivated)
 
 	return nil
 }
 
 // Activate marks the page as active. A non-nil error will
-// be returned if the page is already active.
+// be returned if the page is already active. The first time
+// a page is activated is recorded as its publish date.
 func (p *Page) Activate(ctx context.Context) error {
+	if p.deletedAt != nil {
+		return errPageTrashed
+	}
+
 	if p.isActive {
 		return fmt.Errorf("page ia already active")
 	}
 
 	p.isActive = true
+	p.publishAt = nil
+
+	if p.publishedAt == nil {
+		now := time.Now().UTC()
+		p.publishedAt = &now
+	}
 	p.addAudit(ctx, AuditPageActivated)
 
 	return nil
@@ -340,6 +956,20 @@ func (p *Page) UpdateSEO(d *dto.SEO) error {
 	return p.seo.Update(d)
 }
 
+// addCreatorAsAuthor makes the user creating the page its first author.
+func (p *Page) addCreatorAsAuthor(ctx context.Context) {
+	uid := ctx.Value(contextkey.ContextKey("user_id"))
+	if uid == nil {
+		return
+	}
+
+	p.RaiseEvent(&event.AddPageAuthor{
+		PageID: p.id,
+		UserID: uid.(string),
+		Position: 0,
+	})
+}
+
 // addAudit raises an audit domain event for the page.
 func (p *Page) addAudit(ctx context.Context, message string) {
 	logging.Debugf("[PAGE:%s]: raising audit domain event.\n", p.id)
@@ -362,6 +992,65 @@ func (p *Page) addAudit(ctx context.Context, message string) {
 	p.RaiseEvent(e)
 }
 
+// addRevision raises a domain event to store a snapshot of the page's current content.
+func (p *Page) addRevision(ctx context.Context) {
+	dm := p.DataModel()
+	r := &datamodel.PageRevision{
+		ID: uuid.New().String(),
+		PageID: p.id,
+		Date: time.Now().UTC(),
+		Title: dm.Title,
+		Description: dm.Description,
+		ContentFormat: dm.ContentFormat,
+		URL: dm.URL,
+	}
+
+	// revisions hold the content as the author wrote it, so
+	// they can be restored into the editor.
+	if src := p.source(); src != nil {
+		r.Content = sql.NullString{
+			Valid: true,
+			String: *src,
+		}
+	}
+
+	if uid := ctx.Value(contextkey.ContextKey("user_id")); uid != nil {
+		r.UserID = sql.NullString{
+			Valid: true,
+			String: uid.(string),
+		}
+	}
+
+	if p.seo != nil {
+		r.Seo = p.seo.DataModel()
+	}
+
+	p.RaiseEvent(&event.AddPageRevision{
+		Revision: r,
+	})
+}
+
+// addDraftRevision raises a domain event to store a snapshot of the page's draft,
+// so that changes saved to the draft are kept, even though the draft is replaced.
+func (p *Page) addDraftRevision() {
+	dm := p.draft.DataModel()
+	p.RaiseEvent(&event.AddPageRevision{
+		Revision: &datamodel.PageRevision{
+			ID: uuid.New().String(),
+			PageID: p.id,
+			UserID: dm.UserID,
+			Date: dm.Date,
+			Title: dm.Title,
+			Description: dm.Description,
+			Content: dm.Content,
+			ContentFormat: dm.ContentFormat,
+			URL: dm.URL,
+			IsDraft: true,
+			Seo: dm.Seo,
+		},
+	})
+}
+
 // DataModel returns a data model object for the page.
 func (p *Page) DataModel() *datamodel.Page {
 	dm := &datamodel.Page{
@@ -371,6 +1060,67 @@ func (p *Page) DataModel() *datamodel.Page {
 		IsBlog:      p.isBlog,
 		IsActive:    p.isActive,
 		URL: p.url,
+		Path: p.path,
+		ContentFormat: p.contentFormat,
+		WordCount: p.wordCount,
+		ReadingTime: p.readingTime,
+	}
+
+	if p.outline != nil {
+		outline, _ := json.Marshal(p.outline)
+		dm.Outline = sql.NullString{
+			Valid: true,
+			String: string(outline),
+		}
+	}
+
+	if p.excerpt != nil {
+		dm.Excerpt = sql.NullString{
+			Valid: true,
+			String: *p.excerpt,
+		}
+	}
+
+	if p.contentSource != nil {
+		dm.ContentSource = sql.NullString{
+			Valid: true,
+			String: *p.contentSource,
+		}
+	}
+
+	if p.parentID != nil {
+		dm.ParentID = sql.NullString{
+			Valid: true,
+			String: *p.parentID,
+		}
+	}
+
+	if p.publishAt != nil {
+		dm.PublishAt = sql.NullTime{
+			Valid: true,
+			Time: *p.publishAt,
+		}
+	}
+
+	if p.unpublishAt != nil {
+		dm.UnpublishAt = sql.NullTime{
+			Valid: true,
+			Time: *p.unpublishAt,
+		}
+	}
+
+	if p.publishedAt != nil {
+		dm.PublishedAt = sql.NullTime{
+			Valid: true,
+			Time: *p.publishedAt,
+		}
+	}
+
+	if p.deletedAt != nil {
+		dm.DeletedAt = sql.NullTime{
+			Valid: true,
+			Time: *p.deletedAt,
+		}
 	}
 
 	if p.content == nil {
@@ -408,20 +1158,72 @@ func PageFromDataModel(d *datamodel.Page) *Page {
 		isActive: d.IsActive,
 		isBlog: d.IsBlog,
 		url: d.URL,
+		path: d.Path,
+		contentFormat: d.ContentFormat,
+		wordCount: d.WordCount,
+		readingTime: d.ReadingTime,
+	}
+
+	if d.Outline.Valid {
+		err := json.Unmarshal([]byte(d.Outline.String), &p.outline)
+		if err != nil {
+			logging.Errorf("[PAGE:%s]: failed to read outline: %v\n", p.id, err)
+		}
+	}
+
+	if d.Excerpt.Valid {
+		p.excerpt = &d.Excerpt.String
+	}
+
+	if p.contentFormat == "" {
+		p.contentFormat = ContentFormatHTML
+	}
+
+	// pages which have not yet been given a path are at the top-level.
+	if p.path == "" {
+		p.path = strings.TrimPrefix(PublicPath(p.url, p.isBlog), "/")
+	}
+
+	if d.ParentID.Valid {
+		p.parentID = &d.ParentID.String
 	}
 
 	if d.Content.Valid {
 		p.content = &d.Content.String
 	}
 
+	if d.ContentSource.Valid {
+		p.contentSource = &d.ContentSource.String
+	}
+
 	if d.ImageID.Valid {
 		p.imageID = &d.ImageID.String
 	}
 
+	if d.PublishAt.Valid {
+		p.publishAt = &d.PublishAt.Time
+	}
+
+	if d.UnpublishAt.Valid {
+		p.unpublishAt = &d.UnpublishAt.Time
+	}
+
+	if d.PublishedAt.Valid {
+		p.publishedAt = &d.PublishedAt.Time
+	}
+
+	if d.DeletedAt.Valid {
+		p.deletedAt = &d.DeletedAt.Time
+	}
+
 	if d.Seo != nil {
 		p.seo = SEOFromDataModel(d.Seo)
 	}
 
+	if d.Draft != nil {
+		p.draft = PageDraftFromDataModel(d.Draft)
+	}
+
 	return p
 }
 
@@ -432,15 +1234,39 @@ func (p *Page) DTO() *dto.Page {
 		Title:       p.title,
 		Description: p.description,
 		Content:     p.content,
+		ContentFormat: p.contentFormat,
+		ContentSource: p.source(),
+		Outline: make([]*dto.PageHeading, len(p.outline)),
+		WordCount: p.wordCount,
+		ReadingTime: p.readingTime,
+		Excerpt: p.excerpt,
 		IsBlog:      p.isBlog,
 		IsActive:    p.isActive,
 		ImageID: p.imageID,
 		URL: p.url,
+		ParentID: p.parentID,
+		Path: p.path,
+		PublishAt: p.publishAt,
+		UnpublishAt: p.unpublishAt,
+		PublishedAt: p.publishedAt,
+		DeletedAt: p.deletedAt,
+	}
+
+	for i, h := range p.outline {
+		d.Outline[i] = &dto.PageHeading{
+			Level: h.Level,
+			ID: h.ID,
+			Text: h.Text,
+		}
 	}
 
 	if p.seo != nil {
 		d.SEO = p.seo.DTO()
 	}
 
+	if p.draft != nil {
+		d.Draft = p.draft.DTO()
+	}
+
 	return d
 }
\ No newline at end of file
@@ -0,0 +1,126 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/reecerussell/distro-blog/domain/datamodel"
+	"github.com/reecerussell/distro-blog/domain/dto"
+)
+
+// PageDraft holds changes to a published page, which have been saved
+// but are not yet live. A draft is promoted using Page.PublishDraft.
+type PageDraft struct {
+	pageID string
+	userID *string
+	userFullname *string
+	date time.Time
+	title string
+	description string
+	content *string
+	contentFormat string
+	url string
+
+	seo *SEO
+}
+
+// UpdatePage returns a *dto.UpdatePage populated with the draft's
+// content, which is used to apply the draft to the page.
+func (d *PageDraft) UpdatePage() *dto.UpdatePage {
+	u := &dto.UpdatePage{
+		Title: d.title,
+		Description: d.description,
+		Content: d.content,
+		ContentFormat: d.contentFormat,
+		URL: d.url,
+	}
+
+	if d.seo != nil {
+		u.SEO = d.seo.DTO()
+	}
+
+	return u
+}
+
+// DataModel returns a data model object for the draft.
+func (d *PageDraft) DataModel() *datamodel.PageDraft {
+	dm := &datamodel.PageDraft{
+		PageID: d.pageID,
+		Date: d.date,
+		Title: d.title,
+		Description: d.description,
+		ContentFormat: d.contentFormat,
+		URL: d.url,
+	}
+
+	if d.userID != nil {
+		dm.UserID = sql.NullString{
+			Valid: true,
+			String: *d.userID,
+		}
+	}
+
+	if d.content != nil {
+		dm.Content = sql.NullString{
+			Valid: true,
+			String: *d.content,
+		}
+	}
+
+	if d.seo != nil {
+		dm.Seo = d.seo.DataModel()
+	}
+
+	return dm
+}
+
+// DTO returns a *dto.PageDraft for the draft.
+func (d *PageDraft) DTO() *dto.PageDraft {
+	dto := &dto.PageDraft{
+		UserID: d.userID,
+		UserFullname: d.userFullname,
+		Date: d.date,
+		Title: d.title,
+		Description: d.description,
+		Content: d.content,
+		ContentFormat: d.contentFormat,
+		URL: d.url,
+	}
+
+	if d.seo != nil {
+		dto.SEO = d.seo.DTO()
+	}
+
+	return dto
+}
+
+// PageDraftFromDataModel returns a new instance of PageDraft, populated
+// with the data from the data model. This should only be used by repositories.
+func PageDraftFromDataModel(dm *datamodel.PageDraft) *PageDraft {
+	d := &PageDraft{
+		pageID: dm.PageID,
+		date: dm.Date,
+		title: dm.Title,
+		description: dm.Description,
+		contentFormat: dm.ContentFormat,
+		url: dm.URL,
+	}
+
+	if dm.UserID.Valid {
+		d.userID = &dm.UserID.String
+	}
+
+	if dm.UserFullname.Valid {
+		d.userFullname = &dm.UserFullname.String
+	}
+
+	if dm.Content.Valid {
+		d.content = &dm.Content.String
+	}
+
+	if dm.Seo != nil {
+		d.seo = SEOFromDataModel(dm.Seo)
+	}
+
+	return d
+}
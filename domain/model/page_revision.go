@@ -0,0 +1,189 @@
+package model
+
+import (
+	"time"
+
+	"github.com/reecerussell/distro-blog/domain/datamodel"
+	"github.com/reecerussell/distro-blog/domain/dto"
+	"github.com/reecerussell/distro-blog/libraries/diff"
+)
+
+// PageRevision is a read-only snapshot of a page's content, taken each
+// time the page is saved. Revisions are used to view the history of a
+// page, compare versions and restore previous content. Saves to a page's
+// draft are also recorded, as draft revisions.
+type PageRevision struct {
+	id string
+	pageID string
+	number int
+	userID *string
+	userFullname *string
+	date time.Time
+	title string
+	description string
+	content *string
+	contentFormat string
+	url string
+	isDraft bool
+
+	seo *SEO
+}
+
+// GetID returns the revision's id.
+func (r *PageRevision) GetID() string {
+	return r.id
+}
+
+// PageID returns the id of the page the revision belongs to.
+func (r *PageRevision) PageID() string {
+	return r.pageID
+}
+
+// UpdatePage returns a *dto.UpdatePage populated with the revision's
+// content, which can be used to restore the page to this revision.
+func (r *PageRevision) UpdatePage() *dto.UpdatePage {
+	d := &dto.UpdatePage{
+		ID: r.pageID,
+		Title: r.title,
+		Description: r.description,
+		Content: r.content,
+		ContentFormat: r.contentFormat,
+		URL: r.url,
+	}
+
+	if r.seo != nil {
+		d.SEO = r.seo.DTO()
+	}
+
+	return d
+}
+
+// Diff compares the revision with another revision of the same page, returning
+// the before and after value for each field. If asHTML is true, each changed text
+// field will also contain a HTML representation of the changes.
+func (r *PageRevision) Diff(to *PageRevision, asHTML bool) *dto.PageRevisionDiff {
+	fromSEO, toSEO := r.seoOrDefault(), to.seoOrDefault()
+
+	return &dto.PageRevisionDiff{
+		From: r.summary(),
+		To: to.summary(),
+		Fields: []*dto.PageRevisionFieldDiff{
+			diffText("title", &r.title, &to.title, asHTML),
+			diffText("description", &r.description, &to.description, asHTML),
+			diffText("content", r.content, to.content, asHTML),
+			diffText("contentFormat", &r.contentFormat, &to.contentFormat, asHTML),
+			diffText("url", &r.url, &to.url, asHTML),
+			diffText("seoTitle", fromSEO.title, toSEO.title, asHTML),
+			diffText("seoDescription", fromSEO.description, toSEO.description, asHTML),
+			diffBool("seoIndex", fromSEO.index, toSEO.index),
+			diffBool("seoFollow", fromSEO.follow, toSEO.follow),
+		},
+	}
+}
+
+// seoOrDefault returns the revision's SEO data, or an empty
+// SEO object if the page had no SEO data at the time.
+func (r *PageRevision) seoOrDefault() *SEO {
+	if r.seo == nil {
+		return &SEO{}
+	}
+
+	return r.seo
+}
+
+func diffText(field string, before, after *string, asHTML bool) *dto.PageRevisionFieldDiff {
+	var b, a string
+	if before != nil {
+		b = *before
+	}
+
+	if after != nil {
+		a = *after
+	}
+
+	fd := &dto.PageRevisionFieldDiff{
+		Field: field,
+		Changed: b != a,
+		Before: before,
+		After: after,
+	}
+
+	if asHTML {
+		h := diff.HTML(diff.Words(b, a))
+		fd.HTML = &h
+	}
+
+	return fd
+}
+
+func diffBool(field string, before, after bool) *dto.PageRevisionFieldDiff {
+	return &dto.PageRevisionFieldDiff{
+		Field: field,
+		Changed: before != after,
+		Before: before,
+		After: after,
+	}
+}
+
+// summary returns a DTO for the revision, without the content or SEO data.
+func (r *PageRevision) summary() *dto.PageRevision {
+	return &dto.PageRevision{
+		ID: r.id,
+		PageID: r.pageID,
+		Number: r.number,
+		UserID: r.userID,
+		UserFullname: r.userFullname,
+		Date: r.date,
+		Title: r.title,
+		Description: r.description,
+		URL: r.url,
+		IsDraft: r.isDraft,
+	}
+}
+
+// DTO returns a *dto.PageRevision for the revision.
+func (r *PageRevision) DTO() *dto.PageRevision {
+	d := r.summary()
+	d.Content = r.content
+	d.ContentFormat = r.contentFormat
+
+	if r.seo != nil {
+		d.SEO = r.seo.DTO()
+	}
+
+	return d
+}
+
+// PageRevisionFromDataModel returns a new instance of PageRevision, populated
+// with the data from the data model. This should only be used by repositories.
+func PageRevisionFromDataModel(d *datamodel.PageRevision) *PageRevision {
+	r := &PageRevision{
+		id: d.ID,
+		pageID: d.PageID,
+		number: d.Number,
+		date: d.Date,
+		title: d.Title,
+		description: d.Description,
+		contentFormat: d.ContentFormat,
+		url: d.URL,
+		isDraft: d.IsDraft,
+	}
+
+	if d.UserID.Valid {
+		r.userID = &d.UserID.String
+	}
+
+	if d.UserFullname.Valid {
+		r.userFullname = &d.UserFullname.String
+	}
+
+	if d.Content.Valid {
+		r.content = &d.Content.String
+	}
+
+	if d.Seo != nil {
+		r.seo = SEOFromDataModel(d.Seo)
+	}
+
+	return r
+}
@@ -0,0 +1,102 @@
+package model
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/reecerussell/distro-blog/domain/datamodel"
+	"github.com/reecerussell/distro-blog/libraries/contextkey"
+)
+
+func testRevision(id, title, content string) *PageRevision {
+	return PageRevisionFromDataModel(&datamodel.PageRevision{
+		ID: id,
+		PageID: "page-1",
+		Title: title,
+		Description: "My description",
+		Content: sql.NullString{Valid: true, String: content},
+		URL: "my-page",
+	})
+}
+
+func TestPageRevision_Diff(t *testing.T) {
+	from := testRevision("1", "Old title", "<p>Hello world</p>")
+	to := testRevision("2", "New title", "<p>Hello world</p>")
+
+	d := from.Diff(to, false)
+	if d.From.ID != "1" || d.To.ID != "2" {
+		t.Errorf("expected the diff to be from '1' to '2' but was from '%s' to '%s'", d.From.ID, d.To.ID)
+	}
+
+	changed := make(map[string]bool)
+	for _, f := range d.Fields {
+		changed[f.Field] = f.Changed
+
+		if f.HTML != nil {
+			t.Errorf("expected no HTML diff for field '%s'", f.Field)
+		}
+	}
+
+	if !changed["title"] {
+		t.Errorf("expected the title to have changed")
+	}
+
+	for _, f := range []string{"description", "content", "url", "seoTitle", "seoIndex"} {
+		if changed[f] {
+			t.Errorf("expected '%s' to not have changed", f)
+		}
+	}
+}
+
+func TestPageRevision_DiffAsHTML(t *testing.T) {
+	from := testRevision("1", "Title", "one two")
+	to := testRevision("2", "Title", "one three")
+
+	d := from.Diff(to, true)
+	for _, f := range d.Fields {
+		if f.Field != "content" {
+			continue
+		}
+
+		expected := "one <del>two</del><ins>three</ins>"
+		if f.HTML == nil || *f.HTML != expected {
+			t.Errorf("expected the content HTML to be '%s' but got '%v'", expected, f.HTML)
+		}
+	}
+}
+
+func TestPage_Restore(t *testing.T) {
+	ctx := context.WithValue(context.Background(), contextkey.ContextKey("user_id"), "user-1")
+	p := PageFromDataModel(&datamodel.Page{
+		ID: "page-1",
+		Title: "Current title",
+		Description: "Current description",
+		URL: "current-url",
+	})
+
+	err := p.Restore(ctx, testRevision("1", "Old title", "Old content"))
+	if err != nil {
+		t.Errorf("expected no error but got: %v", err)
+	}
+
+	d := p.DTO()
+	if d.Title != "Old title" || d.URL != "my-page" || d.Content == nil || *d.Content != "Old content" {
+		t.Errorf("expected the page to be restored but got: %v", d)
+	}
+
+	// expect an audit and a revision event, as well as
+	// the child pages to be updated for the restored url.
+	if l := len(p.GetRaisedEvents()); l != 3 {
+		t.Errorf("expected 3 events to be raised but got %d", l)
+	}
+}
+
+func TestPage_RestoreWithRevisionFromAnotherPage(t *testing.T) {
+	p := PageFromDataModel(&datamodel.Page{ID: "page-2"})
+
+	err := p.Restore(context.Background(), testRevision("1", "Title", "Content"))
+	if err == nil {
+		t.Errorf("expected an error but got nil")
+	}
+}
@@ -0,0 +1,656 @@
+package model
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/reecerussell/distro-blog/domain/datamodel"
+	"github.com/reecerussell/distro-blog/domain/dto"
+	"github.com/reecerussell/distro-blog/domain/event"
+	"github.com/reecerussell/distro-blog/libraries/contextkey"
+)
+
+func TestPage_Schedule(t *testing.T) {
+	ctx := context.WithValue(context.Background(), contextkey.ContextKey("user_id"), "user-1")
+	p := PageFromDataModel(&datamodel.Page{ID: "page-1"})
+
+	publishAt := time.Now().Add(time.Hour)
+	unpublishAt := publishAt.Add(time.Hour)
+
+	err := p.Schedule(ctx, &publishAt, &unpublishAt)
+	if err != nil {
+		t.Errorf("expected no error but got: %v", err)
+	}
+
+	d := p.DTO()
+	if d.PublishAt == nil || !d.PublishAt.Equal(publishAt) {
+		t.Errorf("expected publishAt to be '%v' but got '%v'", publishAt, d.PublishAt)
+	}
+
+	if d.UnpublishAt == nil || !d.UnpublishAt.Equal(unpublishAt) {
+		t.Errorf("expected unpublishAt to be '%v' but got '%v'", unpublishAt, d.UnpublishAt)
+	}
+
+	if l := len(p.GetRaisedEvents()); l != 1 {
+		t.Errorf("expected 1 event to be raised but got %d", l)
+	}
+
+	// scheduling the same dates again shouldn't raise another audit event.
+	_ = p.Schedule(ctx, &publishAt, &unpublishAt)
+	if l := len(p.GetRaisedEvents()); l != 1 {
+		t.Errorf("expected 1 event to be raised but got %d", l)
+	}
+}
+
+func TestPage_ScheduleWithUnpublishBeforePublish(t *testing.T) {
+	p := PageFromDataModel(&datamodel.Page{ID: "page-1"})
+
+	publishAt := time.Now()
+	unpublishAt := publishAt.Add(-time.Hour)
+
+	err := p.Schedule(context.Background(), &publishAt, &unpublishAt)
+	if err == nil {
+		t.Errorf("expected an error but got nil")
+	}
+}
+
+func TestPage_ScheduleTrashedPage(t *testing.T) {
+	p := PageFromDataModel(&datamodel.Page{ID: "page-1", DeletedAt: sql.NullTime{Time: time.Now(), Valid: true}})
+
+	publishAt := time.Now().Add(time.Hour)
+	err := p.Schedule(context.Background(), &publishAt, nil)
+	if err != errPageTrashed {
+		t.Errorf("expected '%v' but got '%v'", errPageTrashed, err)
+	}
+}
+
+func TestPage_ApplyScheduleKeepsFailedDates(t *testing.T) {
+	now := time.Now().UTC()
+	p := PageFromDataModel(&datamodel.Page{
+		ID: "page-1",
+		PublishAt: sql.NullTime{Time: now.Add(-time.Minute), Valid: true},
+		DeletedAt: sql.NullTime{Time: now, Valid: true},
+	})
+
+	if p.ApplySchedule(context.Background(), now) {
+		t.Errorf("expected the page to not have changed")
+	}
+
+	d := p.DTO()
+	if d.IsActive {
+		t.Errorf("expected the page to be inactive")
+	}
+
+	if d.PublishAt == nil {
+		t.Errorf("expected publishAt to still be set")
+	}
+}
+
+func TestPage_ApplySchedule(t *testing.T) {
+	ctx := context.WithValue(context.Background(), contextkey.ContextKey("user_id"), "user-1")
+	now := time.Now().UTC()
+	p := PageFromDataModel(&datamodel.Page{ID: "page-1"})
+
+	publishAt := now.Add(-time.Minute)
+	unpublishAt := now.Add(time.Hour)
+	_ = p.Schedule(ctx, &publishAt, &unpublishAt)
+
+	if !p.ApplySchedule(ctx, now) {
+		t.Errorf("expected the page to have changed")
+	}
+
+	d := p.DTO()
+	if !d.IsActive {
+		t.Errorf("expected the page to be active")
+	}
+
+	if d.PublishAt != nil {
+		t.Errorf("expected publishAt to be cleared but got '%v'", d.PublishAt)
+	}
+
+	if d.UnpublishAt == nil {
+		t.Errorf("expected unpublishAt to still be set")
+	}
+
+	if p.ApplySchedule(ctx, now) {
+		t.Errorf("expected the page to not have changed")
+	}
+
+	if !p.ApplySchedule(ctx, unpublishAt) {
+		t.Errorf("expected the page to have changed")
+	}
+
+	if p.DTO().IsActive {
+		t.Errorf("expected the page to be inactive")
+	}
+}
+
+func TestPage_UpdateKeepsSchedule(t *testing.T) {
+	ctx := context.WithValue(context.Background(), contextkey.ContextKey("user_id"), "user-1")
+	p := PageFromDataModel(&datamodel.Page{ID: "page-1", Title: "Title", Description: "Description", URL: "page"})
+
+	publishAt := time.Now().Add(time.Hour)
+	_ = p.Schedule(ctx, &publishAt, nil)
+
+	err := p.Update(ctx, &dto.UpdatePage{ID: "page-1", Title: "New title", Description: "Description", URL: "page"})
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if d := p.DTO(); d.PublishAt == nil || !d.PublishAt.Equal(publishAt) {
+		t.Errorf("expected publishAt to be unchanged but got '%v'", d.PublishAt)
+	}
+}
+
+func TestPage_UpdateSchedulesPage(t *testing.T) {
+	ctx := context.WithValue(context.Background(), contextkey.ContextKey("user_id"), "user-1")
+	p := PageFromDataModel(&datamodel.Page{ID: "page-1", Title: "Title", Description: "Description", URL: "page"})
+
+	unpublishAt := time.Now().Add(time.Hour * 2)
+	_ = p.Schedule(ctx, nil, &unpublishAt)
+
+	publishAt := time.Now().Add(time.Hour)
+	err := p.Update(ctx, &dto.UpdatePage{ID: "page-1", Title: "Title", Description: "Description", URL: "page", PublishAt: &publishAt})
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	d := p.DTO()
+	if d.PublishAt == nil || !d.PublishAt.Equal(publishAt) {
+		t.Errorf("expected publishAt to be '%v' but got '%v'", publishAt, d.PublishAt)
+	}
+
+	if d.UnpublishAt == nil || !d.UnpublishAt.Equal(unpublishAt) {
+		t.Errorf("expected unpublishAt to be unchanged but got '%v'", d.UnpublishAt)
+	}
+
+	t.Run("Invalid Schedule", func(t *testing.T) {
+		publishAt := unpublishAt.Add(time.Hour)
+		err := p.Update(ctx, &dto.UpdatePage{ID: "page-1", Title: "Title", Description: "Description", URL: "page", PublishAt: &publishAt})
+		if err == nil {
+			t.Errorf("expected an error for a publish date after the unpublish date")
+		}
+	})
+}
+
+func TestPage_UpdateActivePageSavesDraft(t *testing.T) {
+	ctx := context.WithValue(context.Background(), contextkey.ContextKey("user_id"), "user-1")
+	p := PageFromDataModel(&datamodel.Page{
+		ID: "page-1",
+		Title: "Live title",
+		Description: "Live description",
+		URL: "live",
+		IsActive: true,
+	})
+
+	err := p.Update(ctx, &dto.UpdatePage{
+		ID: "page-1",
+		Title: "Draft title",
+		Description: "Draft description",
+		URL: "draft",
+	})
+	if err != nil {
+		t.Errorf("expected no error but got: %v", err)
+	}
+
+	d := p.DTO()
+	if d.Title != "Live title" || d.URL != "live" {
+		t.Errorf("expected the live page to be unchanged but got '%s' at '%s'", d.Title, d.URL)
+	}
+
+	if d.Draft == nil {
+		t.Fatalf("expected the page to have a draft")
+	}
+
+	if d.Draft.Title != "Draft title" || d.Draft.URL != "draft" {
+		t.Errorf("expected the draft to be '%s' at '%s' but got '%s' at '%s'", "Draft title", "draft", d.Draft.Title, d.Draft.URL)
+	}
+
+	if d.Draft.UserID == nil || *d.Draft.UserID != "user-1" {
+		t.Errorf("expected the draft to be saved by '%s' but got '%v'", "user-1", d.Draft.UserID)
+	}
+}
+
+func TestPage_UpdateActivePageAddsDraftRevision(t *testing.T) {
+	ctx := context.WithValue(context.Background(), contextkey.ContextKey("user_id"), "user-1")
+	p := PageFromDataModel(&datamodel.Page{ID: "page-1", Title: "Live title", Description: "Live description", URL: "live", IsActive: true})
+
+	for _, title := range []string{"First draft", "Second draft"} {
+		err := p.Update(ctx, &dto.UpdatePage{ID: "page-1", Title: title, Description: "Description", URL: "draft"})
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+	}
+
+	var revisions []*datamodel.PageRevision
+	for _, e := range p.GetRaisedEvents() {
+		if r, ok := e.(*event.AddPageRevision); ok {
+			revisions = append(revisions, r.Revision)
+		}
+	}
+
+	if len(revisions) != 2 {
+		t.Fatalf("expected 2 revisions but got %d", len(revisions))
+	}
+
+	for i, title := range []string{"First draft", "Second draft"} {
+		r := revisions[i]
+		if !r.IsDraft || r.Title != title {
+			t.Errorf("expected a draft revision titled '%s' but got '%s' (draft: %v)", title, r.Title, r.IsDraft)
+		}
+
+		if !r.UserID.Valid || r.UserID.String != "user-1" {
+			t.Errorf("expected the revision to be saved by '%s' but got '%v'", "user-1", r.UserID)
+		}
+	}
+}
+
+func TestPage_UpdateActivePageWithInvalidDraft(t *testing.T) {
+	p := PageFromDataModel(&datamodel.Page{ID: "page-1", IsActive: true})
+
+	err := p.Update(context.Background(), &dto.UpdatePage{ID: "page-1"})
+	if err == nil {
+		t.Errorf("expected an error but got nil")
+	}
+
+	if p.HasDraft() {
+		t.Errorf("expected the page to not have a draft")
+	}
+}
+
+func TestPage_PublishDraft(t *testing.T) {
+	ctx := context.WithValue(context.Background(), contextkey.ContextKey("user_id"), "user-1")
+	p := PageFromDataModel(&datamodel.Page{
+		ID: "page-1",
+		Title: "Live title",
+		Description: "Live description",
+		URL: "live",
+		IsActive: true,
+		Draft: &datamodel.PageDraft{
+			PageID: "page-1",
+			Title: "Draft title",
+			Description: "Draft description",
+			URL: "draft",
+		},
+	})
+
+	err := p.PublishDraft(ctx)
+	if err != nil {
+		t.Errorf("expected no error but got: %v", err)
+	}
+
+	d := p.DTO()
+	if d.Title != "Draft title" || d.URL != "draft" {
+		t.Errorf("expected the draft to be published but got '%s' at '%s'", d.Title, d.URL)
+	}
+
+	if p.HasDraft() {
+		t.Errorf("expected the draft to be removed")
+	}
+
+	// expect a redirect from the old url, the child pages to be updated,
+	// the draft to be deleted, an audit and a revision event.
+	if l := len(p.GetRaisedEvents()); l != 5 {
+		t.Errorf("expected 5 events to be raised but got %d", l)
+	}
+}
+
+func TestPage_PublishDraftWithNoDraft(t *testing.T) {
+	p := PageFromDataModel(&datamodel.Page{ID: "page-1", IsActive: true})
+
+	err := p.PublishDraft(context.Background())
+	if err == nil {
+		t.Errorf("expected an error but got nil")
+	}
+}
+
+func TestPage_ActivateSetsPublishedDate(t *testing.T) {
+	ctx := context.WithValue(context.Background(), contextkey.ContextKey("user_id"), "user-1")
+	p := PageFromDataModel(&datamodel.Page{ID: "page-1"})
+
+	_ = p.Activate(ctx)
+	published := p.DTO().PublishedAt
+	if published == nil {
+		t.Fatalf("expected the page to have a published date")
+	}
+
+	// re-activating the page should keep the original date.
+	_ = p.Deactivate(ctx)
+	_ = p.Activate(ctx)
+	if d := p.DTO().PublishedAt; d == nil || !d.Equal(*published) {
+		t.Errorf("expected the published date to be '%v' but got '%v'", published, d)
+	}
+}
+
+func TestPage_UpdateURLAddsRedirect(t *testing.T) {
+	p := PageFromDataModel(&datamodel.Page{ID: "page-1", URL: "old-url", IsBlog: true, IsActive: true})
+
+	err := p.UpdateURL("new-url")
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	var redirect *event.AddPageRedirect
+	for _, e := range p.GetRaisedEvents() {
+		if r, ok := e.(*event.AddPageRedirect); ok {
+			redirect = r
+		}
+	}
+
+	if redirect == nil {
+		t.Fatalf("expected a redirect to be added")
+	}
+
+	r := redirect.Redirect
+	if r.SourcePath != "/blog/old-url" || r.TargetPath != "/blog/new-url" || r.StatusCode != 301 {
+		t.Errorf("expected a 301 from '/blog/old-url' to '/blog/new-url' but got a %d from '%s' to '%s'",
+			r.StatusCode, r.SourcePath, r.TargetPath)
+	}
+}
+
+func TestPage_UpdateURLOfUnpublishedPage(t *testing.T) {
+	p := PageFromDataModel(&datamodel.Page{ID: "page-1", URL: "old-url"})
+
+	_ = p.UpdateURL("new-url")
+	for _, e := range p.GetRaisedEvents() {
+		if _, ok := e.(*event.AddPageRedirect); ok {
+			t.Errorf("expected no redirect for an unpublished page")
+		}
+	}
+}
+
+func TestNewChildPage(t *testing.T) {
+	ctx := context.WithValue(context.Background(), contextkey.ContextKey("user_id"), "user-1")
+	parent := PageFromDataModel(&datamodel.Page{ID: "parent", URL: "consulting", Path: "services/consulting"})
+
+	p, err := NewChildPage(ctx, &dto.CreatePage{
+		Title: "Pricing",
+		Description: "Our prices",
+		URL: "pricing",
+	}, parent)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	d := p.DTO()
+	if d.Path != "services/consulting/pricing" {
+		t.Errorf("expected the path to be 'services/consulting/pricing' but got '%s'", d.Path)
+	}
+
+	if d.ParentID == nil || *d.ParentID != "parent" {
+		t.Errorf("expected the parent id to be 'parent' but got '%v'", d.ParentID)
+	}
+}
+
+func TestPage_UpdateURLOfChildPage(t *testing.T) {
+	parentID := "parent"
+	p := PageFromDataModel(&datamodel.Page{
+		ID: "page-1",
+		URL: "pricing",
+		Path: "services/pricing",
+		ParentID: sql.NullString{Valid: true, String: parentID},
+		IsActive: true,
+	})
+
+	_ = p.UpdateURL("prices")
+	if path := p.Path(); path != "/services/prices" {
+		t.Errorf("expected the path to be '/services/prices' but got '%s'", path)
+	}
+
+	// expect a redirect from the old path, and the child pages to be updated.
+	var redirect *event.AddPageRedirect
+	var paths *event.UpdatePagePaths
+	for _, e := range p.GetRaisedEvents() {
+		switch v := e.(type) {
+		case *event.AddPageRedirect:
+			redirect = v
+		case *event.UpdatePagePaths:
+			paths = v
+		}
+	}
+
+	if redirect == nil || redirect.Redirect.SourcePath != "/services/pricing" {
+		t.Errorf("expected a redirect from '/services/pricing'")
+	}
+
+	if paths == nil || paths.PageID != "page-1" {
+		t.Errorf("expected the child page paths to be updated")
+	}
+}
+
+func TestPage_Move(t *testing.T) {
+	ctx := context.WithValue(context.Background(), contextkey.ContextKey("user_id"), "user-1")
+	p := PageFromDataModel(&datamodel.Page{ID: "page-1", URL: "pricing"})
+	parent := PageFromDataModel(&datamodel.Page{ID: "parent", URL: "services"})
+
+	err := p.Move(ctx, parent)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if path := p.Path(); path != "/services/pricing" {
+		t.Errorf("expected the path to be '/services/pricing' but got '%s'", path)
+	}
+
+	err = p.Move(ctx, nil)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if path := p.Path(); path != "/pricing" || p.ParentID() != nil {
+		t.Errorf("expected the page to be moved to the top-level but got '%s'", path)
+	}
+}
+
+func TestPage_MoveWithInvalidParent(t *testing.T) {
+	ctx := context.Background()
+	p := PageFromDataModel(&datamodel.Page{ID: "page-1", URL: "services"})
+
+	tests := map[string]*Page{
+		"itself": p,
+		"descendant": PageFromDataModel(&datamodel.Page{ID: "child", URL: "pricing", Path: "services/pricing"}),
+		"blog": PageFromDataModel(&datamodel.Page{ID: "blog", URL: "post", IsBlog: true}),
+		"home page": PageFromDataModel(&datamodel.Page{ID: "home"}),
+	}
+
+	for name, parent := range tests {
+		err := p.Move(ctx, parent)
+		if err == nil {
+			t.Errorf("%s: expected an error but got nil", name)
+		}
+	}
+
+	blog := PageFromDataModel(&datamodel.Page{ID: "blog", URL: "post", IsBlog: true})
+	err := blog.Move(ctx, p)
+	if err == nil {
+		t.Errorf("expected an error moving a blog but got nil")
+	}
+}
+
+func TestPage_Trash(t *testing.T) {
+	ctx := context.WithValue(context.Background(), contextkey.ContextKey("user_id"), "user-1")
+	p := PageFromDataModel(&datamodel.Page{ID: "page-1", URL: "pricing", IsActive: true})
+
+	err := p.Trash(ctx)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	d := p.DTO()
+	if !p.IsTrashed() || d.DeletedAt == nil {
+		t.Errorf("expected the page to be in the trash")
+	}
+
+	if d.IsActive {
+		t.Errorf("expected the page to be deactivated")
+	}
+
+	if err = p.Trash(ctx); err == nil {
+		t.Errorf("expected an error trashing the page twice but got nil")
+	}
+
+	if err = p.Activate(ctx); err == nil {
+		t.Errorf("expected an error activating a trashed page but got nil")
+	}
+
+	err = p.RestoreFromTrash(ctx)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if p.IsTrashed() || p.DTO().IsActive {
+		t.Errorf("expected the page to be restored as inactive")
+	}
+
+	if err = p.RestoreFromTrash(ctx); err == nil {
+		t.Errorf("expected an error restoring a page which isn't trashed but got nil")
+	}
+}
+
+func TestNewPageWithMarkdown(t *testing.T) {
+	ctx := context.WithValue(context.Background(), contextkey.ContextKey("user_id"), "user-1")
+	src := "# Hello\n\nSome *markdown*."
+	p, err := NewPage(ctx, &dto.CreatePage{
+		Title: "Hello",
+		Description: "A markdown page",
+		Content: &src,
+		ContentFormat: ContentFormatMarkdown,
+		URL: "hello",
+	})
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	d := p.DTO()
+	if d.ContentSource == nil || *d.ContentSource != src {
+		t.Errorf("expected the source to be '%s' but got '%v'", src, d.ContentSource)
+	}
+
+	expected := "<h1 id=\"hello\">Hello</h1>\n<p>Some <em>markdown</em>.</p>\n"
+	if d.Content == nil || *d.Content != expected {
+		t.Errorf("expected the content to be '%s' but got '%v'", expected, d.Content)
+	}
+
+	// the revision should hold the source, rather than the rendered HTML.
+	for _, e := range p.GetRaisedEvents() {
+		if r, ok := e.(*event.AddPageRevision); ok {
+			if r.Revision.Content.String != src || r.Revision.ContentFormat != ContentFormatMarkdown {
+				t.Errorf("expected the revision to contain the markdown source")
+			}
+		}
+	}
+}
+
+func TestPage_UpdateContent(t *testing.T) {
+	p := PageFromDataModel(&datamodel.Page{ID: "page-1"})
+
+	html := "<p>Hello</p>"
+	err := p.UpdateContent("", &html)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	d := p.DTO()
+	if d.ContentFormat != ContentFormatHTML || d.ContentSource == nil || *d.ContentSource != html {
+		t.Errorf("expected HTML content to be its own source")
+	}
+
+	if p.DataModel().ContentSource.Valid {
+		t.Errorf("expected the source not to be stored for HTML content")
+	}
+
+	err = p.UpdateContent("textile", &html)
+	if err == nil {
+		t.Errorf("expected an error for an unsupported format but got nil")
+	}
+
+	src := "Hi <script>alert(1)</script>"
+	err = p.UpdateContent(ContentFormatMarkdown, &src)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if c := p.DTO().Content; c == nil || strings.Contains(*c, "<script") {
+		t.Errorf("expected raw HTML to be removed from markdown but got '%v'", c)
+	}
+}
+
+func TestPage_UpdateContentStripsUnsafeHTML(t *testing.T) {
+	p := PageFromDataModel(&datamodel.Page{ID: "page-1"})
+
+	html := "<p onclick=\"steal()\">Hi</p><script>alert(1)</script>" +
+		"<iframe src=\"https://www.youtube.com/embed/abc\"></iframe>"
+	err := p.UpdateContent(ContentFormatHTML, &html)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	expected := "<p>Hi</p><iframe src=\"https://www.youtube.com/embed/abc\"></iframe>"
+	if c := p.DTO().Content; c == nil || *c != expected {
+		t.Errorf("expected the content to be '%s' but got '%v'", expected, c)
+	}
+
+	stripped := strings.Join(p.StrippedContent(), " ")
+	if stripped != "<p onclick> <script>" {
+		t.Errorf("expected the onclick attribute and script to be reported but got '%s'", stripped)
+	}
+}
+
+func TestPage_UpdateContentDerivesMetadata(t *testing.T) {
+	p := PageFromDataModel(&datamodel.Page{ID: "page-1"})
+
+	html := "<h2>Introduction</h2><p>" + strings.Repeat("word ", 250) + "</p><h2>Summary</h2>"
+	err := p.UpdateContent(ContentFormatHTML, &html)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	d := p.DTO()
+	if len(d.Outline) != 2 || d.Outline[0].ID != "introduction" || d.Outline[1].Text != "Summary" {
+		t.Errorf("expected an outline of two headings but got: %v", d.Outline)
+	}
+
+	if !strings.HasPrefix(*d.Content, `<h2 id="introduction">Introduction</h2>`) {
+		t.Errorf("expected the headings to be given ids but got '%s'", *d.Content)
+	}
+
+	if d.WordCount != 252 || d.ReadingTime != 2 {
+		t.Errorf("expected 252 words and 2 minutes but got %d words and %d minutes", d.WordCount, d.ReadingTime)
+	}
+
+	if d.Excerpt == nil || !strings.HasPrefix(*d.Excerpt, "Introduction word word") {
+		t.Errorf("expected an excerpt but got '%v'", d.Excerpt)
+	}
+
+	// the metadata should survive being saved and read back.
+	p = PageFromDataModel(p.DataModel())
+	if len(p.DTO().Outline) != 2 || p.DTO().ReadingTime != 2 {
+		t.Errorf("expected the metadata to be read from the data model")
+	}
+}
+
+func TestNewPage_AddsCreatorAsAuthor(t *testing.T) {
+	ctx := context.WithValue(context.Background(), contextkey.ContextKey("user_id"), "user-1")
+	p, err := NewBlogPage(ctx, &dto.CreatePage{
+		Title: "Hello",
+		Description: "A blog",
+		URL: "hello",
+	})
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	var authors []*event.AddPageAuthor
+	for _, e := range p.GetRaisedEvents() {
+		if a, ok := e.(*event.AddPageAuthor); ok {
+			authors = append(authors, a)
+		}
+	}
+
+	if len(authors) != 1 || authors[0].UserID != "user-1" || authors[0].Position != 0 {
+		t.Errorf("expected the creator to be added as the first author but got: %v", authors)
+	}
+}
@@ -0,0 +1,298 @@
+package model
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/reecerussell/distro-blog/domain/datamodel"
+	"github.com/reecerussell/distro-blog/domain/dto"
+	"github.com/reecerussell/distro-blog/libraries/content"
+	"github.com/reecerussell/distro-blog/libraries/locale"
+	"github.com/reecerussell/distro-blog/libraries/logging"
+)
+
+// PageTranslation is a page's content in another locale. It's served from its
+// own path, which is the page's path prefixed with the locale, where the last
+// segment can be translated. For example, "fr/pricing" or "fr/tarifs".
+type PageTranslation struct {
+	pageID string
+	locale string
+	title string
+	description string
+	content *string
+	contentFormat string
+	contentSource *string
+	outline []*content.Heading
+	wordCount int
+	readingTime int
+	excerpt *string
+	url *string
+	path string
+	seoTitle *string
+	seoDescription *string
+}
+
+// NewPageTranslation creates a translation of the page, in the given locale, with the given data.
+func NewPageTranslation(p *Page, l string, d *dto.SetPageTranslation) (*PageTranslation, error) {
+	if p.IsTrashed() {
+		return nil, errPageTrashed
+	}
+
+	l, err := locale.Normalize(l)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &PageTranslation{
+		pageID: p.id,
+		locale: l,
+	}
+
+	err = t.update(p, d)
+	if err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// Locale returns the translation's locale, such as "fr" or "pt-br".
+func (t *PageTranslation) Locale() string {
+	return t.locale
+}
+
+// Path returns the translation's path on the public site, relative to the site's root.
+func (t *PageTranslation) Path() string {
+	return "/" + t.path
+}
+
+func (t *PageTranslation) update(p *Page, d *dto.SetPageTranslation) error {
+	switch true {
+	case len(d.Title) < 1:
+		return fmt.Errorf("title is required")
+	case len(d.Title) > 255:
+		return fmt.Errorf("title cannot be greater than 255 characters long")
+	case len(d.Description) < 1:
+		return fmt.Errorf("description is required")
+	case len(d.Description) > 255:
+		return fmt.Errorf("description cannot be greater than 255 characters long")
+	case d.SeoTitle != nil && len(*d.SeoTitle) > 255:
+		return fmt.Errorf("seo title cannot be greater than 255 characters long")
+	case d.SeoDescription != nil && len(*d.SeoDescription) > 255:
+		return fmt.Errorf("seo description cannot be greater than 255 characters long")
+	}
+
+	err := t.updateURL(p, d.URL)
+	if err != nil {
+		return err
+	}
+
+	rc, err := renderContent(d.ContentFormat, d.Content)
+	if err != nil {
+		return err
+	}
+
+	if len(rc.stripped) > 0 {
+		logging.Debugf("[PAGE:%s:%s]: stripped from content: %s\n", t.pageID, t.locale, strings.Join(rc.stripped, ", "))
+	}
+
+	t.title = d.Title
+	t.description = d.Description
+	t.contentFormat = rc.format
+	t.contentSource = rc.source
+	t.content, t.outline, t.wordCount, t.readingTime, t.excerpt = contentMetadata(rc.html)
+	t.seoTitle = emptyToNil(d.SeoTitle)
+	t.seoDescription = emptyToNil(d.SeoDescription)
+
+	return nil
+}
+
+// updateURL sets the translated url of the page, or uses the page's own if url is
+// empty. The home page's url can't be translated, so it's served from the locale's root.
+func (t *PageTranslation) updateURL(p *Page, url *string) error {
+	url = emptyToNil(url)
+	if url != nil {
+		if p.url == "" {
+			return fmt.Errorf("the home page's url cannot be translated")
+		}
+
+		err := validateURL(*url)
+		if err != nil {
+			return err
+		}
+	}
+
+	segment := p.url
+	if url != nil {
+		segment = *url
+	}
+
+	path := strings.TrimSuffix(t.locale + "/" + p.parentPath() + segment, "/")
+	if len(path) > 255 {
+		return fmt.Errorf("page path cannot be greater than 255 characters long")
+	}
+
+	t.url = url
+	t.path = path
+
+	return nil
+}
+
+func emptyToNil(s *string) *string {
+	if s == nil || *s == "" {
+		return nil
+	}
+
+	return s
+}
+
+// DataModel returns a data model object for the translation.
+func (t *PageTranslation) DataModel() *datamodel.PageTranslation {
+	dm := &datamodel.PageTranslation{
+		PageID: t.pageID,
+		Locale: t.locale,
+		Title: t.title,
+		Description: t.description,
+		ContentFormat: t.contentFormat,
+		WordCount: t.wordCount,
+		ReadingTime: t.readingTime,
+		Path: t.path,
+	}
+
+	if t.content != nil {
+		dm.Content = sql.NullString{
+			Valid: true,
+			String: *t.content,
+		}
+	}
+
+	if t.contentSource != nil {
+		dm.ContentSource = sql.NullString{
+			Valid: true,
+			String: *t.contentSource,
+		}
+	}
+
+	if t.outline != nil {
+		outline, _ := json.Marshal(t.outline)
+		dm.Outline = sql.NullString{
+			Valid: true,
+			String: string(outline),
+		}
+	}
+
+	if t.excerpt != nil {
+		dm.Excerpt = sql.NullString{
+			Valid: true,
+			String: *t.excerpt,
+		}
+	}
+
+	if t.url != nil {
+		dm.URL = sql.NullString{
+			Valid: true,
+			String: *t.url,
+		}
+	}
+
+	if t.seoTitle != nil {
+		dm.SeoTitle = sql.NullString{
+			Valid: true,
+			String: *t.seoTitle,
+		}
+	}
+
+	if t.seoDescription != nil {
+		dm.SeoDescription = sql.NullString{
+			Valid: true,
+			String: *t.seoDescription,
+		}
+	}
+
+	return dm
+}
+
+// DTO returns a *dto.PageTranslation for the translation.
+func (t *PageTranslation) DTO() *dto.PageTranslation {
+	d := &dto.PageTranslation{
+		PageID: t.pageID,
+		Locale: t.locale,
+		Title: t.title,
+		Description: t.description,
+		Content: t.content,
+		ContentFormat: t.contentFormat,
+		ContentSource: t.content,
+		Outline: make([]*dto.PageHeading, len(t.outline)),
+		WordCount: t.wordCount,
+		ReadingTime: t.readingTime,
+		Excerpt: t.excerpt,
+		URL: t.url,
+		Path: t.path,
+		SeoTitle: t.seoTitle,
+		SeoDescription: t.seoDescription,
+	}
+
+	if t.contentFormat == ContentFormatMarkdown {
+		d.ContentSource = t.contentSource
+	}
+
+	for i, h := range t.outline {
+		d.Outline[i] = &dto.PageHeading{
+			Level: h.Level,
+			ID: h.ID,
+			Text: h.Text,
+		}
+	}
+
+	return d
+}
+
+// PageTranslationFromDataModel returns a new instance of PageTranslation, populated
+// with the data from the data model. This should only be used by repositories.
+func PageTranslationFromDataModel(dm *datamodel.PageTranslation) *PageTranslation {
+	t := &PageTranslation{
+		pageID: dm.PageID,
+		locale: dm.Locale,
+		title: dm.Title,
+		description: dm.Description,
+		contentFormat: dm.ContentFormat,
+		wordCount: dm.WordCount,
+		readingTime: dm.ReadingTime,
+		path: dm.Path,
+	}
+
+	if dm.Content.Valid {
+		t.content = &dm.Content.String
+	}
+
+	if dm.ContentSource.Valid {
+		t.contentSource = &dm.ContentSource.String
+	}
+
+	if dm.Outline.Valid {
+		err := json.Unmarshal([]byte(dm.Outline.String), &t.outline)
+		if err != nil {
+			logging.Errorf("[PAGE:%s:%s]: failed to read outline: %v\n", t.pageID, t.locale, err)
+		}
+	}
+
+	if dm.Excerpt.Valid {
+		t.excerpt = &dm.Excerpt.String
+	}
+
+	if dm.URL.Valid {
+		t.url = &dm.URL.String
+	}
+
+	if dm.SeoTitle.Valid {
+		t.seoTitle = &dm.SeoTitle.String
+	}
+
+	if dm.SeoDescription.Valid {
+		t.seoDescription = &dm.SeoDescription.String
+	}
+
+	return t
+}
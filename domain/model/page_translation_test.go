@@ -0,0 +1,94 @@
+package model
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/reecerussell/distro-blog/domain/datamodel"
+	"github.com/reecerussell/distro-blog/domain/dto"
+)
+
+func TestNewPageTranslation(t *testing.T) {
+	p := PageFromDataModel(&datamodel.Page{ID: "page-1", URL: "pricing", Path: "products/pricing", ParentID: sql.NullString{Valid: true, String: "parent-1"}})
+
+	url := "tarifs"
+	src := "# Nos tarifs"
+	tr, err := NewPageTranslation(p, "FR_ca", &dto.SetPageTranslation{
+		Title: "Tarifs",
+		Description: "Nos tarifs",
+		Content: &src,
+		ContentFormat: ContentFormatMarkdown,
+		URL: &url,
+	})
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if tr.Locale() != "fr-ca" {
+		t.Errorf("expected locale 'fr-ca' but got '%s'", tr.Locale())
+	}
+
+	if tr.Path() != "/fr-ca/products/tarifs" {
+		t.Errorf("expected path '/fr-ca/products/tarifs' but got '%s'", tr.Path())
+	}
+
+	d := tr.DTO()
+	if d.Content == nil || *d.Content != "<h1 id=\"nos-tarifs\">Nos tarifs</h1>\n" {
+		t.Errorf("expected the markdown to be rendered but got %v", d.Content)
+	}
+
+	if d.ContentSource == nil || *d.ContentSource != src || len(d.Outline) != 1 {
+		t.Errorf("expected the source to be kept and an outline to be derived")
+	}
+}
+
+func TestNewPageTranslation_DefaultsToPageURL(t *testing.T) {
+	blog := PageFromDataModel(&datamodel.Page{ID: "page-1", URL: "hello", Path: "blog/hello", IsBlog: true})
+	tr, err := NewPageTranslation(blog, "de", &dto.SetPageTranslation{Title: "Hallo", Description: "Hallo"})
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if tr.Path() != "/de/blog/hello" {
+		t.Errorf("expected path '/de/blog/hello' but got '%s'", tr.Path())
+	}
+
+	home := PageFromDataModel(&datamodel.Page{ID: "home", URL: "", Path: ""})
+	tr, err = NewPageTranslation(home, "de", &dto.SetPageTranslation{Title: "Startseite", Description: "Startseite"})
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if tr.Path() != "/de" {
+		t.Errorf("expected path '/de' but got '%s'", tr.Path())
+	}
+
+	url := "start"
+	_, err = NewPageTranslation(home, "de", &dto.SetPageTranslation{Title: "Startseite", Description: "Startseite", URL: &url})
+	if err == nil {
+		t.Errorf("expected an error translating the home page's url but got nil")
+	}
+}
+
+func TestNewPageTranslationWithInvalidData(t *testing.T) {
+	p := PageFromDataModel(&datamodel.Page{ID: "page-1", URL: "pricing", Path: "pricing"})
+	url := "Tarifs!"
+
+	tests := map[string]struct {
+		locale string
+		d *dto.SetPageTranslation
+	}{
+		"invalid locale": {"french", &dto.SetPageTranslation{Title: "Tarifs", Description: "Tarifs"}},
+		"empty title": {"fr", &dto.SetPageTranslation{Description: "Tarifs"}},
+		"empty description": {"fr", &dto.SetPageTranslation{Title: "Tarifs"}},
+		"invalid url": {"fr", &dto.SetPageTranslation{Title: "Tarifs", Description: "Tarifs", URL: &url}},
+		"invalid format": {"fr", &dto.SetPageTranslation{Title: "Tarifs", Description: "Tarifs", ContentFormat: "rtf"}},
+	}
+
+	for name, test := range tests {
+		_, err := NewPageTranslation(p, test.locale, test.d)
+		if err == nil {
+			t.Errorf("%s: expected an error but got nil", name)
+		}
+	}
+}
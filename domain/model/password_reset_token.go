@@ -0,0 +1,122 @@
+package model
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/reecerussell/distro-blog/domain/datamodel"
+)
+
+// PasswordResetTokenLifetime is how long a user has to reset their password,
+// after requesting a password reset.
+const PasswordResetTokenLifetime = time.Hour
+
+// Errors returned when a password reset token can't be used.
+var (
+	ErrPasswordResetTokenExpired = errors.New("password reset token has expired")
+	ErrPasswordResetTokenUsed = errors.New("password reset token has already been used")
+)
+
+// PasswordResetToken is a single-use token, emailed to a user who has forgotten
+// their password, which allows them to set a new one.
+//
+// Only a hash of the token is stored, so the token can't be read from the data source.
+type PasswordResetToken struct {
+	id string
+	userID string
+	tokenHash string
+	expiresAt time.Time
+	usedAt *time.Time
+}
+
+// NewPasswordResetToken returns a new password reset token for the user,
+// along with its value, which should be sent to the user.
+func NewPasswordResetToken(userID string, now time.Time) (*PasswordResetToken, string, error) {
+	b := make([]byte, 32)
+	_, err := rand.Read(b)
+	if err != nil {
+		return nil, "", err
+	}
+
+	value := base64.RawURLEncoding.EncodeToString(b)
+	t := &PasswordResetToken{
+		id: uuid.New().String(),
+		userID: userID,
+		tokenHash: HashPasswordResetToken(value),
+		expiresAt: now.Add(PasswordResetTokenLifetime),
+	}
+
+	return t, value, nil
+}
+
+// HashPasswordResetToken returns the hash of a password reset token's value, used to look it up.
+func HashPasswordResetToken(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
+
+// UserID returns the id of the user the token was issued to.
+func (t *PasswordResetToken) UserID() string {
+	return t.userID
+}
+
+// ExpiresAt returns the time the token expires at.
+func (t *PasswordResetToken) ExpiresAt() time.Time {
+	return t.expiresAt
+}
+
+// Use marks the token as used, returning an error if it has
+// already been used, or has expired.
+func (t *PasswordResetToken) Use(now time.Time) error {
+	if t.usedAt != nil {
+		return ErrPasswordResetTokenUsed
+	}
+
+	if !now.Before(t.expiresAt) {
+		return ErrPasswordResetTokenExpired
+	}
+
+	t.usedAt = &now
+
+	return nil
+}
+
+// DataModel returns a data model object for the password reset token.
+func (t *PasswordResetToken) DataModel() *datamodel.PasswordResetToken {
+	dm := &datamodel.PasswordResetToken{
+		ID: t.id,
+		UserID: t.userID,
+		TokenHash: t.tokenHash,
+		ExpiresAt: t.expiresAt,
+	}
+
+	if t.usedAt != nil {
+		dm.UsedAt.Valid = true
+		dm.UsedAt.Time = *t.usedAt
+	}
+
+	return dm
+}
+
+// PasswordResetTokenFromDataModel returns a new instance of PasswordResetToken, populated
+// with the data from the data model. This should only be used by repositories.
+func PasswordResetTokenFromDataModel(dm *datamodel.PasswordResetToken) *PasswordResetToken {
+	t := &PasswordResetToken{
+		id: dm.ID,
+		userID: dm.UserID,
+		tokenHash: dm.TokenHash,
+		expiresAt: dm.ExpiresAt,
+	}
+
+	if dm.UsedAt.Valid {
+		t.usedAt = &dm.UsedAt.Time
+	}
+
+	return t
+}
@@ -0,0 +1,35 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPasswordResetToken_Use(t *testing.T) {
+	now := time.Now().UTC()
+	token, value, err := NewPasswordResetToken("user-1", now)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if token.DataModel().TokenHash != HashPasswordResetToken(value) || token.DataModel().TokenHash == value {
+		t.Errorf("expected only the hash of the token to be stored")
+	}
+
+	if err = token.Use(now.Add(time.Minute)); err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if !token.DataModel().UsedAt.Valid {
+		t.Errorf("expected the token to be marked as used")
+	}
+
+	if err = token.Use(now.Add(time.Minute)); err != ErrPasswordResetTokenUsed {
+		t.Errorf("expected '%v' but got: %v", ErrPasswordResetTokenUsed, err)
+	}
+
+	token, _, _ = NewPasswordResetToken("user-1", now)
+	if err = token.Use(now.Add(PasswordResetTokenLifetime)); err != ErrPasswordResetTokenExpired {
+		t.Errorf("expected '%v' but got: %v", ErrPasswordResetTokenExpired, err)
+	}
+}
@@ -0,0 +1,218 @@
+package model
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/reecerussell/distro-blog/domain/datamodel"
+	"github.com/reecerussell/distro-blog/domain/dto"
+)
+
+var allowedRedirectStatusCodes = [...]int{
+	http.StatusMovedPermanently,
+	http.StatusFound,
+	http.StatusTemporaryRedirect,
+	http.StatusPermanentRedirect,
+}
+
+// Redirect redirects requests for a path on the public site, which no
+// longer exists, to another path or an external url.
+type Redirect struct {
+	id string
+	source string
+	target string
+	statusCode int
+	pageID *string
+	createdAt time.Time
+}
+
+// NewRedirect creates a new redirect with the given data. If no
+// status code is given, the redirect will be permanent.
+func NewRedirect(d *dto.CreateRedirect) (*Redirect, error) {
+	r := &Redirect{
+		id: uuid.New().String(),
+		createdAt: time.Now().UTC(),
+	}
+
+	err := r.updateSource(d.Source)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.Update(&dto.UpdateRedirect{
+		Target: d.Target,
+		StatusCode: d.StatusCode,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// newPageRedirect creates a permanent redirect from a page's old path to its new one.
+func newPageRedirect(pageID, source, target string) *Redirect {
+	return &Redirect{
+		id: uuid.New().String(),
+		source: strings.ToLower(source),
+		target: target,
+		statusCode: http.StatusMovedPermanently,
+		pageID: &pageID,
+		createdAt: time.Now().UTC(),
+	}
+}
+
+// GetID returns the redirect's id.
+func (r *Redirect) GetID() string {
+	return r.id
+}
+
+// Source returns the path which is redirected.
+func (r *Redirect) Source() string {
+	return r.source
+}
+
+// Target returns the path or url requests are redirected to.
+func (r *Redirect) Target() string {
+	return r.target
+}
+
+func (r *Redirect) updateSource(source string) error {
+	source = strings.ToLower(strings.TrimSpace(source))
+	err := validateRedirectPath(source)
+	if err != nil {
+		return fmt.Errorf("source %v", err)
+	}
+
+	r.source = source
+
+	return nil
+}
+
+// Update updates the redirect's target and status code. The target can either be a
+// path on the public site, or an absolute http(s) url, but cannot be the redirect's source.
+func (r *Redirect) Update(d *dto.UpdateRedirect) error {
+	target := strings.TrimSpace(d.Target)
+	if !strings.HasPrefix(target, "http://") && !strings.HasPrefix(target, "https://") {
+		err := validateRedirectPath(target)
+		if err != nil {
+			return fmt.Errorf("target %v", err)
+		}
+	} else if len(target) > 255 {
+		return fmt.Errorf("target cannot be greater than 255 characters long")
+	}
+
+	if strings.EqualFold(target, r.source) {
+		return fmt.Errorf("a redirect cannot redirect to itself")
+	}
+
+	statusCode := d.StatusCode
+	if statusCode == 0 {
+		statusCode = http.StatusMovedPermanently
+	}
+
+	if !isValidRedirectStatusCode(statusCode) {
+		return fmt.Errorf("status code %d is not a valid redirect status", statusCode)
+	}
+
+	r.target = target
+	r.statusCode = statusCode
+
+	return nil
+}
+
+// Follow updates the redirect's target to the target of next, which redirects this
+// redirect's target, collapsing the chain into a single redirect.
+func (r *Redirect) Follow(next *Redirect) error {
+	if !strings.EqualFold(r.target, next.source) {
+		return fmt.Errorf("redirect does not follow on from '%s'", r.target)
+	}
+
+	if strings.EqualFold(next.target, r.source) {
+		return fmt.Errorf("redirecting '%s' to '%s' would create a loop", r.source, r.target)
+	}
+
+	r.target = next.target
+
+	return nil
+}
+
+func validateRedirectPath(path string) error {
+	switch true {
+	case path == "":
+		return fmt.Errorf("is required")
+	case len(path) > 255:
+		return fmt.Errorf("cannot be greater than 255 characters long")
+	case !strings.HasPrefix(path, "/"):
+		return fmt.Errorf("must be a path starting with a '/'")
+	case strings.ContainsAny(path, "?# "):
+		return fmt.Errorf("cannot contain a query string, fragment or spaces")
+	}
+
+	return nil
+}
+
+func isValidRedirectStatusCode(statusCode int) bool {
+	for _, c := range allowedRedirectStatusCodes {
+		if statusCode == c {
+			return true
+		}
+	}
+
+	return false
+}
+
+// DTO returns a *dto.Redirect for the redirect.
+func (r *Redirect) DTO() *dto.Redirect {
+	return &dto.Redirect{
+		ID: r.id,
+		Source: r.source,
+		Target: r.target,
+		StatusCode: r.statusCode,
+		PageID: r.pageID,
+		CreatedAt: r.createdAt,
+	}
+}
+
+// DataModel returns a data model object for the redirect.
+func (r *Redirect) DataModel() *datamodel.Redirect {
+	dm := &datamodel.Redirect{
+		ID: r.id,
+		SourcePath: r.source,
+		TargetPath: r.target,
+		StatusCode: r.statusCode,
+		CreatedAt: r.createdAt,
+	}
+
+	if r.pageID != nil {
+		dm.PageID = sql.NullString{
+			Valid: true,
+			String: *r.pageID,
+		}
+	}
+
+	return dm
+}
+
+// RedirectFromDataModel returns a new instance of Redirect, populated with
+// the data from the data model. This should only be used by repositories.
+func RedirectFromDataModel(dm *datamodel.Redirect) *Redirect {
+	r := &Redirect{
+		id: dm.ID,
+		source: dm.SourcePath,
+		target: dm.TargetPath,
+		statusCode: dm.StatusCode,
+		createdAt: dm.CreatedAt,
+	}
+
+	if dm.PageID.Valid {
+		r.pageID = &dm.PageID.String
+	}
+
+	return r
+}
@@ -0,0 +1,80 @@
+package model
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/reecerussell/distro-blog/domain/dto"
+)
+
+func TestNewRedirect(t *testing.T) {
+	r, err := NewRedirect(&dto.CreateRedirect{
+		Source: " /Old-Page ",
+		Target: "/new-page",
+	})
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	d := r.DTO()
+	if d.Source != "/old-page" {
+		t.Errorf("expected the source to be '/old-page' but got '%s'", d.Source)
+	}
+
+	if d.StatusCode != http.StatusMovedPermanently {
+		t.Errorf("expected a default status code of 301 but got %d", d.StatusCode)
+	}
+}
+
+func TestNewRedirectWithInvalidData(t *testing.T) {
+	tests := map[string]*dto.CreateRedirect{
+		"no source": {Target: "/new"},
+		"relative source": {Source: "old", Target: "/new"},
+		"source with query": {Source: "/old?a=b", Target: "/new"},
+		"relative target": {Source: "/old", Target: "new"},
+		"self": {Source: "/old", Target: "/OLD"},
+		"status code": {Source: "/old", Target: "/new", StatusCode: http.StatusOK},
+	}
+
+	for name, d := range tests {
+		_, err := NewRedirect(d)
+		if err == nil {
+			t.Errorf("%s: expected an error but got nil", name)
+		}
+	}
+}
+
+func TestNewRedirectWithExternalTarget(t *testing.T) {
+	_, err := NewRedirect(&dto.CreateRedirect{
+		Source: "/old",
+		Target: "https://example.com/new",
+		StatusCode: http.StatusFound,
+	})
+	if err != nil {
+		t.Errorf("expected no error but got: %v", err)
+	}
+}
+
+func TestRedirect_Follow(t *testing.T) {
+	a, _ := NewRedirect(&dto.CreateRedirect{Source: "/a", Target: "/b"})
+	b, _ := NewRedirect(&dto.CreateRedirect{Source: "/b", Target: "/c"})
+
+	err := a.Follow(b)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if a.Target() != "/c" {
+		t.Errorf("expected the target to be '/c' but got '%s'", a.Target())
+	}
+}
+
+func TestRedirect_FollowCreatingLoop(t *testing.T) {
+	a, _ := NewRedirect(&dto.CreateRedirect{Source: "/a", Target: "/b"})
+	b, _ := NewRedirect(&dto.CreateRedirect{Source: "/b", Target: "/a"})
+
+	err := a.Follow(b)
+	if err == nil {
+		t.Errorf("expected an error but got nil")
+	}
+}
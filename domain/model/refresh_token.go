@@ -0,0 +1,164 @@
+package model
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/reecerussell/distro-blog/domain/datamodel"
+)
+
+// RefreshTokenLifetime is how long a refresh token can be used for. Each time
+// a token is used it's replaced, so users stay signed in while they're active.
+const RefreshTokenLifetime = time.Hour * 24 * 30
+
+// Errors returned when a refresh token can't be used.
+var (
+	ErrRefreshTokenExpired = errors.New("refresh token has expired")
+	ErrRefreshTokenReused = errors.New("refresh token has already been used or revoked")
+)
+
+// RefreshToken is a long-lived token used to get new access tokens. Refresh tokens
+// are rotated each time they're used, and each token belongs to a family, which starts
+// when the user signs in. If a token is used more than once, it's likely to have been
+// stolen, so the whole family should be revoked.
+//
+// Only a hash of the token is stored, so the token can't be read from the data source.
+type RefreshToken struct {
+	id string
+	userID string
+	familyID string
+	tokenHash string
+	mfaVerified bool
+	expiresAt time.Time
+	usedAt *time.Time
+	revokedAt *time.Time
+}
+
+// NewRefreshToken starts a new family of refresh tokens for the user, returning
+// the first token in the family and its value, which should be given to the user.
+// mfaVerified is whether the user signed in using two-factor authentication, which
+// applies to every token in the family.
+func NewRefreshToken(userID string, mfaVerified bool, now time.Time) (*RefreshToken, string, error) {
+	return newRefreshToken(userID, uuid.New().String(), mfaVerified, now)
+}
+
+func newRefreshToken(userID, familyID string, mfaVerified bool, now time.Time) (*RefreshToken, string, error) {
+	b := make([]byte, 32)
+	_, err := rand.Read(b)
+	if err != nil {
+		return nil, "", err
+	}
+
+	value := base64.RawURLEncoding.EncodeToString(b)
+	t := &RefreshToken{
+		id: uuid.New().String(),
+		userID: userID,
+		familyID: familyID,
+		tokenHash: HashRefreshToken(value),
+		mfaVerified: mfaVerified,
+		expiresAt: now.Add(RefreshTokenLifetime),
+	}
+
+	return t, value, nil
+}
+
+// HashRefreshToken returns the hash of a refresh token's value, used to look it up.
+func HashRefreshToken(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
+
+// UserID returns the id of the user the token was issued to.
+func (t *RefreshToken) UserID() string {
+	return t.userID
+}
+
+// FamilyID returns the id of the family the token belongs to.
+func (t *RefreshToken) FamilyID() string {
+	return t.familyID
+}
+
+// MFAVerified returns whether the family of tokens was started by the user
+// signing in using two-factor authentication.
+func (t *RefreshToken) MFAVerified() bool {
+	return t.mfaVerified
+}
+
+// ExpiresAt returns the time the token expires at.
+func (t *RefreshToken) ExpiresAt() time.Time {
+	return t.expiresAt
+}
+
+// Rotate marks the token as used, returning the token which replaces it and its value.
+// ErrRefreshTokenReused is returned if the token has already been used or revoked.
+func (t *RefreshToken) Rotate(now time.Time) (*RefreshToken, string, error) {
+	if t.usedAt != nil || t.revokedAt != nil {
+		return nil, "", ErrRefreshTokenReused
+	}
+
+	if !now.Before(t.expiresAt) {
+		return nil, "", ErrRefreshTokenExpired
+	}
+
+	next, value, err := newRefreshToken(t.userID, t.familyID, t.mfaVerified, now)
+	if err != nil {
+		return nil, "", err
+	}
+
+	t.usedAt = &now
+
+	return next, value, nil
+}
+
+// DataModel returns a data model object for the refresh token.
+func (t *RefreshToken) DataModel() *datamodel.RefreshToken {
+	dm := &datamodel.RefreshToken{
+		ID: t.id,
+		UserID: t.userID,
+		FamilyID: t.familyID,
+		TokenHash: t.tokenHash,
+		MFAVerified: t.mfaVerified,
+		ExpiresAt: t.expiresAt,
+	}
+
+	if t.usedAt != nil {
+		dm.UsedAt.Valid = true
+		dm.UsedAt.Time = *t.usedAt
+	}
+
+	if t.revokedAt != nil {
+		dm.RevokedAt.Valid = true
+		dm.RevokedAt.Time = *t.revokedAt
+	}
+
+	return dm
+}
+
+// RefreshTokenFromDataModel returns a new instance of RefreshToken, populated with
+// the data from the data model. This should only be used by repositories.
+func RefreshTokenFromDataModel(dm *datamodel.RefreshToken) *RefreshToken {
+	t := &RefreshToken{
+		id: dm.ID,
+		userID: dm.UserID,
+		familyID: dm.FamilyID,
+		tokenHash: dm.TokenHash,
+		mfaVerified: dm.MFAVerified,
+		expiresAt: dm.ExpiresAt,
+	}
+
+	if dm.UsedAt.Valid {
+		t.usedAt = &dm.UsedAt.Time
+	}
+
+	if dm.RevokedAt.Valid {
+		t.revokedAt = &dm.RevokedAt.Time
+	}
+
+	return t
+}
@@ -0,0 +1,39 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRefreshToken_Rotate(t *testing.T) {
+	now := time.Now().UTC()
+	first, value, err := NewRefreshToken("user-1", true, now)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if first.DataModel().TokenHash != HashRefreshToken(value) || first.DataModel().TokenHash == value {
+		t.Errorf("expected only the hash of the token to be stored")
+	}
+
+	next, nextValue, err := first.Rotate(now.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if next.FamilyID() != first.FamilyID() || next.UserID() != "user-1" || !next.MFAVerified() || nextValue == value {
+		t.Errorf("expected a new token in the same family")
+	}
+
+	if !first.DataModel().UsedAt.Valid {
+		t.Errorf("expected the token to be marked as used")
+	}
+
+	if _, _, err = first.Rotate(now.Add(time.Minute)); err != ErrRefreshTokenReused {
+		t.Errorf("expected '%v' but got: %v", ErrRefreshTokenReused, err)
+	}
+
+	if _, _, err = next.Rotate(now.Add(RefreshTokenLifetime * 2)); err != ErrRefreshTokenExpired {
+		t.Errorf("expected '%v' but got: %v", ErrRefreshTokenExpired, err)
+	}
+}
@@ -0,0 +1,241 @@
+package model
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/reecerussell/distro-blog/domain/datamodel"
+	"github.com/reecerussell/distro-blog/domain/dto"
+)
+
+// MaxSeriesPages is the maximum number of blogs in a single series.
+const MaxSeriesPages = 100
+
+// Series is an ordered sequence of blogs, such as the parts of a tutorial.
+// A blog can only be part of one series.
+type Series struct {
+	id string
+	title string
+	description *string
+	pages []*seriesPage
+}
+
+type seriesPage struct {
+	pageID string
+	title string
+	path string
+	isActive bool
+}
+
+// NewSeries creates a new, empty, series with the given data.
+func NewSeries(d *dto.CreateSeries) (*Series, error) {
+	s := &Series{
+		id: uuid.New().String(),
+	}
+
+	err := s.update(d.Title, d.Description)
+	if err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// GetID returns the series' id.
+func (s *Series) GetID() string {
+	return s.id
+}
+
+// PageIDs returns the ids of the blogs in the series, in order.
+func (s *Series) PageIDs() []string {
+	ids := make([]string, len(s.pages))
+	for i, p := range s.pages {
+		ids[i] = p.pageID
+	}
+
+	return ids
+}
+
+// Update updates the title and description of the series.
+func (s *Series) Update(d *dto.UpdateSeries) error {
+	return s.update(d.Title, d.Description)
+}
+
+func (s *Series) update(title string, description *string) error {
+	title = strings.TrimSpace(title)
+	l := len(title)
+
+	switch true {
+	case l < 1:
+		return fmt.Errorf("title is required")
+	case l > 255:
+		return fmt.Errorf("title cannot be greater than 255 characters long")
+	}
+
+	var desc *string
+	if description != nil && strings.TrimSpace(*description) != "" {
+		v := strings.TrimSpace(*description)
+		if len(v) > 1000 {
+			return fmt.Errorf("description cannot be greater than 1000 characters long")
+		}
+
+		desc = &v
+	}
+
+	s.title = title
+	s.description = desc
+
+	return nil
+}
+
+// indexOf returns the index of the page in the series, or -1.
+func (s *Series) indexOf(pageID string) int {
+	for i, p := range s.pages {
+		if p.pageID == pageID {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// AddPage adds the blog to the end of the series. Only blogs can be added
+// to a series, and each blog can only appear in the series once.
+func (s *Series) AddPage(p *Page) error {
+	switch true {
+	case !p.IsBlog():
+		return fmt.Errorf("only blogs can be added to a series")
+	case p.IsTrashed():
+		return fmt.Errorf("blogs in the trash cannot be added to a series")
+	case s.indexOf(p.GetID()) > -1:
+		return fmt.Errorf("the blog is already part of this series")
+	case len(s.pages) >= MaxSeriesPages:
+		return fmt.Errorf("a series cannot have more than %d blogs", MaxSeriesPages)
+	}
+
+	s.pages = append(s.pages, &seriesPage{
+		pageID: p.GetID(),
+		title: p.Title(),
+		path: p.Path(),
+		isActive: p.IsActive(),
+	})
+
+	return nil
+}
+
+// RemovePage removes the blog from the series, moving the blogs after it up.
+func (s *Series) RemovePage(pageID string) error {
+	i := s.indexOf(pageID)
+	if i < 0 {
+		return fmt.Errorf("the blog is not part of this series")
+	}
+
+	s.pages = append(s.pages[:i], s.pages[i+1:]...)
+
+	return nil
+}
+
+// Reorder changes the order of the blogs in the series. The given ids
+// must contain each of the blogs in the series exactly once.
+func (s *Series) Reorder(pageIDs []string) error {
+	if len(pageIDs) != len(s.pages) {
+		return fmt.Errorf("the order must contain each of the %d blogs in the series", len(s.pages))
+	}
+
+	pages := make([]*seriesPage, len(pageIDs))
+	for i, id := range pageIDs {
+		j := s.indexOf(id)
+		if j < 0 {
+			return fmt.Errorf("the blog '%s' is not part of this series", id)
+		}
+
+		for _, p := range pages[:i] {
+			if p.pageID == id {
+				return fmt.Errorf("the blog '%s' appears more than once", id)
+			}
+		}
+
+		pages[i] = s.pages[j]
+	}
+
+	s.pages = pages
+
+	return nil
+}
+
+// DTO returns a *dto.Series for the series, including its blogs.
+func (s *Series) DTO() *dto.Series {
+	d := &dto.Series{
+		ID: s.id,
+		Title: s.title,
+		Description: s.description,
+		Pages: make([]*dto.SeriesPage, len(s.pages)),
+	}
+
+	for i, p := range s.pages {
+		d.Pages[i] = &dto.SeriesPage{
+			ID: p.pageID,
+			Title: p.title,
+			Path: p.path,
+			IsActive: p.isActive,
+			Position: i + 1,
+		}
+	}
+
+	return d
+}
+
+// DataModel returns a data model object for the series.
+func (s *Series) DataModel() *datamodel.Series {
+	dm := &datamodel.Series{
+		ID: s.id,
+		Title: s.title,
+		Pages: make([]*datamodel.SeriesPage, len(s.pages)),
+	}
+
+	if s.description != nil {
+		dm.Description = sql.NullString{
+			Valid: true,
+			String: *s.description,
+		}
+	}
+
+	for i, p := range s.pages {
+		dm.Pages[i] = &datamodel.SeriesPage{
+			PageID: p.pageID,
+			Title: p.title,
+			Path: p.path,
+			IsActive: p.isActive,
+		}
+	}
+
+	return dm
+}
+
+// SeriesFromDataModel returns a new instance of Series, populated with
+// the data from the data model. This should only be used by repositories.
+func SeriesFromDataModel(dm *datamodel.Series) *Series {
+	s := &Series{
+		id: dm.ID,
+		title: dm.Title,
+		pages: make([]*seriesPage, len(dm.Pages)),
+	}
+
+	if dm.Description.Valid {
+		s.description = &dm.Description.String
+	}
+
+	for i, p := range dm.Pages {
+		s.pages[i] = &seriesPage{
+			pageID: p.PageID,
+			title: p.Title,
+			path: p.Path,
+			isActive: p.IsActive,
+		}
+	}
+
+	return s
+}
@@ -0,0 +1,124 @@
+package model
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/reecerussell/distro-blog/domain/datamodel"
+	"github.com/reecerussell/distro-blog/domain/dto"
+)
+
+func testSeriesBlog(id string) *Page {
+	return PageFromDataModel(&datamodel.Page{ID: id, Title: "Part " + id, URL: "blog/" + id, IsBlog: true, IsActive: true})
+}
+
+func TestNewSeries(t *testing.T) {
+	desc := "  "
+	s, err := NewSeries(&dto.CreateSeries{Title: "  Building a blog  ", Description: &desc})
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	d := s.DTO()
+	if d.Title != "Building a blog" {
+		t.Errorf("expected title '%s' but got '%s'", "Building a blog", d.Title)
+	}
+
+	if d.Description != nil {
+		t.Errorf("expected an empty description to be nil but got '%s'", *d.Description)
+	}
+
+	tests := map[string]string{
+		"empty title": "   ",
+		"long title": strings.Repeat("a", 256),
+	}
+
+	for name, title := range tests {
+		_, err := NewSeries(&dto.CreateSeries{Title: title})
+		if err == nil {
+			t.Errorf("%s: expected an error but got nil", name)
+		}
+	}
+}
+
+func TestSeries_AddPage(t *testing.T) {
+	s, _ := NewSeries(&dto.CreateSeries{Title: "Building a blog"})
+
+	err := s.AddPage(testSeriesBlog("1"))
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if err = s.AddPage(testSeriesBlog("1")); err == nil {
+		t.Errorf("expected an error adding a blog twice but got nil")
+	}
+
+	page := PageFromDataModel(&datamodel.Page{ID: "2", URL: "about"})
+	if err = s.AddPage(page); err == nil {
+		t.Errorf("expected an error adding a page which isn't a blog but got nil")
+	}
+
+	trashed := PageFromDataModel(&datamodel.Page{ID: "3", URL: "blog/3", IsBlog: true, DeletedAt: sql.NullTime{Valid: true, Time: time.Now()}})
+	if err = s.AddPage(trashed); err == nil {
+		t.Errorf("expected an error adding a trashed blog but got nil")
+	}
+
+	d := s.DTO()
+	if len(d.Pages) != 1 || d.Pages[0].ID != "1" || d.Pages[0].Position != 1 || d.Pages[0].Title != "Part 1" {
+		t.Errorf("expected the series to contain only blog '1' but got %v", d.Pages)
+	}
+}
+
+func TestSeries_RemovePage(t *testing.T) {
+	s, _ := NewSeries(&dto.CreateSeries{Title: "Building a blog"})
+	for _, id := range []string{"1", "2", "3"} {
+		_ = s.AddPage(testSeriesBlog(id))
+	}
+
+	err := s.RemovePage("2")
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if ids := strings.Join(s.PageIDs(), ","); ids != "1,3" {
+		t.Errorf("expected '1,3' but got '%s'", ids)
+	}
+
+	if p := s.DTO().Pages[1]; p.Position != 2 {
+		t.Errorf("expected blog '3' to move up to position 2 but got %d", p.Position)
+	}
+
+	if err = s.RemovePage("2"); err == nil {
+		t.Errorf("expected an error removing a blog which isn't in the series but got nil")
+	}
+}
+
+func TestSeries_Reorder(t *testing.T) {
+	s, _ := NewSeries(&dto.CreateSeries{Title: "Building a blog"})
+	for _, id := range []string{"1", "2", "3"} {
+		_ = s.AddPage(testSeriesBlog(id))
+	}
+
+	tests := map[string][]string{
+		"missing blog": {"3", "1"},
+		"unknown blog": {"3", "1", "4"},
+		"duplicate blog": {"3", "1", "1"},
+	}
+
+	for name, ids := range tests {
+		if err := s.Reorder(ids); err == nil {
+			t.Errorf("%s: expected an error but got nil", name)
+		}
+	}
+
+	err := s.Reorder([]string{"3", "1", "2"})
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if ids := strings.Join(s.PageIDs(), ","); ids != "3,1,2" {
+		t.Errorf("expected '3,1,2' but got '%s'", ids)
+	}
+}
@@ -3,15 +3,26 @@ package model
 import (
 	"database/sql"
 	"fmt"
+	"regexp"
+	"strings"
 	"github.com/reecerussell/distro-blog/domain/datamodel"
 	"github.com/reecerussell/distro-blog/domain/dto"
+	"github.com/reecerussell/distro-blog/libraries/locale"
 )
 
 const (
 	SettingSiteName = "SITE_NAME"
 	SettingTitleFormat = "TITLE_FORMAT"
+	SettingRobotsDisallow = "ROBOTS_DISALLOW"
+	SettingDefaultLocale = "DEFAULT_LOCALE"
+	SettingMFARequiredScopes = "MFA_REQUIRED_SCOPES"
 )
 
+// DefaultLocale is the locale of pages' own content, if the DEFAULT_LOCALE setting is empty.
+const DefaultLocale = "en"
+
+var scopeNameRegex = regexp.MustCompile(`^[a-z]+:[a-z]+$`)
+
 type Setting struct {
 	key string
 	value *string
@@ -23,6 +34,12 @@ func (s *Setting) Update(value *string) error {
 		return s.updateSiteName(value)
 	case SettingTitleFormat:
 		return s.updateTitleFormat(value)
+	case SettingRobotsDisallow:
+		return s.updateRobotsDisallow(value)
+	case SettingDefaultLocale:
+		return s.updateDefaultLocale(value)
+	case SettingMFARequiredScopes:
+		return s.updateMFARequiredScopes(value)
 	default:
 		s.value = value
 		return nil
@@ -57,6 +74,107 @@ func (s *Setting) updateTitleFormat(format *string) error {
 	return nil
 }
 
+// updateRobotsDisallow sets the paths which crawlers should not visit,
+// separated by either commas or new lines.
+func (s *Setting) updateRobotsDisallow(paths *string) error {
+	if paths == nil || strings.TrimSpace(*paths) == "" {
+		s.value = nil
+		return nil
+	}
+
+	if len(*paths) > 255 {
+		return fmt.Errorf("disallowed paths cannot be greater than 255 characters long")
+	}
+
+	for _, p := range splitPaths(*paths) {
+		if !strings.HasPrefix(p, "/") {
+			return fmt.Errorf("disallowed path '%s' must start with a '/'", p)
+		}
+	}
+
+	s.value = paths
+
+	return nil
+}
+
+// updateDefaultLocale sets the locale pages are written in, which
+// is the locale their content is served in without a translation.
+func (s *Setting) updateDefaultLocale(value *string) error {
+	if value == nil || *value == "" {
+		return fmt.Errorf("default locale can not be empty")
+	}
+
+	l, err := locale.Normalize(*value)
+	if err != nil {
+		return err
+	}
+
+	s.value = &l
+
+	return nil
+}
+
+// updateMFARequiredScopes sets the scopes which users can only be granted once they've
+// enabled two-factor authentication, separated by either commas or new lines.
+func (s *Setting) updateMFARequiredScopes(scopes *string) error {
+	if scopes == nil || strings.TrimSpace(*scopes) == "" {
+		s.value = nil
+		return nil
+	}
+
+	if len(*scopes) > 255 {
+		return fmt.Errorf("scopes cannot be greater than 255 characters long")
+	}
+
+	for _, sc := range splitPaths(*scopes) {
+		if !scopeNameRegex.MatchString(sc) {
+			return fmt.Errorf("'%s' is not a valid scope", sc)
+		}
+	}
+
+	s.value = scopes
+
+	return nil
+}
+
+// MFARequiredScopes returns the scopes of an MFA required scopes setting.
+func (s *Setting) MFARequiredScopes() []string {
+	if s.value == nil {
+		return nil
+	}
+
+	return splitPaths(*s.value)
+}
+
+// DefaultLocale returns the value of a default locale setting, or DefaultLocale if it's empty.
+func (s *Setting) DefaultLocale() string {
+	if s.value == nil || *s.value == "" {
+		return DefaultLocale
+	}
+
+	return *s.value
+}
+
+// RobotsDisallow returns the paths of a robots disallow setting.
+func (s *Setting) RobotsDisallow() []string {
+	if s.value == nil {
+		return nil
+	}
+
+	return splitPaths(*s.value)
+}
+
+func splitPaths(value string) []string {
+	var paths []string
+	for _, p := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == '\n' }) {
+		if p = strings.TrimSpace(p); p != "" {
+			paths = append(paths, p)
+		}
+	}
+
+	return paths
+}
+
 func (s *Setting) DTO() *dto.Setting {
 	return &dto.Setting{
 		Key: s.key,
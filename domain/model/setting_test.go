@@ -0,0 +1,82 @@
+package model
+
+import (
+	"testing"
+)
+
+func TestSetting_UpdateRobotsDisallow(t *testing.T) {
+	s := &Setting{key: SettingRobotsDisallow}
+
+	paths := "/admin,\n /private \n"
+	err := s.Update(&paths)
+	if err != nil {
+		t.Errorf("expected no error but got: %v", err)
+	}
+
+	p := s.RobotsDisallow()
+	if len(p) != 2 || p[0] != "/admin" || p[1] != "/private" {
+		t.Errorf("expected [/admin /private] but got %v", p)
+	}
+}
+
+func TestSetting_UpdateRobotsDisallowWithInvalidPath(t *testing.T) {
+	s := &Setting{key: SettingRobotsDisallow}
+
+	paths := "/admin\nprivate"
+	err := s.Update(&paths)
+	if err == nil {
+		t.Errorf("expected an error but got nil")
+	}
+}
+
+func TestSetting_UpdateRobotsDisallowWithEmptyValue(t *testing.T) {
+	s := &Setting{key: SettingRobotsDisallow}
+
+	paths := "  "
+	err := s.Update(&paths)
+	if err != nil {
+		t.Errorf("expected no error but got: %v", err)
+	}
+
+	if s.DTO().Value != nil {
+		t.Errorf("expected the value to be cleared")
+	}
+}
+
+func TestSetting_UpdateDefaultLocale(t *testing.T) {
+	s := &Setting{key: SettingDefaultLocale}
+
+	l := "en_GB"
+	err := s.Update(&l)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if v := s.DefaultLocale(); v != "en-gb" {
+		t.Errorf("expected 'en-gb' but got '%s'", v)
+	}
+
+	l = "english"
+	if err = s.Update(&l); err == nil {
+		t.Errorf("expected an error but got nil")
+	}
+}
+
+func TestSetting_UpdateMFARequiredScopes(t *testing.T) {
+	s := &Setting{key: SettingMFARequiredScopes}
+
+	scopes := "users:write, settings:write\n"
+	err := s.Update(&scopes)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if v := s.MFARequiredScopes(); len(v) != 2 || v[0] != "users:write" || v[1] != "settings:write" {
+		t.Errorf("expected 'users:write' and 'settings:write' but got %v", v)
+	}
+
+	scopes = "users:write,admin"
+	if err = s.Update(&scopes); err == nil {
+		t.Errorf("expected an error but got nil")
+	}
+}
@@ -0,0 +1,173 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/reecerussell/distro-blog/domain/datamodel"
+	"github.com/reecerussell/distro-blog/domain/dto"
+)
+
+// Supported types of taxonomy term.
+const (
+	TermTypeTag = "tag"
+	TermTypeCategory = "category"
+)
+
+var allowedTermTypes = [...]string{TermTypeTag, TermTypeCategory}
+
+// Term is a tag or category which blogs can be grouped by.
+type Term struct {
+	id string
+	termType string
+	name string
+	slug string
+}
+
+// NewTerm creates a new tag or category with the given data.
+func NewTerm(d *dto.CreateTerm) (*Term, error) {
+	t := &Term{
+		id: uuid.New().String(),
+	}
+
+	err := t.updateType(d.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	err = t.Rename(d.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// GetID returns the term's id.
+func (t *Term) GetID() string {
+	return t.id
+}
+
+// Type returns the term's type, either a tag or category.
+func (t *Term) Type() string {
+	return t.termType
+}
+
+// Slug returns the url safe version of the term's name.
+func (t *Term) Slug() string {
+	return t.slug
+}
+
+// IsValidTermType returns true if termType is a supported type of term.
+func IsValidTermType(termType string) bool {
+	for _, s := range allowedTermTypes {
+		if termType == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (t *Term) updateType(termType string) error {
+	lt := strings.ToLower(termType)
+	if !IsValidTermType(lt) {
+		return fmt.Errorf("type '%s' is not valid", termType)
+	}
+
+	t.termType = lt
+
+	return nil
+}
+
+// Rename updates the term's name, and the slug derived from it.
+//
+// The name cannot be empty or greater than 255 characters long, and
+// must contain at least one character which can be used in a url.
+func (t *Term) Rename(name string) error {
+	name = strings.TrimSpace(name)
+	l := len(name)
+
+	switch true {
+	case l < 1:
+		return fmt.Errorf("name is required")
+	case l > 255:
+		return fmt.Errorf("name cannot be greater than 255 characters long")
+	}
+
+	slug := slugify(name)
+	if slug == "" {
+		return fmt.Errorf("name must contain at least one letter or number")
+	}
+
+	t.name = name
+	t.slug = slug
+
+	return nil
+}
+
+// CanMergeInto returns an error if the term cannot be merged into target.
+// Terms can only be merged into a different term of the same type.
+func (t *Term) CanMergeInto(target *Term) error {
+	if t.id == target.id {
+		return fmt.Errorf("cannot merge a term into itself")
+	}
+
+	if t.termType != target.termType {
+		return fmt.Errorf("cannot merge a %s into a %s", t.termType, target.termType)
+	}
+
+	return nil
+}
+
+// slugify converts s into a lowercase, url safe value, replacing
+// any runs of disallowed characters with a single hyphen.
+func slugify(s string) string {
+	var b strings.Builder
+	hyphen := false
+
+	for _, c := range strings.Split(strings.ToLower(s), "") {
+		if _, ok := urlSafeCharMap[c]; ok && c != "-" {
+			b.WriteString(c)
+			hyphen = false
+		} else if !hyphen && b.Len() > 0 {
+			b.WriteString("-")
+			hyphen = true
+		}
+	}
+
+	return strings.TrimSuffix(b.String(), "-")
+}
+
+// DTO returns a *dto.Term for the term.
+func (t *Term) DTO() *dto.Term {
+	return &dto.Term{
+		ID: t.id,
+		Type: t.termType,
+		Name: t.name,
+		Slug: t.slug,
+	}
+}
+
+// DataModel returns a data model object for the term.
+func (t *Term) DataModel() *datamodel.Term {
+	return &datamodel.Term{
+		ID: t.id,
+		Type: t.termType,
+		Name: t.name,
+		Slug: t.slug,
+	}
+}
+
+// TermFromDataModel returns a new instance of Term, populated with
+// the data from the data model. This should only be used by repositories.
+func TermFromDataModel(dm *datamodel.Term) *Term {
+	return &Term{
+		id: dm.ID,
+		termType: dm.Type,
+		name: dm.Name,
+		slug: dm.Slug,
+	}
+}
@@ -0,0 +1,63 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/reecerussell/distro-blog/domain/dto"
+)
+
+func TestNewTerm(t *testing.T) {
+	term, err := NewTerm(&dto.CreateTerm{
+		Type: "Tag",
+		Name: "  Go & AWS Lambda!  ",
+	})
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	d := term.DTO()
+	if d.Type != TermTypeTag {
+		t.Errorf("expected type '%s' but got '%s'", TermTypeTag, d.Type)
+	}
+
+	if d.Name != "Go & AWS Lambda!" {
+		t.Errorf("expected name '%s' but got '%s'", "Go & AWS Lambda!", d.Name)
+	}
+
+	if d.Slug != "go-aws-lambda" {
+		t.Errorf("expected slug '%s' but got '%s'", "go-aws-lambda", d.Slug)
+	}
+}
+
+func TestNewTermWithInvalidData(t *testing.T) {
+	tests := map[string]*dto.CreateTerm{
+		"invalid type": {Type: "label", Name: "Go"},
+		"empty name": {Type: TermTypeTag, Name: "   "},
+		"no slug": {Type: TermTypeTag, Name: "!!!"},
+	}
+
+	for name, d := range tests {
+		_, err := NewTerm(d)
+		if err == nil {
+			t.Errorf("%s: expected an error but got nil", name)
+		}
+	}
+}
+
+func TestTerm_CanMergeInto(t *testing.T) {
+	tag, _ := NewTerm(&dto.CreateTerm{Type: TermTypeTag, Name: "golang"})
+	other, _ := NewTerm(&dto.CreateTerm{Type: TermTypeTag, Name: "Go"})
+	category, _ := NewTerm(&dto.CreateTerm{Type: TermTypeCategory, Name: "Go"})
+
+	if err := tag.CanMergeInto(other); err != nil {
+		t.Errorf("expected no error but got: %v", err)
+	}
+
+	if err := tag.CanMergeInto(tag); err == nil {
+		t.Errorf("expected an error merging a term into itself")
+	}
+
+	if err := tag.CanMergeInto(category); err == nil {
+		t.Errorf("expected an error merging a tag into a category")
+	}
+}
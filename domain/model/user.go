@@ -2,16 +2,21 @@ package model
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
 	"fmt"
 	"github.com/reecerussell/distro-blog/domain/event"
 	"github.com/reecerussell/distro-blog/domain/handler"
 	"github.com/reecerussell/distro-blog/libraries/contextkey"
 	"github.com/reecerussell/distro-blog/libraries/domainevents"
+	"github.com/reecerussell/distro-blog/libraries/logging"
 	"github.com/reecerussell/distro-blog/libraries/result"
-	"math/rand"
 	"net/http"
+	"net/url"
 	"regexp"
+	"strings"
 	"time"
+	"unicode"
 
 	"github.com/google/uuid"
 
@@ -27,6 +32,14 @@ const (
 	AuditUserUpdated = "USER_UPDATED"
 	AuditUserPasswordReset = "USER_PASSWORD_RESET"
 	AuditUserPasswordChanged = "USER_PASSWORD_CHANGED"
+	AuditUserProfileUpdated = "USER_PROFILE_UPDATED"
+)
+
+// Limits of a user's public profile.
+const (
+	MaxDisplayNameLength = 100
+	MaxBioLength = 1000
+	MaxSocialLinks = 10
 )
 
 func init() {
@@ -43,6 +56,10 @@ type User struct {
 	email           string
 	normalizedEmail string
 	passwordHash    string
+	displayName     *string
+	bio             *string
+	avatarImageID   *string
+	socialLinks     []*dto.SocialLink
 
 	scopes []*Scope
 }
@@ -102,6 +119,21 @@ func (u *User) NormalizedEmail() string {
 	return u.normalizedEmail
 }
 
+// DisplayName returns the name shown on the user's posts and profile,
+// which defaults to the user's full name, if a display name isn't set.
+func (u *User) DisplayName() string {
+	if u.displayName != nil {
+		return *u.displayName
+	}
+
+	return u.firstname + " " + u.lastname
+}
+
+// AvatarImageID returns the id of the user's avatar image, or nil.
+func (u *User) AvatarImageID() *string {
+	return u.avatarImageID
+}
+
 // Scopes returns the user's scopes.
 func (u *User) Scopes() []*Scope {
 	return u.scopes
@@ -209,6 +241,111 @@ func (u *User) ChangePassword(ctx context.Context, d *dto.ChangePassword, svc pa
 	return result.Ok()
 }
 
+// UpdateProfile updates the user's public profile, which is shown on
+// the pages they author. Empty values clear the respective field.
+func (u *User) UpdateProfile(ctx context.Context, d *dto.UpdateUserProfile) error {
+	beforeUpdate := u.DTO()
+
+	err := u.UpdateDisplayName(d.DisplayName)
+	if err != nil {
+		return err
+	}
+
+	err = u.UpdateBio(d.Bio)
+	if err != nil {
+		return err
+	}
+
+	err = u.UpdateSocialLinks(d.SocialLinks)
+	if err != nil {
+		return err
+	}
+
+	u.avatarImageID = nil
+	if d.AvatarImageID != nil && *d.AvatarImageID != "" {
+		id := *d.AvatarImageID
+		u.avatarImageID = &id
+	}
+
+	u.AddAudit(AuditUserProfileUpdated, u.getPerformingUserID(ctx), beforeUpdate, u.DTO())
+
+	return nil
+}
+
+// UpdateDisplayName updates the user's display name. A nil or empty
+// name clears it, so the user's full name is displayed instead.
+func (u *User) UpdateDisplayName(name *string) error {
+	if name == nil || strings.TrimSpace(*name) == "" {
+		u.displayName = nil
+		return nil
+	}
+
+	v := strings.TrimSpace(*name)
+
+	switch true {
+	case len([]rune(v)) > MaxDisplayNameLength:
+		return fmt.Errorf("display name cannot be greater than %d characters long", MaxDisplayNameLength)
+	case strings.IndexFunc(v, unicode.IsControl) > -1:
+		return fmt.Errorf("display name cannot contain control characters")
+	}
+
+	u.displayName = &v
+
+	return nil
+}
+
+// UpdateBio updates the user's biography. A nil or empty bio clears it.
+func (u *User) UpdateBio(bio *string) error {
+	if bio == nil || strings.TrimSpace(*bio) == "" {
+		u.bio = nil
+		return nil
+	}
+
+	v := strings.TrimSpace(*bio)
+	if len([]rune(v)) > MaxBioLength {
+		return fmt.Errorf("bio cannot be greater than %d characters long", MaxBioLength)
+	}
+
+	u.bio = &v
+
+	return nil
+}
+
+// UpdateSocialLinks replaces the user's social links. Each link must
+// have a name and an absolute http or https url.
+func (u *User) UpdateSocialLinks(links []*dto.SocialLink) error {
+	if len(links) > MaxSocialLinks {
+		return fmt.Errorf("a user cannot have more than %d social links", MaxSocialLinks)
+	}
+
+	var socialLinks []*dto.SocialLink
+	for _, sl := range links {
+		link := &dto.SocialLink{
+			Name: strings.TrimSpace(sl.Name),
+			URL: strings.TrimSpace(sl.URL),
+		}
+
+		l := len(link.Name)
+		switch true {
+		case l < 1:
+			return fmt.Errorf("social link name is required")
+		case l > 45:
+			return fmt.Errorf("social link name cannot be greater than 45 characters long")
+		}
+
+		v, err := url.Parse(link.URL)
+		if err != nil || (v.Scheme != "http" && v.Scheme != "https") || v.Host == "" {
+			return fmt.Errorf("social link '%s' must have a valid http or https url", link.Name)
+		}
+
+		socialLinks = append(socialLinks, link)
+	}
+
+	u.socialLinks = socialLinks
+
+	return nil
+}
+
 // Sets the user's password after validating and hashing it.
 func (u *User) setPassword(password string, serv password.Service) error {
 	err := serv.Validate(password)
@@ -221,23 +358,17 @@ func (u *User) setPassword(password string, serv password.Service) error {
 	return nil
 }
 
-// ResetPassword is used to reset the user's password with a randomly generated string.
-// A string will be generated then set as the user's password. The new password will be returned.
-func (u *User) ResetPassword(ctx context.Context, scv password.Service) string {
-	chars := "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789@#*&!?£$"
-	rnd := rand.New(rand.NewSource(time.Now().UnixNano()))
-	b := make([]byte, 10)
-
-	for i := range b {
-		b[i] = chars[rnd.Intn(len(chars))]
+// ResetPassword sets a new password for the user, who has forgotten theirs,
+// after validating it. The reset must be authorized using a password reset token.
+func (u *User) ResetPassword(ctx context.Context, newPassword string, svc password.Service) error {
+	err := u.setPassword(newPassword, svc)
+	if err != nil {
+		return err
 	}
 
-	pwd := string(b)
-	u.passwordHash = scv.Hash(pwd)
-
 	u.AddAudit(AuditUserPasswordReset, u.getPerformingUserID(ctx), nil, nil)
 
-	return pwd
+	return nil
 }
 
 func (u *User) getPerformingUserID(ctx context.Context) string {
@@ -260,7 +391,7 @@ func (u *User) VerifyPassword(password string, serv password.Service) error {
 
 // DataModel returns a datamodel object for the User.
 func (u *User) DataModel() *datamodel.User {
-	return &datamodel.User{
+	dm := &datamodel.User{
 		ID:              u.id,
 		Firstname:       u.firstname,
 		Lastname:        u.lastname,
@@ -268,6 +399,37 @@ func (u *User) DataModel() *datamodel.User {
 		NormalizedEmail: u.normalizedEmail,
 		PasswordHash:    u.passwordHash,
 	}
+
+	if u.displayName != nil {
+		dm.DisplayName = sql.NullString{
+			Valid: true,
+			String: *u.displayName,
+		}
+	}
+
+	if u.bio != nil {
+		dm.Bio = sql.NullString{
+			Valid: true,
+			String: *u.bio,
+		}
+	}
+
+	if u.avatarImageID != nil {
+		dm.AvatarImageID = sql.NullString{
+			Valid: true,
+			String: *u.avatarImageID,
+		}
+	}
+
+	if u.socialLinks != nil {
+		links, _ := json.Marshal(u.socialLinks)
+		dm.SocialLinks = sql.NullString{
+			Valid: true,
+			String: string(links),
+		}
+	}
+
+	return dm
 }
 
 // UserFromDataModel returns a new instance of User populated with
@@ -280,7 +442,7 @@ func UserFromDataModel(dm *datamodel.User, sdm []*datamodel.UserScope) *User {
 		}
 	}
 
-	return &User{
+	u := &User{
 		id: dm.ID,
 		firstname: dm.Firstname,
 		lastname: dm.Lastname,
@@ -289,6 +451,27 @@ func UserFromDataModel(dm *datamodel.User, sdm []*datamodel.UserScope) *User {
 		passwordHash: dm.PasswordHash,
 		scopes: scopes,
 	}
+
+	if dm.DisplayName.Valid {
+		u.displayName = &dm.DisplayName.String
+	}
+
+	if dm.Bio.Valid {
+		u.bio = &dm.Bio.String
+	}
+
+	if dm.AvatarImageID.Valid {
+		u.avatarImageID = &dm.AvatarImageID.String
+	}
+
+	if dm.SocialLinks.Valid {
+		err := json.Unmarshal([]byte(dm.SocialLinks.String), &u.socialLinks)
+		if err != nil {
+			logging.Errorf("[USER:%s]: failed to read social links: %v\n", u.id, err)
+		}
+	}
+
+	return u
 }
 
 // DTO returns a dto.User populated with the user' data.
@@ -299,6 +482,21 @@ func (u *User) DTO() *dto.User {
 		Lastname:        u.lastname,
 		Email:           u.email,
 		NormalizedEmail: u.normalizedEmail,
+		DisplayName:     u.displayName,
+		Bio:             u.bio,
+		AvatarImageID:   u.avatarImageID,
+		SocialLinks:     u.socialLinks,
+	}
+}
+
+// Author returns the user's public profile, as a *dto.Author.
+func (u *User) Author() *dto.Author {
+	return &dto.Author{
+		ID: u.id,
+		Name: u.DisplayName(),
+		Bio: u.bio,
+		AvatarImageID: u.avatarImageID,
+		SocialLinks: u.socialLinks,
 	}
 }
 
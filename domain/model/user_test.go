@@ -1,6 +1,7 @@
 package model
 
 import (
+	"context"
 	"github.com/google/uuid"
 	"github.com/reecerussell/distro-blog/domain/datamodel"
 	"testing"
@@ -520,4 +521,94 @@ func TestUser_VerifyPassword(t *testing.T) {
 			t.Errorf("expected to fail")
 		}
 	})
-}
\ No newline at end of file
+}
+func TestUser_UpdateProfile(t *testing.T) {
+	u := &User{
+		id: "63",
+		firstname: "John",
+		lastname: "Doe",
+	}
+
+	name := " Johnny "
+	bio := "Writes about Go."
+	avatar := "image-1"
+	err := u.UpdateProfile(context.Background(), &dto.UpdateUserProfile{
+		DisplayName: &name,
+		Bio: &bio,
+		AvatarImageID: &avatar,
+		SocialLinks: []*dto.SocialLink{
+			{Name: "GitHub", URL: "https://github.com/johndoe"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if u.DisplayName() != "Johnny" {
+		t.Errorf("expected the display name to be 'Johnny' but got: %s", u.DisplayName())
+	}
+
+	a := u.Author()
+	if a.Bio == nil || *a.Bio != bio || a.AvatarImageID == nil || len(a.SocialLinks) != 1 {
+		t.Errorf("expected the author to have the profile's values but got: %v", a)
+	}
+
+	// clearing the display name falls back to the user's full name.
+	err = u.UpdateProfile(context.Background(), &dto.UpdateUserProfile{})
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if u.DisplayName() != "John Doe" {
+		t.Errorf("expected the display name to be 'John Doe' but got: %s", u.DisplayName())
+	}
+
+	if u.bio != nil || u.avatarImageID != nil || u.socialLinks != nil {
+		t.Errorf("expected the profile to be cleared")
+	}
+}
+
+func TestUser_UpdateSocialLinks(t *testing.T) {
+	tests := map[string]*dto.SocialLink{
+		"missing name": {URL: "https://github.com"},
+		"relative url": {Name: "GitHub", URL: "/johndoe"},
+		"javascript url": {Name: "GitHub", URL: "javascript:alert(1)"},
+	}
+
+	for name, l := range tests {
+		err := new(User).UpdateSocialLinks([]*dto.SocialLink{l})
+		if err == nil {
+			t.Errorf("%s: expected an error, but got none", name)
+		}
+	}
+
+	links := make([]*dto.SocialLink, MaxSocialLinks+1)
+	for i := range links {
+		links[i] = &dto.SocialLink{Name: "Site", URL: "https://example.com"}
+	}
+
+	err := new(User).UpdateSocialLinks(links)
+	if err == nil {
+		t.Errorf("too many links: expected an error, but got none")
+	}
+}
+
+func TestUser_DataModelProfile(t *testing.T) {
+	name := "Johnny"
+	u := &User{
+		id: "63",
+		displayName: &name,
+		socialLinks: []*dto.SocialLink{
+			{Name: "GitHub", URL: "https://github.com/johndoe"},
+		},
+	}
+
+	r := UserFromDataModel(u.DataModel(), nil)
+	if r.DisplayName() != name {
+		t.Errorf("expected the display name to be '%s' but got: %s", name, r.DisplayName())
+	}
+
+	if len(r.socialLinks) != 1 || r.socialLinks[0].URL != "https://github.com/johndoe" {
+		t.Errorf("expected the social links to be read from the data model but got: %v", r.socialLinks)
+	}
+}
@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/reecerussell/distro-blog/libraries/result"
+)
+
+// AuthorRepository is a high-level interface used to read and write
+// the authors of pages, and to list the posts written by an author.
+type AuthorRepository interface {
+	GetPageAuthors(ctx context.Context, pageID string) result.Result
+	SetPageAuthors(ctx context.Context, pageID string, userIDs []string) result.Result
+	ListPosts(ctx context.Context, userID string, limit, offset int) result.Result
+}
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/reecerussell/distro-blog/domain/model"
+	"github.com/reecerussell/distro-blog/libraries/result"
+)
+
+// MFARepository is used to manage users' two-factor authentication in a data source.
+type MFARepository interface {
+	Get(ctx context.Context, userID string) result.Result
+	Save(ctx context.Context, m *model.MFA) result.Result
+	Attempt(ctx context.Context, userID string, now time.Time) result.Result
+	Delete(ctx context.Context, userID string) result.Result
+}
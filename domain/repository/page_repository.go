@@ -2,7 +2,9 @@ package repository
 
 import (
 	"context"
+	"time"
 
+	"github.com/reecerussell/distro-blog/domain/dto"
 	"github.com/reecerussell/distro-blog/domain/model"
 	"github.com/reecerussell/distro-blog/libraries/result"
 )
@@ -11,12 +13,20 @@ import (
 // page data to and from a data source.
 type PageRepository interface {
 	Get(ctx context.Context, id string) result.Result
-	ListPages(ctx context.Context) result.Result
-	ListBlogs(ctx context.Context) result.Result
+	ListPages(ctx context.Context, q *dto.PageListQuery) result.Result
+	ListBlogs(ctx context.Context, q *dto.PageListQuery) result.Result
+	ListPublishedBlogs(ctx context.Context, limit, offset int) result.Result
 	Create(ctx context.Context, p *model.Page) result.Result
 	Update(ctx context.Context, p *model.Page) result.Result
-	Delete(ctx context.Context, id string) result.Result
 	GetAudit(ctx context.Context, id string) result.Result
 	CountByURL(ctx context.Context, p *model.Page) result.Result
 	GetDropdownOptions(ctx context.Context) result.Result
+	ListRevisions(ctx context.Context, pageID string) result.Result
+	GetRevision(ctx context.Context, id string) result.Result
+	GetScheduledIDs(ctx context.Context, date time.Time) result.Result
+	ListSitemapEntries(ctx context.Context) result.Result
+	ListTrash(ctx context.Context) result.Result
+	GetTrashedIDs(ctx context.Context, before time.Time) result.Result
+	CountChildren(ctx context.Context, id string) result.Result
+	Purge(ctx context.Context, id, userID string, date time.Time) result.Result
 }
\ No newline at end of file
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/reecerussell/distro-blog/domain/model"
+	"github.com/reecerussell/distro-blog/libraries/result"
+)
+
+// PageTranslationRepository is a high-level interface used to read and
+// write the translations of pages to and from a data source.
+type PageTranslationRepository interface {
+	List(ctx context.Context, pageID string) result.Result
+	Save(ctx context.Context, t *model.PageTranslation) result.Result
+	Delete(ctx context.Context, pageID, locale string) result.Result
+	CountByPath(ctx context.Context, t *model.PageTranslation) result.Result
+}
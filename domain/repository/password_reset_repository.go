@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/reecerussell/distro-blog/domain/model"
+	"github.com/reecerussell/distro-blog/libraries/result"
+)
+
+// PasswordResetRepository is a high-level interface used to store password reset tokens.
+type PasswordResetRepository interface {
+	Create(ctx context.Context, t *model.PasswordResetToken) result.Result
+	Get(ctx context.Context, tokenHash string) result.Result
+	Use(ctx context.Context, t *model.PasswordResetToken) result.Result
+}
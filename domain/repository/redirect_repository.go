@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/reecerussell/distro-blog/domain/model"
+	"github.com/reecerussell/distro-blog/libraries/result"
+)
+
+// RedirectRepository is a high-level interface used to read and write
+// redirects to and from a data source.
+type RedirectRepository interface {
+	List(ctx context.Context) result.Result
+	Get(ctx context.Context, id string) result.Result
+	GetBySource(ctx context.Context, source string) result.Result
+	Create(ctx context.Context, r *model.Redirect) result.Result
+	Update(ctx context.Context, r *model.Redirect) result.Result
+	Delete(ctx context.Context, id string) result.Result
+}
@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/reecerussell/distro-blog/domain/dto"
+	"github.com/reecerussell/distro-blog/libraries/result"
+)
+
+// RelatedPostRepository is a high-level interface used to read the text
+// of active blogs, and to store and read the posts related to each blog.
+// It also records when the related posts were last requested to be refreshed,
+// and when they were refreshed, so they can be refreshed in the background.
+type RelatedPostRepository interface {
+	ListDocuments(ctx context.Context) result.Result
+	Replace(ctx context.Context, posts []*dto.RelatedPost, refreshedAt time.Time) result.Result
+	List(ctx context.Context, pageID string, limit int) result.Result
+	RequestRefresh(ctx context.Context, date time.Time) result.Result
+	NeedsRefresh(ctx context.Context) result.Result
+}
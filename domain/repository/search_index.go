@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/reecerussell/distro-blog/libraries/result"
+)
+
+// SearchIndex is a high-level interface used to search the content of pages
+// and blogs. Search returns a *dto.PagedList of *dto.SearchHit, with the most
+// relevant first. Pages in the trash are never returned, and inactive pages
+// are only returned if includeInactive is true, which also matches drafts.
+type SearchIndex interface {
+	Search(ctx context.Context, query string, includeInactive bool, limit, offset int) result.Result
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/reecerussell/distro-blog/domain/model"
+	"github.com/reecerussell/distro-blog/libraries/result"
+)
+
+// SeriesRepository is a high-level interface used to read and
+// write series of blogs to and from a data source.
+type SeriesRepository interface {
+	List(ctx context.Context) result.Result
+	Get(ctx context.Context, id string) result.Result
+	GetSeriesIDByPage(ctx context.Context, pageID string) result.Result
+	Create(ctx context.Context, s *model.Series) result.Result
+	Update(ctx context.Context, s *model.Series) result.Result
+	Delete(ctx context.Context, id string) result.Result
+}
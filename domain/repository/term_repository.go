@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/reecerussell/distro-blog/domain/model"
+	"github.com/reecerussell/distro-blog/libraries/result"
+)
+
+// TermRepository is a high-level interface used to read and write
+// tags and categories to and from a data source.
+type TermRepository interface {
+	List(ctx context.Context, termType string) result.Result
+	Get(ctx context.Context, id string) result.Result
+	Create(ctx context.Context, t *model.Term) result.Result
+	Update(ctx context.Context, t *model.Term) result.Result
+	Delete(ctx context.Context, id string) result.Result
+	Merge(ctx context.Context, source, target *model.Term) result.Result
+	CountBySlug(ctx context.Context, t *model.Term) result.Result
+	GetPageTerms(ctx context.Context, pageID string) result.Result
+	SetPageTerms(ctx context.Context, pageID string, termIDs []string) result.Result
+	ListBlogsByTerm(ctx context.Context, termType, slug string) result.Result
+}
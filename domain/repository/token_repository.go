@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/reecerussell/distro-blog/domain/model"
+	"github.com/reecerussell/distro-blog/libraries/result"
+)
+
+// TokenRepository is a high-level interface used to store refresh tokens,
+// and to revoke access tokens before they expire.
+type TokenRepository interface {
+	CreateRefreshToken(ctx context.Context, t *model.RefreshToken) result.Result
+	GetRefreshToken(ctx context.Context, tokenHash string) result.Result
+	RotateRefreshToken(ctx context.Context, used, next *model.RefreshToken) result.Result
+	RevokeRefreshTokenFamily(ctx context.Context, familyID string) result.Result
+	RevokeToken(ctx context.Context, tokenID string, expiresAt time.Time) result.Result
+	RevokeUserTokens(ctx context.Context, userID string) result.Result
+	IsRevoked(ctx context.Context, tokenID, userID string, issuedAt time.Time) result.Result
+}
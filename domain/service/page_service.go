@@ -23,8 +23,8 @@ func NewPageService(repo repository.PageRepository) *PageService {
 	}
 }
 
-// EnsureURLIsUnique ensures the given page's url is unique by
-// ensuring it has not be previously used in the database.
+// EnsureURLIsUnique ensures the given page's url is unique by ensuring it has not be
+// previously used in the database, by another page at the same level as the page.
 func (s *PageService) EnsureURLIsUnique(ctx context.Context, p *model.Page) result.Result {
 	success, _, value, err := s.repo.CountByURL(ctx, p).Deconstruct()
 	if !success {
@@ -33,7 +33,7 @@ func (s *PageService) EnsureURLIsUnique(ctx context.Context, p *model.Page) resu
 
 	count := value.(int64)
 	if count > 0 {
-		msg := fmt.Sprintf("The url '%s' is already being used.", p.URL())
+		msg := fmt.Sprintf("The url '%s' is already being used.", p.Path())
 		return result.Failure(msg).WithStatusCode(http.StatusBadRequest)
 	}
 
@@ -0,0 +1,41 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/reecerussell/distro-blog/domain/model"
+	"github.com/reecerussell/distro-blog/domain/repository"
+	"github.com/reecerussell/distro-blog/libraries/result"
+)
+
+// TaxonomyService is used to provide the taxonomy domain with extra
+// functionality, such as validation which doesn't belong to the domain layer.
+type TaxonomyService struct {
+	repo repository.TermRepository
+}
+
+// NewTaxonomyService returns a new instance of TaxonomyService with the given repository.
+func NewTaxonomyService(repo repository.TermRepository) *TaxonomyService {
+	return &TaxonomyService{
+		repo: repo,
+	}
+}
+
+// EnsureSlugIsUnique ensures the given term's slug is not being
+// used by another term of the same type.
+func (s *TaxonomyService) EnsureSlugIsUnique(ctx context.Context, t *model.Term) result.Result {
+	success, _, value, err := s.repo.CountBySlug(ctx, t).Deconstruct()
+	if !success {
+		return result.Failure(err)
+	}
+
+	count := value.(int64)
+	if count > 0 {
+		msg := fmt.Sprintf("A %s with the slug '%s' already exists.", t.Type(), t.Slug())
+		return result.Failure(msg).WithStatusCode(http.StatusBadRequest)
+	}
+
+	return result.Ok()
+}
@@ -0,0 +1,45 @@
+package shortcodes
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/reecerussell/distro-blog/libraries/shortcode"
+)
+
+var (
+	youTubeIDRegex = regexp.MustCompile(`^[A-Za-z0-9_-]{6,20}$`)
+	gistIDRegex = regexp.MustCompile(`^[A-Za-z0-9-]+/[0-9a-f]+$`)
+	gistFileRegex = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+)
+
+// YouTube renders [youtube id="..."] shortcodes as an embedded YouTube video.
+func YouTube(ctx context.Context, s *shortcode.Shortcode) (string, error) {
+	id := s.Attr("id")
+	if !youTubeIDRegex.MatchString(id) {
+		return "", fmt.Errorf("'%s' is not a valid video id", id)
+	}
+
+	return fmt.Sprintf(`<iframe class="youtube" src="https://www.youtube-nocookie.com/embed/%s" width="560" height="315" title="YouTube video" frameborder="0" allowfullscreen></iframe>`, id), nil
+}
+
+// Gist renders [gist id="user/id"] shortcodes as an embedded GitHub gist. A
+// single file from the gist can be shown using the file attribute.
+func Gist(ctx context.Context, s *shortcode.Shortcode) (string, error) {
+	id := s.Attr("id")
+	if !gistIDRegex.MatchString(id) {
+		return "", fmt.Errorf("'%s' is not a valid gist id", id)
+	}
+
+	src := fmt.Sprintf("https://gist.github.com/%s.js", id)
+	if f := s.Attr("file"); f != "" {
+		if !gistFileRegex.MatchString(f) {
+			return "", fmt.Errorf("'%s' is not a valid file name", f)
+		}
+
+		src += "?file=" + f
+	}
+
+	return fmt.Sprintf(`<script src="%s"></script>`, src), nil
+}
@@ -0,0 +1,39 @@
+package shortcodes
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/reecerussell/distro-blog/libraries/shortcode"
+)
+
+func TestYouTube(t *testing.T) {
+	ctx := context.Background()
+
+	out, err := YouTube(ctx, shortcode.Parse(`[youtube id="dQw4w9WgXcQ"]`)[0])
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if !strings.Contains(out, `src="https://www.youtube-nocookie.com/embed/dQw4w9WgXcQ"`) {
+		t.Errorf("expected an embedded video but got '%s'", out)
+	}
+
+	_, err = YouTube(ctx, shortcode.Parse(`[youtube id="x&quot;onload=alert(1)"]`)[0])
+	if err == nil {
+		t.Errorf("expected an error for an invalid id but got nil")
+	}
+}
+
+func TestGist(t *testing.T) {
+	out, err := Gist(context.Background(), shortcode.Parse(`[gist id="reecerussell/0a1b2c" file="main.go"]`)[0])
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	expected := `<script src="https://gist.github.com/reecerussell/0a1b2c.js?file=main.go"></script>`
+	if out != expected {
+		t.Errorf("expected '%s' but got '%s'", expected, out)
+	}
+}
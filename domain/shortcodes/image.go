@@ -0,0 +1,56 @@
+package shortcodes
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/reecerussell/distro-blog/domain/model"
+	"github.com/reecerussell/distro-blog/domain/repository"
+	"github.com/reecerussell/distro-blog/libraries/shortcode"
+)
+
+// Image renders [image id="..."] shortcodes as an img element, using the image's
+// alternative text, unless an alt attribute is given. If a caption is given,
+// the image is wrapped in a figure.
+type Image struct {
+	repo repository.ImageRepository
+	mediaURL string
+}
+
+// NewImageHandler returns a new instance of the Image shortcode handler.
+func NewImageHandler(repo repository.ImageRepository, mediaURL string) *Image {
+	return &Image{
+		repo: repo,
+		mediaURL: strings.TrimRight(mediaURL, "/"),
+	}
+}
+
+// Render renders the image shortcode, s.
+func (h *Image) Render(ctx context.Context, s *shortcode.Shortcode) (string, error) {
+	id := s.Attr("id")
+	if id == "" {
+		return "", fmt.Errorf("an id is required")
+	}
+
+	success, _, value, err := h.repo.Get(ctx, id).Deconstruct()
+	if !success {
+		return "", err
+	}
+
+	img := value.(*model.Image)
+	alt := s.Attr("alt")
+	if alt == "" && img.GetAlternativeText() != nil {
+		alt = *img.GetAlternativeText()
+	}
+
+	tag := fmt.Sprintf(`<img src="%s/%s" alt="%s" />`,
+		html.EscapeString(h.mediaURL), html.EscapeString(img.GetID()), html.EscapeString(alt))
+
+	if c := s.Attr("caption"); c != "" {
+		return fmt.Sprintf("<figure>%s<figcaption>%s</figcaption></figure>", tag, html.EscapeString(c)), nil
+	}
+
+	return tag, nil
+}
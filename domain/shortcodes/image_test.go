@@ -0,0 +1,77 @@
+package shortcodes
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"testing"
+
+	"github.com/reecerussell/distro-blog/domain/datamodel"
+	"github.com/reecerussell/distro-blog/domain/model"
+	"github.com/reecerussell/distro-blog/domain/repository"
+	"github.com/reecerussell/distro-blog/libraries/result"
+	"github.com/reecerussell/distro-blog/libraries/shortcode"
+)
+
+// memoryImages is an in-memory ImageRepository, used to test the image
+// shortcode. Only Get is implemented, as it's the only method used.
+type memoryImages struct {
+	repository.ImageRepository
+	images map[string]*model.Image
+}
+
+func (r *memoryImages) Get(ctx context.Context, id string) result.Result {
+	i, ok := r.images[id]
+	if !ok {
+		return result.Failure("image not found").WithStatusCode(http.StatusNotFound)
+	}
+
+	return result.Ok().WithValue(i)
+}
+
+func TestImage_Render(t *testing.T) {
+	repo := &memoryImages{
+		images: map[string]*model.Image{
+			"img-1": model.ImageFromDataModel(&datamodel.Image{
+				ID: "img-1",
+				AlternativeText: sql.NullString{String: "A \"red\" door", Valid: true},
+			}),
+			"img-2": model.ImageFromDataModel(&datamodel.Image{ID: "img-2"}),
+		},
+	}
+	h := NewImageHandler(repo, "https://media.example.com/")
+	ctx := context.Background()
+
+	tests := map[string]string{
+		`[image id="img-1"]`: `<img src="https://media.example.com/img-1" alt="A &#34;red&#34; door" />`,
+		`[image id="img-1" alt="Front door"]`: `<img src="https://media.example.com/img-1" alt="Front door" />`,
+		`[image id="img-2"]`: `<img src="https://media.example.com/img-2" alt="" />`,
+		`[image id="img-2" caption="Home"]`: `<figure><img src="https://media.example.com/img-2" alt="" /><figcaption>Home</figcaption></figure>`,
+	}
+
+	for c, expected := range tests {
+		out, err := h.Render(ctx, shortcode.Parse(c)[0])
+		if err != nil {
+			t.Errorf("%s: expected no error but got: %v", c, err)
+			continue
+		}
+
+		if out != expected {
+			t.Errorf("%s: expected '%s' but got '%s'", c, expected, out)
+		}
+	}
+
+	t.Run("Missing ID", func(t *testing.T) {
+		_, err := h.Render(ctx, shortcode.Parse(`[image alt="Front door"]`)[0])
+		if err == nil {
+			t.Errorf("expected an error but got nil")
+		}
+	})
+
+	t.Run("Unknown Image", func(t *testing.T) {
+		_, err := h.Render(ctx, shortcode.Parse(`[image id="img-3"]`)[0])
+		if err == nil {
+			t.Errorf("expected an error but got nil")
+		}
+	})
+}
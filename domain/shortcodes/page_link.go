@@ -0,0 +1,51 @@
+package shortcodes
+
+import (
+	"context"
+	"fmt"
+	"html"
+
+	"github.com/reecerussell/distro-blog/domain/model"
+	"github.com/reecerussell/distro-blog/domain/repository"
+	"github.com/reecerussell/distro-blog/libraries/shortcode"
+)
+
+// PageLink renders [page-link id="..."] shortcodes as a link to the page's current
+// path, so links don't break when a page is renamed or moved. The link's text is
+// the page's title, unless a text attribute is given. Links to pages which are
+// not live are rendered as plain text.
+type PageLink struct {
+	repo repository.PageRepository
+}
+
+// NewPageLinkHandler returns a new instance of the PageLink shortcode handler.
+func NewPageLinkHandler(repo repository.PageRepository) *PageLink {
+	return &PageLink{repo: repo}
+}
+
+// Render renders the page link shortcode, s.
+func (h *PageLink) Render(ctx context.Context, s *shortcode.Shortcode) (string, error) {
+	id := s.Attr("id")
+	if id == "" {
+		return "", fmt.Errorf("an id is required")
+	}
+
+	success, _, value, err := h.repo.Get(ctx, id).Deconstruct()
+	if !success {
+		return "", err
+	}
+
+	p := value.(*model.Page)
+	d := p.DTO()
+
+	text := s.Attr("text")
+	if text == "" {
+		text = d.Title
+	}
+
+	if !d.IsActive || p.IsTrashed() {
+		return html.EscapeString(text), nil
+	}
+
+	return fmt.Sprintf(`<a href="%s">%s</a>`, html.EscapeString(p.Path()), html.EscapeString(text)), nil
+}
@@ -0,0 +1,82 @@
+package shortcodes
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/reecerussell/distro-blog/domain/datamodel"
+	"github.com/reecerussell/distro-blog/domain/model"
+	"github.com/reecerussell/distro-blog/domain/repository"
+	"github.com/reecerussell/distro-blog/libraries/result"
+	"github.com/reecerussell/distro-blog/libraries/shortcode"
+)
+
+// memoryPages is an in-memory PageRepository, used to test the page link
+// shortcode. Only Get is implemented, as it's the only method used.
+type memoryPages struct {
+	repository.PageRepository
+	pages map[string]*model.Page
+}
+
+func (r *memoryPages) Get(ctx context.Context, id string) result.Result {
+	p, ok := r.pages[id]
+	if !ok {
+		return result.Failure("page not found").WithStatusCode(http.StatusNotFound)
+	}
+
+	return result.Ok().WithValue(p)
+}
+
+func TestPageLink_Render(t *testing.T) {
+	repo := &memoryPages{
+		pages: map[string]*model.Page{
+			"live": model.PageFromDataModel(&datamodel.Page{ID: "live", Title: "About <us>", Path: "about", IsActive: true}),
+			"inactive": model.PageFromDataModel(&datamodel.Page{ID: "inactive", Title: "Draft", Path: "draft"}),
+			"trashed": model.PageFromDataModel(&datamodel.Page{
+				ID: "trashed",
+				Title: "Old",
+				Path: "old",
+				IsActive: true,
+				DeletedAt: sql.NullTime{Time: time.Now(), Valid: true},
+			}),
+		},
+	}
+	h := NewPageLinkHandler(repo)
+	ctx := context.Background()
+
+	tests := map[string]string{
+		`[page-link id="live"]`: `<a href="/about">About &lt;us&gt;</a>`,
+		`[page-link id="live" text="Read more"]`: `<a href="/about">Read more</a>`,
+		`[page-link id="inactive"]`: `Draft`,
+		`[page-link id="trashed" text="Old page"]`: `Old page`,
+	}
+
+	for c, expected := range tests {
+		out, err := h.Render(ctx, shortcode.Parse(c)[0])
+		if err != nil {
+			t.Errorf("%s: expected no error but got: %v", c, err)
+			continue
+		}
+
+		if out != expected {
+			t.Errorf("%s: expected '%s' but got '%s'", c, expected, out)
+		}
+	}
+
+	t.Run("Missing ID", func(t *testing.T) {
+		_, err := h.Render(ctx, shortcode.Parse(`[page-link text="About"]`)[0])
+		if err == nil {
+			t.Errorf("expected an error but got nil")
+		}
+	})
+
+	t.Run("Unknown Page", func(t *testing.T) {
+		_, err := h.Render(ctx, shortcode.Parse(`[page-link id="missing"]`)[0])
+		if err == nil {
+			t.Errorf("expected an error but got nil")
+		}
+	})
+}
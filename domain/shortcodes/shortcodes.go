@@ -0,0 +1,18 @@
+// Package shortcodes contains the handlers for the shortcodes which can be
+// used in page content, which are expanded when a page is read by the site.
+package shortcodes
+
+import (
+	"github.com/reecerussell/distro-blog/domain/repository"
+	"github.com/reecerussell/distro-blog/libraries/shortcode"
+)
+
+// Register registers the handlers for each of the built-in shortcodes. Images
+// are linked to using mediaURL, which is the base url images are served from.
+func Register(images repository.ImageRepository, pages repository.PageRepository, mediaURL string) {
+	shortcode.RegisterHandler("image", NewImageHandler(images, mediaURL))
+	shortcode.RegisterHandler("page-link", NewPageLinkHandler(pages))
+	shortcode.RegisterHandler("youtube", shortcode.HandlerFunc(YouTube))
+	shortcode.RegisterHandler("gist", shortcode.HandlerFunc(Gist))
+	shortcode.RegisterHandler("toc", shortcode.HandlerFunc(TOC))
+}
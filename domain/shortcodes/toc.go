@@ -0,0 +1,36 @@
+package shortcodes
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/reecerussell/distro-blog/libraries/content"
+	"github.com/reecerussell/distro-blog/libraries/shortcode"
+)
+
+var headingRegex = regexp.MustCompile(`(?is)<h([2-6])\b[^>]*\bid="([^"]+)"[^>]*>(.*?)</h[2-6]>`)
+
+// TOC renders [toc] shortcodes as a table of contents, linking to each
+// of the headings in the content which have an id. Nothing is rendered
+// if there aren't any headings.
+func TOC(ctx context.Context, s *shortcode.Shortcode) (string, error) {
+	headings := headingRegex.FindAllStringSubmatch(s.Content, -1)
+	if len(headings) < 1 {
+		return "", nil
+	}
+
+	var b strings.Builder
+	b.WriteString(`<nav class="toc"><ol>`)
+
+	for _, h := range headings {
+		text := html.EscapeString(content.PlainText(h[3]))
+		fmt.Fprintf(&b, `<li class="toc-h%s"><a href="#%s">%s</a></li>`, h[1], h[2], text)
+	}
+
+	b.WriteString("</ol></nav>")
+
+	return b.String(), nil
+}
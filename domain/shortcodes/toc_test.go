@@ -0,0 +1,23 @@
+package shortcodes
+
+import (
+	"context"
+	"testing"
+
+	"github.com/reecerussell/distro-blog/libraries/shortcode"
+)
+
+func TestTOC(t *testing.T) {
+	c := `[toc]<h1 id="title">Title</h1><h2 id="setup">Set <em>up</em></h2><h3 id="install">Install</h3>`
+
+	out, err := TOC(context.Background(), shortcode.Parse(c)[0])
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	expected := `<nav class="toc"><ol><li class="toc-h2"><a href="#setup">Set up</a></li>` +
+		`<li class="toc-h3"><a href="#install">Install</a></li></ol></nav>`
+	if out != expected {
+		t.Errorf("expected '%s' but got '%s'", expected, out)
+	}
+}
@@ -14,9 +14,12 @@ import (
 	"github.com/aws/aws-lambda-go/lambda"
 	"gopkg.in/yaml.v2"
 
+	authMod "github.com/reecerussell/distro-blog/auth"
 	"github.com/reecerussell/distro-blog/libraries/contextkey"
+	"github.com/reecerussell/distro-blog/libraries/database"
 	"github.com/reecerussell/distro-blog/libraries/logging"
 	"github.com/reecerussell/distro-blog/libraries/storage"
+	"github.com/reecerussell/distro-blog/persistence"
 	"github.com/reecerussell/distro-blog/usecase"
 )
 
@@ -27,9 +30,16 @@ var (
 )
 
 func init(){
-	auth = usecase.NewAuthUsecase(nil)
+	db := database.NewMySQL(os.Getenv("CONN_STRING"))
+
+	signer, err := authMod.NewSignerFromEnv()
+	if err != nil {
+		logging.Error(err)
+		panic(err)
+	}
+
+	auth = usecase.NewAuthUsecase(nil, persistence.NewTokenRepository(db), nil, nil, signer)
 
-	var err error
 	store, err = storage.New(os.Getenv("CONFIG_BUCKET_NAME"))
 	if err != nil {
 		err = fmt.Errorf("failed to init storage: %v", err)
@@ -149,7 +159,7 @@ func scanToken(token string) ([]byte, []string) {
 		panic(errMsg)
 	}
 
-	data, err := base64.StdEncoding.DecodeString(tokenParts[1])
+	data, err := base64.RawURLEncoding.DecodeString(tokenParts[1])
 	if err != nil {
 		err = fmt.Errorf("failed to decode payload: %v", err)
 		logging.Error(err)
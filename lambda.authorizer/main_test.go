@@ -26,7 +26,12 @@ var (
 func init() {
 	db := database.NewMySQL(testConnString)
 	repo := persistence.NewUserRepository(db)
-	testAuth = usecase.NewAuthUsecase(repo)
+	signer, err := authMod.NewSignerFromEnv()
+	if err != nil {
+		panic(err)
+	}
+
+	testAuth = usecase.NewAuthUsecase(repo, persistence.NewTokenRepository(db), persistence.NewMFARepository(db), persistence.NewSettingRepository(db), signer)
 }
 
 func TestHandleAuthentication(t *testing.T) {
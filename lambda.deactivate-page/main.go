@@ -18,7 +18,8 @@ var pages usecase.PageUsecase
 func init() {
 	db := database.NewMySQL(os.Getenv("CONN_STRING"))
 	repo := persistence.NewPageRepository(db)
-	pages = usecase.NewPageUsecase(repo, nil)
+	related := usecase.NewRelatedUsecase(persistence.NewRelatedPostRepository(db))
+	pages = usecase.NewPageUsecase(repo, nil, related)
 }
 
 // handleDeactivate handles incoming API Gateway requests to deactivate pages.
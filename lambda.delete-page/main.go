@@ -26,7 +26,8 @@ func init() {
 		panic(err)
 	}
 
-	pages = usecase.NewPageUsecase(repo, media)
+	related := usecase.NewRelatedUsecase(persistence.NewRelatedPostRepository(db))
+	pages = usecase.NewPageUsecase(repo, media, related)
 }
 
 // handleDelete handles incoming API Gateway requests to delete pages.
@@ -18,7 +18,7 @@ var pages usecase.PageUsecase
 func init() {
 	db := database.NewMySQL(os.Getenv("CONN_STRING"))
 	repo := persistence.NewPageRepository(db)
-	pages = usecase.NewPageUsecase(repo, nil)
+	pages = usecase.NewPageUsecase(repo, nil, nil)
 }
 
 // handleGet handles incoming API Gateway requests to get a page.
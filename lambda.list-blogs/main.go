@@ -2,13 +2,16 @@ package main
 
 import (
 	"context"
+	"net/http"
 	"os"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
 
+	"github.com/reecerussell/distro-blog/domain/dto"
 	"github.com/reecerussell/distro-blog/libraries/database"
 	"github.com/reecerussell/distro-blog/libraries/helper"
+	"github.com/reecerussell/distro-blog/libraries/result"
 	"github.com/reecerussell/distro-blog/persistence"
 	"github.com/reecerussell/distro-blog/usecase"
 )
@@ -18,14 +21,22 @@ var pages usecase.PageUsecase
 func init() {
 	db := database.NewMySQL(os.Getenv("CONN_STRING"))
 	repo := persistence.NewPageRepository(db)
-	pages = usecase.NewPageUsecase(repo, nil)
+	pages = usecase.NewPageUsecase(repo, nil, nil)
 }
 
 // handleList is a Lambda handler function used to handle incoming
 // APIGateway proxy requests to gather a list of pages.
 func handleList(ctx context.Context, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
 	ctx = helper.PopulateContext(ctx, req)
-	res := pages.ListBlogs(ctx)
+
+	var q dto.PageListQuery
+	err := helper.ReadQuery(req, &q)
+	if err != nil {
+		br := result.Failure(err).WithStatusCode(http.StatusBadRequest)
+		return helper.Response(ctx, br, req), nil
+	}
+
+	res := pages.ListBlogs(ctx, &q)
 	return helper.Response(ctx, res, req), nil
 }
 
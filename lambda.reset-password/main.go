@@ -9,23 +9,37 @@ import (
 
 	"github.com/reecerussell/distro-blog/libraries/database"
 	"github.com/reecerussell/distro-blog/libraries/helper"
+	"github.com/reecerussell/distro-blog/libraries/logging"
+	"github.com/reecerussell/distro-blog/libraries/mail"
 	"github.com/reecerussell/distro-blog/persistence"
 	"github.com/reecerussell/distro-blog/usecase"
 )
-var users usecase.UserUsecase
+
+var resets usecase.PasswordResetUsecase
 
 func init() {
 	db := database.NewMySQL(os.Getenv("CONN_STRING"))
-	repo := persistence.NewUserRepository(db)
-	users = usecase.NewUserUsecase(repo)
+	repo := persistence.NewPasswordResetRepository(db)
+	users := persistence.NewUserRepository(db)
+	tokens := persistence.NewTokenRepository(db)
+
+	sender, err := mail.NewSenderFromEnv()
+	if err != nil {
+		logging.Error(err)
+		panic(err)
+	}
+
+	resets = usecase.NewPasswordResetUsecase(repo, users, tokens, persistence.NewSettingRepository(db), sender, os.Getenv("PASSWORD_RESET_URL"))
 }
 
+// handleResetPassword handles incoming API Gateway requests to email a password
+// reset link to a user, so they can choose a new password.
 func handleResetPassword(ctx context.Context, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
 	ctx = helper.PopulateContext(ctx, req)
-	res := users.ResetPassword(ctx, req.PathParameters["id"])
+	res := resets.Send(ctx, req.PathParameters["id"])
 	return helper.Response(ctx, res, req), nil
 }
 
 func main() {
 	lambda.Start(handleResetPassword)
-}
\ No newline at end of file
+}
@@ -8,9 +8,11 @@ import (
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
 
+	authMod "github.com/reecerussell/distro-blog/auth"
 	"github.com/reecerussell/distro-blog/domain/dto"
 	"github.com/reecerussell/distro-blog/libraries/database"
 	"github.com/reecerussell/distro-blog/libraries/helper"
+	"github.com/reecerussell/distro-blog/libraries/logging"
 	"github.com/reecerussell/distro-blog/libraries/result"
 	"github.com/reecerussell/distro-blog/persistence"
 	"github.com/reecerussell/distro-blog/usecase"
@@ -23,7 +25,17 @@ var (
 func init() {
 	db := database.NewMySQL(os.Getenv("CONN_STRING"))
 	repo := persistence.NewUserRepository(db)
-	auth = usecase.NewAuthUsecase(repo)
+	tokens := persistence.NewTokenRepository(db)
+	mfa := persistence.NewMFARepository(db)
+	settings := persistence.NewSettingRepository(db)
+
+	signer, err := authMod.NewSignerFromEnv()
+	if err != nil {
+		logging.Error(err)
+		panic(err)
+	}
+
+	auth = usecase.NewAuthUsecase(repo, tokens, mfa, settings, signer)
 }
 
 func handleToken(ctx context.Context, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
@@ -35,7 +35,8 @@ func init() {
 		panic(err)
 	}
 
-	pages = usecase.NewPageUsecase(repo, media)
+	related := usecase.NewRelatedUsecase(persistence.NewRelatedPostRepository(db))
+	pages = usecase.NewPageUsecase(repo, media, related)
 }
 
 // handleUpdate handles incoming API Gateway requests to update a page.
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"os"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+
+	"github.com/reecerussell/distro-blog/domain/dto"
+	"github.com/reecerussell/distro-blog/libraries/database"
+	"github.com/reecerussell/distro-blog/libraries/helper"
+	"github.com/reecerussell/distro-blog/libraries/result"
+	"github.com/reecerussell/distro-blog/persistence"
+	"github.com/reecerussell/distro-blog/usecase"
+)
+
+var series usecase.SeriesUsecase
+
+func init() {
+	db := database.NewMySQL(os.Getenv("CONN_STRING"))
+	repo := persistence.NewSeriesRepository(db)
+	series = usecase.NewSeriesUsecase(repo, persistence.NewPageRepository(db))
+}
+
+// handleAdd handles incoming API Gateway requests to add a blog to the end of a series.
+func handleAdd(ctx context.Context, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	ctx = helper.PopulateContext(ctx, req)
+
+	var d dto.AddSeriesPage
+	err := helper.ReadBody(req, &d)
+	if err != nil {
+		br := result.Failure(err).WithStatusCode(http.StatusBadRequest)
+		return helper.Response(ctx, br, req), nil
+	}
+
+	res := series.AddPage(ctx, req.PathParameters["id"], &d)
+	return helper.Response(ctx, res, req), nil
+}
+
+func main() {
+	lambda.Start(handleAdd)
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"os"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+
+	"github.com/reecerussell/distro-blog/domain/dto"
+	"github.com/reecerussell/distro-blog/libraries/database"
+	"github.com/reecerussell/distro-blog/libraries/helper"
+	"github.com/reecerussell/distro-blog/libraries/logging"
+	"github.com/reecerussell/distro-blog/libraries/mail"
+	"github.com/reecerussell/distro-blog/libraries/result"
+	"github.com/reecerussell/distro-blog/persistence"
+	"github.com/reecerussell/distro-blog/usecase"
+)
+
+var resets usecase.PasswordResetUsecase
+
+func init() {
+	db := database.NewMySQL(os.Getenv("CONN_STRING"))
+	repo := persistence.NewPasswordResetRepository(db)
+	users := persistence.NewUserRepository(db)
+	tokens := persistence.NewTokenRepository(db)
+
+	sender, err := mail.NewSenderFromEnv()
+	if err != nil {
+		logging.Error(err)
+		panic(err)
+	}
+
+	resets = usecase.NewPasswordResetUsecase(repo, users, tokens, persistence.NewSettingRepository(db), sender, os.Getenv("PASSWORD_RESET_URL"))
+}
+
+// handleReset handles incoming API Gateway requests to set a new password, using the token from a password reset email.
+func handleReset(ctx context.Context, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	ctx = helper.PopulateContext(ctx, req)
+
+	var d dto.ResetPassword
+	err := helper.ReadBody(req, &d)
+	if err != nil {
+		br := result.Failure(err).WithStatusCode(http.StatusBadRequest)
+		return helper.Response(ctx, br, req), nil
+	}
+
+	res := resets.Reset(ctx, &d)
+	return helper.Response(ctx, res, req), nil
+}
+
+func main() {
+	lambda.Start(handleReset)
+}
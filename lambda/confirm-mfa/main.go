@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"os"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+
+	"github.com/reecerussell/distro-blog/domain/dto"
+	"github.com/reecerussell/distro-blog/libraries/database"
+	"github.com/reecerussell/distro-blog/libraries/helper"
+	"github.com/reecerussell/distro-blog/libraries/result"
+	"github.com/reecerussell/distro-blog/persistence"
+	"github.com/reecerussell/distro-blog/usecase"
+)
+
+var mfa usecase.MFAUsecase
+
+func init() {
+	db := database.NewMySQL(os.Getenv("CONN_STRING"))
+	repo := persistence.NewMFARepository(db)
+	users := persistence.NewUserRepository(db)
+	tokens := persistence.NewTokenRepository(db)
+	mfa = usecase.NewMFAUsecase(repo, users, tokens, persistence.NewSettingRepository(db))
+}
+
+// handleConfirm handles incoming API Gateway requests to enable the current user's two-factor authentication, using a code from their authenticator app.
+func handleConfirm(ctx context.Context, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	ctx = helper.PopulateContext(ctx, req)
+
+	var d dto.MFACode
+	err := helper.ReadBody(req, &d)
+	if err != nil {
+		br := result.Failure(err).WithStatusCode(http.StatusBadRequest)
+		return helper.Response(ctx, br, req), nil
+	}
+
+	res := mfa.Confirm(ctx, &d)
+	return helper.Response(ctx, res, req), nil
+}
+
+func main() {
+	lambda.Start(handleConfirm)
+}
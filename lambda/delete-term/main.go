@@ -0,0 +1,33 @@
+package main
+
+import (
+	"context"
+	"os"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+
+	"github.com/reecerussell/distro-blog/libraries/database"
+	"github.com/reecerussell/distro-blog/libraries/helper"
+	"github.com/reecerussell/distro-blog/persistence"
+	"github.com/reecerussell/distro-blog/usecase"
+)
+
+var taxonomy usecase.TaxonomyUsecase
+
+func init() {
+	db := database.NewMySQL(os.Getenv("CONN_STRING"))
+	repo := persistence.NewTermRepository(db)
+	taxonomy = usecase.NewTaxonomyUsecase(repo, persistence.NewPageRepository(db))
+}
+
+// handleDelete handles incoming API Gateway requests to delete a tag or category.
+func handleDelete(ctx context.Context, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	ctx = helper.PopulateContext(ctx, req)
+	res := taxonomy.Delete(ctx, req.PathParameters["id"])
+	return helper.Response(ctx, res, req), nil
+}
+
+func main() {
+	lambda.Start(handleDelete)
+}
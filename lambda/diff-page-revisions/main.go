@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+
+	"github.com/reecerussell/distro-blog/libraries/database"
+	"github.com/reecerussell/distro-blog/libraries/helper"
+	"github.com/reecerussell/distro-blog/libraries/result"
+	"github.com/reecerussell/distro-blog/persistence"
+	"github.com/reecerussell/distro-blog/usecase"
+)
+
+var pages usecase.PageUsecase
+
+func init() {
+	db := database.NewMySQL(os.Getenv("CONN_STRING"))
+	repo := persistence.NewPageRepository(db)
+	pages = usecase.NewPageUsecase(repo, nil, nil)
+}
+
+// handleDiff handles incoming API Gateway requests to compare two revisions of a page.
+// The revisions are given by the "from" and "to" query parameters, and a HTML diff can
+// be requested by setting the "format" query parameter to "html".
+func handleDiff(ctx context.Context, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	ctx = helper.PopulateContext(ctx, req)
+
+	from := req.QueryStringParameters["from"]
+	to := req.QueryStringParameters["to"]
+	if from == "" || to == "" {
+		br := result.Failure("both 'from' and 'to' revisions are required").WithStatusCode(http.StatusBadRequest)
+		return helper.Response(ctx, br, req), nil
+	}
+
+	asHTML := strings.ToLower(req.QueryStringParameters["format"]) == "html"
+	res := pages.DiffRevisions(ctx, req.PathParameters["id"], from, to, asHTML)
+	return helper.Response(ctx, res, req), nil
+}
+
+func main() {
+	lambda.Start(handleDiff)
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+	"os"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+
+	"github.com/reecerussell/distro-blog/libraries/database"
+	"github.com/reecerussell/distro-blog/libraries/helper"
+	"github.com/reecerussell/distro-blog/persistence"
+	"github.com/reecerussell/distro-blog/usecase"
+)
+
+var mfa usecase.MFAUsecase
+
+func init() {
+	db := database.NewMySQL(os.Getenv("CONN_STRING"))
+	repo := persistence.NewMFARepository(db)
+	users := persistence.NewUserRepository(db)
+	tokens := persistence.NewTokenRepository(db)
+	mfa = usecase.NewMFAUsecase(repo, users, tokens, persistence.NewSettingRepository(db))
+}
+
+// handleEnroll handles incoming API Gateway requests to create a new authenticator
+// for the current user, returning its secret and otpauth:// URI.
+func handleEnroll(ctx context.Context, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	ctx = helper.PopulateContext(ctx, req)
+	res := mfa.Enroll(ctx)
+	return helper.Response(ctx, res, req), nil
+}
+
+func main() {
+	lambda.Start(handleEnroll)
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"context"
+	"os"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+
+	"github.com/reecerussell/distro-blog/libraries/database"
+	"github.com/reecerussell/distro-blog/libraries/helper"
+	"github.com/reecerussell/distro-blog/persistence"
+	"github.com/reecerussell/distro-blog/usecase"
+)
+
+var authors usecase.AuthorUsecase
+
+func init() {
+	db := database.NewMySQL(os.Getenv("CONN_STRING"))
+	repo := persistence.NewAuthorRepository(db)
+	users := persistence.NewUserRepository(db)
+	authors = usecase.NewAuthorUsecase(repo, users, persistence.NewPageRepository(db), persistence.NewImageRepository(db))
+}
+
+// handleGet handles incoming, unauthenticated, API Gateway requests
+// to get the public profile of an author.
+func handleGet(ctx context.Context, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	ctx = helper.PopulateContext(ctx, req)
+	res := authors.Get(ctx, req.PathParameters["id"])
+	return helper.Response(ctx, res, req), nil
+}
+
+func main() {
+	lambda.Start(handleGet)
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"os"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+
+	"github.com/reecerussell/distro-blog/domain/dto"
+	"github.com/reecerussell/distro-blog/libraries/database"
+	"github.com/reecerussell/distro-blog/libraries/helper"
+	"github.com/reecerussell/distro-blog/libraries/result"
+	"github.com/reecerussell/distro-blog/persistence"
+	"github.com/reecerussell/distro-blog/usecase"
+)
+
+// feedMaxAge is the number of seconds clients may cache the feed for.
+const feedMaxAge = 900
+
+var feeds usecase.FeedUsecase
+
+func init() {
+	db := database.NewMySQL(os.Getenv("CONN_STRING"))
+	pageRepo := persistence.NewPageRepository(db)
+	settingRepo := persistence.NewSettingRepository(db)
+	feeds = usecase.NewFeedUsecase(pageRepo, settingRepo, os.Getenv("SITE_URL"), os.Getenv("MEDIA_URL"))
+}
+
+// handleFeed handles incoming, unauthenticated, API Gateway requests for a feed of
+// the latest blogs. The format is given by the "format" query or path parameter.
+func handleFeed(ctx context.Context, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	ctx = helper.PopulateContext(ctx, req)
+
+	var q dto.FeedQuery
+	err := helper.ReadQuery(req, &q)
+	if err != nil {
+		br := result.Failure(err).WithStatusCode(http.StatusBadRequest)
+		return helper.Response(ctx, br, req), nil
+	}
+
+	if f, ok := req.PathParameters["format"]; ok {
+		q.Format = f
+	}
+
+	res := feeds.Generate(ctx, &q)
+	success, _, value, _ := res.Deconstruct()
+	if !success {
+		return helper.Response(ctx, res, req), nil
+	}
+
+	f := value.(*dto.Document)
+	return helper.ContentResponse(ctx, req, f.ContentType, f.Body, f.LastModified, feedMaxAge), nil
+}
+
+func main() {
+	lambda.Start(handleFeed)
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+
+	authMod "github.com/reecerussell/distro-blog/auth"
+	"github.com/reecerussell/distro-blog/libraries/contextkey"
+	"github.com/reecerussell/distro-blog/libraries/helper"
+	"github.com/reecerussell/distro-blog/libraries/logging"
+	"github.com/reecerussell/distro-blog/usecase"
+)
+
+// jwksMaxAge is the number of seconds clients may cache the key set for.
+const jwksMaxAge = 3600
+
+var auth usecase.AuthUsecase
+
+func init() {
+	signer, err := authMod.NewSignerFromEnv()
+	if err != nil {
+		logging.Error(err)
+		panic(err)
+	}
+
+	auth = usecase.NewAuthUsecase(nil, nil, nil, nil, signer)
+}
+
+// handleJWKS handles incoming, unauthenticated, API Gateway requests for
+// /.well-known/jwks.json, which publishes the keys used to verify tokens.
+func handleJWKS(ctx context.Context, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	ctx = helper.PopulateContext(ctx, req)
+	ctx = context.WithValue(ctx, contextkey.ContextKey("JWT_KEY_ID"), os.Getenv("JWT_KEY_ID"))
+
+	res := auth.KeySet(ctx)
+	success, _, value, _ := res.Deconstruct()
+	if !success {
+		return helper.Response(ctx, res, req), nil
+	}
+
+	body, _ := json.Marshal(value)
+	return helper.ContentResponse(ctx, req, "application/jwk-set+json", body, time.Time{}, jwksMaxAge), nil
+}
+
+func main() {
+	lambda.Start(handleJWKS)
+}
@@ -3,52 +3,131 @@ package main
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"net/http"
+	neturl "net/url"
 	"os"
 	"strings"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/reecerussell/distro-blog/domain/dto"
+	"github.com/reecerussell/distro-blog/domain/shortcodes"
 	"github.com/reecerussell/distro-blog/libraries/database"
 	"github.com/reecerussell/distro-blog/libraries/helper"
+	"github.com/reecerussell/distro-blog/libraries/locale"
 	"github.com/reecerussell/distro-blog/libraries/result"
+	"github.com/reecerussell/distro-blog/libraries/shortcode"
+	"github.com/reecerussell/distro-blog/persistence"
+	"github.com/reecerussell/distro-blog/usecase"
 )
 
 var (
 	db *database.MySQL
+	redirects usecase.RedirectUsecase
 )
 
 func init() {
 	db = database.NewMySQL(os.Getenv("CONN_STRING"))
+	redirects = usecase.NewRedirectUsecase(persistence.NewRedirectRepository(db))
+
+	images := persistence.NewImageRepository(db)
+	pages := persistence.NewPageRepository(db)
+	shortcodes.Register(images, pages, os.Getenv("MEDIA_URL"))
 }
 
 func handler(ctx context.Context, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
 	ctx = helper.PopulateContext(ctx, req)
-	res := getPageData(ctx, req.PathParameters["url"])
-	return helper.Response(ctx, res, req), nil
+	res := getPageData(ctx, req.PathParameters["url"], locales(req))
+	resp := helper.Response(ctx, res, req)
+
+	// the locale of the page's content can be negotiated from the Accept-Language header.
+	resp.Headers["Vary"] = "Accept-Language"
+
+	return resp, nil
 }
 
 func main() {
 	lambda.Start(handler)
 }
 
-func getPageData(ctx context.Context, url string) result.Result {
+// locales returns the locales the client would prefer the page in, from the "locale" query
+// parameter, or the Accept-Language header, as a comma-separated list in order of preference.
+// Translations are served from their own path, which takes precedence over these.
+func locales(req events.APIGatewayProxyRequest) *string {
+	var l []string
+	if v := req.QueryStringParameters["locale"]; v != "" {
+		l = locale.Fallbacks(v)
+	} else if v, ok := helper.Header(req, "Accept-Language"); ok {
+		l = locale.ParseAcceptLanguage(v)
+	}
+
+	if len(l) < 1 {
+		return nil
+	}
+
+	v := strings.Join(l, ",")
+	return &v
+}
+
+func getPageData(ctx context.Context, url string, locales *string) result.Result {
+	// the paths of child pages are escaped to fit in a single path parameter.
+	if u, err := neturl.PathUnescape(url); err == nil {
+		url = u
+	}
+
 	url = strings.ToLower(url)
 	if strings.HasPrefix(url, "blog-") {
 		url = "blog/" + url[5:]
 	}
 
-	const query string = "CALL `get_page_data_by_url`(?);"
-	data, err := db.Read(ctx, query, pageReader, url)
+	const query string = "CALL `get_page_data_by_url`(?, ?);"
+	data, err := db.Read(ctx, query, pageReader, url, locales)
 	if err != nil {
 		return result.Failure(err)
 	}
 
 	if data == nil {
-		return result.Failure("page not found").WithStatusCode(http.StatusNotFound)
+		return getRedirect(ctx, url)
 	}
 
-	return result.Ok().WithValue(data)
+	pd := data.(PageData)
+	if pd.Content != nil {
+		c := shortcode.Expand(ctx, *pd.Content)
+		pd.Content = &c
+	}
+
+	return result.Ok().WithValue(pd)
+}
+
+// getRedirect returns the redirect for the given url, if the page has moved,
+// allowing the site to redirect the client, rather than show a not found error.
+func getRedirect(ctx context.Context, url string) result.Result {
+	success, status, value, err := redirects.Resolve(ctx, url).Deconstruct()
+	if !success {
+		if status == http.StatusNotFound {
+			return result.Failure("page not found").WithStatusCode(http.StatusNotFound)
+		}
+
+		return result.Failure(err).WithStatusCode(status)
+	}
+
+	r := value.(*dto.Redirect)
+	return result.Ok().WithValue(&RedirectData{
+		Redirect: RedirectTarget{
+			Location: r.Target,
+			StatusCode: r.StatusCode,
+		},
+	})
+}
+
+type RedirectData struct {
+	Redirect RedirectTarget `json:"redirect"`
+}
+
+type RedirectTarget struct {
+	Location string `json:"location"`
+	StatusCode int `json:"statusCode"`
 }
 
 type PageData struct {
@@ -56,30 +135,89 @@ type PageData struct {
 	Title string `json:"title"`
 	Description string `json:"description"`
 	Content *string `json:"content"`
+	Outline json.RawMessage `json:"outline"`
+	WordCount int `json:"wordCount"`
+	ReadingTime int `json:"readingTime"`
+	Excerpt *string `json:"excerpt"`
+	Authors json.RawMessage `json:"authors"`
+	Series *SeriesData `json:"series"`
 	IsBlog bool `json:"isBlog"`
 	ImageID *string `json:"imageId"`
+	Locale string `json:"locale"`
+	Path string `json:"path"`
 	SEO SEOData `json:"seo"`
 }
 
+// SeriesData is the position of a blog in its series, with links
+// to the previous and next active blogs in the series.
+type SeriesData struct {
+	ID string `json:"id"`
+	Title string `json:"title"`
+	Position int `json:"position"`
+	Count int `json:"count"`
+	Previous *SeriesLink `json:"previous"`
+	Next *SeriesLink `json:"next"`
+}
+
+type SeriesLink struct {
+	Title string `json:"title"`
+	Path string `json:"path"`
+}
+
 type SEOData struct {
 	Title string `json:"title"`
 	Description string `json:"description"`
 	SiteName string `json:"siteName"`
 	Index bool `json:"index"`
 	Follow bool `json:"follow"`
+	Alternates []*Alternate `json:"alternates"`
+}
+
+// Alternate is a version of the page in another locale, used for hreflang links. The
+// "x-default" alternate is the page's own path, for clients which don't match a locale.
+type Alternate struct {
+	HrefLang string `json:"hreflang"`
+	Path string `json:"path"`
 }
 
 func pageReader(s database.ScannerFunc) (interface{}, error) {
 	var data PageData
 	var content sql.NullString
+	var outline sql.NullString
+	var excerpt sql.NullString
+	var authors sql.NullString
+	var seriesID, seriesTitle sql.NullString
+	var seriesPosition, seriesCount sql.NullInt64
+	var previousTitle, previousPath sql.NullString
+	var nextTitle, nextPath sql.NullString
 	var imageID sql.NullString
+	var defaultLocale, defaultPath string
+	var alternates sql.NullString
 	err := s(
 		&data.ID,
 		&data.Title,
 		&data.Description,
 		&content,
+		&outline,
+		&data.WordCount,
+		&data.ReadingTime,
+		&excerpt,
+		&authors,
+		&seriesID,
+		&seriesTitle,
+		&seriesPosition,
+		&seriesCount,
+		&previousTitle,
+		&previousPath,
+		&nextTitle,
+		&nextPath,
 		&data.IsBlog,
 		&imageID,
+		&data.Locale,
+		&data.Path,
+		&defaultLocale,
+		&defaultPath,
+		&alternates,
 		&data.SEO.Title,
 		&data.SEO.Description,
 		&data.SEO.SiteName,
@@ -94,9 +232,62 @@ func pageReader(s database.ScannerFunc) (interface{}, error) {
 		data.Content = &content.String
 	}
 
+	// pages which haven't been saved since outlines were
+	// introduced won't have one, so default to an empty list.
+	data.Outline = json.RawMessage("[]")
+	if outline.Valid {
+		data.Outline = json.RawMessage(outline.String)
+	}
+
+	if excerpt.Valid {
+		data.Excerpt = &excerpt.String
+	}
+
+	data.Authors = json.RawMessage("[]")
+	if authors.Valid {
+		data.Authors = json.RawMessage(authors.String)
+	}
+
+	if seriesID.Valid {
+		data.Series = &SeriesData{
+			ID: seriesID.String,
+			Title: seriesTitle.String,
+			Position: int(seriesPosition.Int64),
+			Count: int(seriesCount.Int64),
+		}
+
+		if previousPath.Valid {
+			data.Series.Previous = &SeriesLink{
+				Title: previousTitle.String,
+				Path: previousPath.String,
+			}
+		}
+
+		if nextPath.Valid {
+			data.Series.Next = &SeriesLink{
+				Title: nextTitle.String,
+				Path: nextPath.String,
+			}
+		}
+	}
+
 	if imageID.Valid {
 		data.ImageID = &imageID.String
 	}
 
+	// hreflang links are only needed when the page has been translated.
+	data.SEO.Alternates = []*Alternate{}
+	if alternates.Valid {
+		var translations []*Alternate
+		err = json.Unmarshal([]byte(alternates.String), &translations)
+		if err != nil {
+			return nil, err
+		}
+
+		data.SEO.Alternates = append(data.SEO.Alternates, &Alternate{HrefLang: defaultLocale, Path: defaultPath})
+		data.SEO.Alternates = append(data.SEO.Alternates, translations...)
+		data.SEO.Alternates = append(data.SEO.Alternates, &Alternate{HrefLang: "x-default", Path: defaultPath})
+	}
+
 	return data, nil
 }
\ No newline at end of file
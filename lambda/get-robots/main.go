@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"os"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+
+	"github.com/reecerussell/distro-blog/domain/dto"
+	"github.com/reecerussell/distro-blog/libraries/database"
+	"github.com/reecerussell/distro-blog/libraries/helper"
+	"github.com/reecerussell/distro-blog/persistence"
+	"github.com/reecerussell/distro-blog/usecase"
+)
+
+// robotsMaxAge is the number of seconds clients may cache robots.txt for.
+const robotsMaxAge = 3600
+
+var sitemaps usecase.SitemapUsecase
+
+func init() {
+	db := database.NewMySQL(os.Getenv("CONN_STRING"))
+	pageRepo := persistence.NewPageRepository(db)
+	settingRepo := persistence.NewSettingRepository(db)
+	sitemaps = usecase.NewSitemapUsecase(pageRepo, settingRepo, os.Getenv("SITE_URL"))
+}
+
+// handleRobots handles incoming, unauthenticated, API Gateway requests for robots.txt.
+func handleRobots(ctx context.Context, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	ctx = helper.PopulateContext(ctx, req)
+
+	res := sitemaps.Robots(ctx)
+	success, _, value, _ := res.Deconstruct()
+	if !success {
+		return helper.Response(ctx, res, req), nil
+	}
+
+	d := value.(*dto.Document)
+	return helper.ContentResponse(ctx, req, d.ContentType, d.Body, d.LastModified, robotsMaxAge), nil
+}
+
+func main() {
+	lambda.Start(handleRobots)
+}
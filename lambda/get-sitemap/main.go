@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"os"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+
+	"github.com/reecerussell/distro-blog/domain/dto"
+	"github.com/reecerussell/distro-blog/libraries/database"
+	"github.com/reecerussell/distro-blog/libraries/helper"
+	"github.com/reecerussell/distro-blog/libraries/result"
+	"github.com/reecerussell/distro-blog/persistence"
+	"github.com/reecerussell/distro-blog/usecase"
+)
+
+// sitemapMaxAge is the number of seconds clients may cache the sitemap for.
+const sitemapMaxAge = 3600
+
+var sitemaps usecase.SitemapUsecase
+
+func init() {
+	db := database.NewMySQL(os.Getenv("CONN_STRING"))
+	pageRepo := persistence.NewPageRepository(db)
+	settingRepo := persistence.NewSettingRepository(db)
+	sitemaps = usecase.NewSitemapUsecase(pageRepo, settingRepo, os.Getenv("SITE_URL"))
+}
+
+// handleSitemap handles incoming, unauthenticated, API Gateway requests for the sitemap.
+func handleSitemap(ctx context.Context, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	ctx = helper.PopulateContext(ctx, req)
+
+	var q dto.SitemapQuery
+	err := helper.ReadQuery(req, &q)
+	if err != nil {
+		br := result.Failure(err).WithStatusCode(http.StatusBadRequest)
+		return helper.Response(ctx, br, req), nil
+	}
+
+	res := sitemaps.Sitemap(ctx, &q)
+	success, _, value, _ := res.Deconstruct()
+	if !success {
+		return helper.Response(ctx, res, req), nil
+	}
+
+	d := value.(*dto.Document)
+	return helper.ContentResponse(ctx, req, d.ContentType, d.Body, d.LastModified, sitemapMaxAge), nil
+}
+
+func main() {
+	lambda.Start(handleSitemap)
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"os"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+
+	"github.com/reecerussell/distro-blog/domain/dto"
+	"github.com/reecerussell/distro-blog/libraries/database"
+	"github.com/reecerussell/distro-blog/libraries/helper"
+	"github.com/reecerussell/distro-blog/libraries/result"
+	"github.com/reecerussell/distro-blog/persistence"
+	"github.com/reecerussell/distro-blog/usecase"
+)
+
+var authors usecase.AuthorUsecase
+
+func init() {
+	db := database.NewMySQL(os.Getenv("CONN_STRING"))
+	repo := persistence.NewAuthorRepository(db)
+	users := persistence.NewUserRepository(db)
+	authors = usecase.NewAuthorUsecase(repo, users, persistence.NewPageRepository(db), persistence.NewImageRepository(db))
+}
+
+// handleList handles incoming, unauthenticated, API Gateway requests
+// to list the published blogs written by an author.
+func handleList(ctx context.Context, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	ctx = helper.PopulateContext(ctx, req)
+
+	var q dto.PagingQuery
+	err := helper.ReadQuery(req, &q)
+	if err != nil {
+		br := result.Failure(err).WithStatusCode(http.StatusBadRequest)
+		return helper.Response(ctx, br, req), nil
+	}
+
+	res := authors.ListPosts(ctx, req.PathParameters["id"], &q)
+	return helper.Response(ctx, res, req), nil
+}
+
+func main() {
+	lambda.Start(handleList)
+}
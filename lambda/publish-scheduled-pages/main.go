@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"os"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+
+	"github.com/reecerussell/distro-blog/libraries/contextkey"
+	"github.com/reecerussell/distro-blog/libraries/database"
+	"github.com/reecerussell/distro-blog/libraries/logging"
+	"github.com/reecerussell/distro-blog/persistence"
+	"github.com/reecerussell/distro-blog/usecase"
+)
+
+var (
+	pages usecase.PageUsecase
+	schedulerUserID string
+)
+
+func init() {
+	// audit records must be attributed to a user, so they aren't dropped.
+	schedulerUserID = os.Getenv("SCHEDULER_USER_ID")
+	if schedulerUserID == "" {
+		panic("SCHEDULER_USER_ID must be set to the id of the user changes are made by")
+	}
+
+	db := database.NewMySQL(os.Getenv("CONN_STRING"))
+	repo := persistence.NewPageRepository(db)
+	related := usecase.NewRelatedUsecase(persistence.NewRelatedPostRepository(db))
+	pages = usecase.NewPageUsecase(repo, nil, related)
+}
+
+// handleSchedule handles scheduled CloudWatch events, activating and deactivating
+// any pages which are due. As there is no user making the request, audit records
+// are attributed to the user with the id in the SCHEDULER_USER_ID variable.
+func handleSchedule(ctx context.Context, e events.CloudWatchEvent) error {
+	ctx = context.WithValue(ctx, contextkey.ContextKey("user_id"), schedulerUserID)
+
+	success, _, value, err := pages.PublishScheduled(ctx).Deconstruct()
+	if !success {
+		return err
+	}
+
+	logging.Debugf("Updated %d scheduled pages.\n", value.(int))
+
+	return nil
+}
+
+func main() {
+	lambda.Start(handleSchedule)
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+
+	"github.com/reecerussell/distro-blog/libraries/contextkey"
+	"github.com/reecerussell/distro-blog/libraries/database"
+	"github.com/reecerussell/distro-blog/persistence"
+	"github.com/reecerussell/distro-blog/usecase"
+)
+
+// defaultRetentionDays is the number of days a page is kept
+// in the trash, if TRASH_RETENTION_DAYS is not set.
+const defaultRetentionDays = 30
+
+var (
+	pages usecase.PageUsecase
+	retention time.Duration
+	schedulerUserID string
+)
+
+func init() {
+	// audit records must be attributed to a user, so they aren't dropped.
+	schedulerUserID = os.Getenv("SCHEDULER_USER_ID")
+	if schedulerUserID == "" {
+		panic("SCHEDULER_USER_ID must be set to the id of the user changes are made by")
+	}
+
+	db := database.NewMySQL(os.Getenv("CONN_STRING"))
+	repo := persistence.NewPageRepository(db)
+
+	ir := persistence.NewImageRepository(db)
+	itr := persistence.NewImageTypeRepository(db)
+	media, err := usecase.NewMediaUsecase(ir, itr)
+	if err != nil {
+		panic(err)
+	}
+
+	pages = usecase.NewPageUsecase(repo, media, nil)
+
+	days := defaultRetentionDays
+	if v := os.Getenv("TRASH_RETENTION_DAYS"); v != "" {
+		days, err = strconv.Atoi(v)
+		if err != nil || days < 0 {
+			panic("TRASH_RETENTION_DAYS must be a non-negative number of days")
+		}
+	}
+
+	retention = time.Duration(days) * 24 * time.Hour
+}
+
+// handleSchedule handles scheduled CloudWatch events, permanently deleting any pages
+// which have been in the trash for longer than the retention period. As there is no
+// user making the request, the context is populated with the SCHEDULER_USER_ID variable.
+func handleSchedule(ctx context.Context, e events.CloudWatchEvent) error {
+	ctx = context.WithValue(ctx, contextkey.ContextKey("user_id"), schedulerUserID)
+
+	success, _, _, err := pages.PurgeTrash(ctx, retention).Deconstruct()
+	if !success {
+		return err
+	}
+
+	return nil
+}
+
+func main() {
+	lambda.Start(handleSchedule)
+}
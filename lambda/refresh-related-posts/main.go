@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"os"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+
+	"github.com/reecerussell/distro-blog/libraries/database"
+	"github.com/reecerussell/distro-blog/libraries/logging"
+	"github.com/reecerussell/distro-blog/persistence"
+	"github.com/reecerussell/distro-blog/usecase"
+)
+
+var related usecase.RelatedUsecase
+
+func init() {
+	db := database.NewMySQL(os.Getenv("CONN_STRING"))
+	repo := persistence.NewRelatedPostRepository(db)
+	related = usecase.NewRelatedUsecase(repo)
+}
+
+// handleSchedule handles scheduled CloudWatch events, refreshing the related
+// posts if a blog has been changed since they were last refreshed.
+func handleSchedule(ctx context.Context, e events.CloudWatchEvent) error {
+	success, _, value, err := related.RefreshIfRequested(ctx).Deconstruct()
+	if !success {
+		return err
+	}
+
+	if value.(bool) {
+		logging.Debugf("Refreshed the related posts.\n")
+	}
+
+	return nil
+}
+
+func main() {
+	lambda.Start(handleSchedule)
+}
@@ -0,0 +1,88 @@
+package content
+
+import (
+	"html"
+	"strings"
+	"unicode"
+)
+
+// DefaultExcerptLength is the default maximum number of characters in an excerpt.
+const DefaultExcerptLength = 200
+
+// tags which contain text that should never be shown as plain text.
+var skippedTags = map[string]bool{
+	"script": true,
+	"style": true,
+}
+
+// PlainText strips the HTML tags from s, returning the text content with
+// entities decoded and runs of whitespace collapsed into a single space.
+func PlainText(s string) string {
+	var b strings.Builder
+	skipping := ""
+
+	for len(s) > 0 {
+		i := strings.IndexByte(s, '<')
+		if i < 0 {
+			if skipping == "" {
+				b.WriteString(s)
+			}
+			break
+		}
+
+		if skipping == "" {
+			b.WriteString(s[:i])
+		}
+
+		end := strings.IndexByte(s[i:], '>')
+		if end < 0 {
+			break
+		}
+
+		name, closing := tagName(s[i+1 : i+end])
+		switch true {
+		case skippedTags[name] && !closing:
+			skipping = name
+		case closing && name == skipping:
+			skipping = ""
+		}
+
+		// tags separate words, e.g. "<p>one</p><p>two</p>".
+		b.WriteByte(' ')
+		s = s[i+end+1:]
+	}
+
+	return strings.Join(strings.FieldsFunc(html.UnescapeString(b.String()), unicode.IsSpace), " ")
+}
+
+// tagName returns the lowercase name of the tag, and whether it is a closing tag.
+func tagName(tag string) (string, bool) {
+	closing := strings.HasPrefix(tag, "/")
+	tag = strings.TrimPrefix(tag, "/")
+
+	end := strings.IndexFunc(tag, func(r rune) bool {
+		return unicode.IsSpace(r) || r == '/'
+	})
+	if end >= 0 {
+		tag = tag[:end]
+	}
+
+	return strings.ToLower(tag), closing
+}
+
+// Excerpt returns the plain text of the HTML content, shortened to at most
+// max characters. Text is cut at a word boundary and suffixed with an ellipsis.
+func Excerpt(s string, max int) string {
+	text := PlainText(s)
+	runes := []rune(text)
+	if len(runes) <= max {
+		return text
+	}
+
+	cut := string(runes[:max])
+	if i := strings.LastIndexByte(cut, ' '); i > 0 {
+		cut = cut[:i]
+	}
+
+	return strings.TrimRightFunc(cut, unicode.IsPunct) + "…"
+}
@@ -0,0 +1,33 @@
+package content
+
+import "testing"
+
+func TestPlainText(t *testing.T) {
+	tests := map[string]string{
+		"<p>Hello <strong>world</strong></p>": "Hello world",
+		"<h1>Title</h1><p>Body</p>": "Title Body",
+		"Fish &amp; chips": "Fish & chips",
+		"<style>p { color: red; }</style><p>Text</p>": "Text",
+		"  lots\n\tof   space ": "lots of space",
+		"<img src=\"a.png\"/>Caption": "Caption",
+	}
+
+	for input, expected := range tests {
+		if v := PlainText(input); v != expected {
+			t.Errorf("expected '%s' but got '%s'", expected, v)
+		}
+	}
+}
+
+func TestExcerpt(t *testing.T) {
+	s := "<p>The quick brown fox jumps over the lazy dog.</p>"
+
+	if v := Excerpt(s, 100); v != "The quick brown fox jumps over the lazy dog." {
+		t.Errorf("expected the full text but got '%s'", v)
+	}
+
+	expected := "The quick brown…"
+	if v := Excerpt(s, 18); v != expected {
+		t.Errorf("expected '%s' but got '%s'", expected, v)
+	}
+}
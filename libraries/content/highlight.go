@@ -0,0 +1,116 @@
+package content
+
+import (
+	"html"
+	"strings"
+	"unicode"
+)
+
+// Terms splits a search query into lowercase terms, ignoring any
+// punctuation around each word, such as quotes. Duplicates are removed.
+func Terms(query string) []string {
+	var terms []string
+	seen := make(map[string]bool)
+
+	for _, f := range strings.Fields(strings.ToLower(query)) {
+		t := strings.TrimFunc(f, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+		})
+
+		if t != "" && !seen[t] {
+			seen[t] = true
+			terms = append(terms, t)
+		}
+	}
+
+	return terms
+}
+
+// Highlight returns a snippet of the plain text, s, of at most max characters,
+// starting shortly before the first of the terms found in it. Each occurrence of
+// the terms is wrapped in a <mark> element, and the rest of the text is escaped.
+// The snippet is prefixed or suffixed with an ellipsis if the text was cut.
+func Highlight(s string, terms []string, max int) string {
+	text := []rune(s)
+	lower := make([]rune, len(text))
+	for i, r := range text {
+		lower[i] = unicode.ToLower(r)
+	}
+
+	needles := make([][]rune, len(terms))
+	for i, t := range terms {
+		needles[i] = []rune(strings.ToLower(t))
+	}
+
+	start := 0
+	if i, _ := nextMatch(lower, needles, 0); i > max/4 {
+		start = i - max/4
+
+		// start at the beginning of a word.
+		for start < i && !unicode.IsSpace(text[start-1]) {
+			start++
+		}
+	}
+
+	end := start + max
+	if end > len(text) {
+		end = len(text)
+	}
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("…")
+	}
+
+	for pos := start; pos < end; {
+		i, l := nextMatch(lower[:end], needles, pos)
+		if i < 0 {
+			b.WriteString(html.EscapeString(string(text[pos:end])))
+			break
+		}
+
+		b.WriteString(html.EscapeString(string(text[pos:i])))
+		b.WriteString("<mark>" + html.EscapeString(string(text[i:i+l])) + "</mark>")
+		pos = i + l
+	}
+
+	if end < len(text) {
+		b.WriteString("…")
+	}
+
+	return b.String()
+}
+
+// nextMatch returns the index and length of the first needle found in s, from
+// the given position. The longest needle is used if several match at the same
+// index. An index of -1 is returned if none of the needles are found.
+func nextMatch(s []rune, needles [][]rune, from int) (int, int) {
+	for i := from; i < len(s); i++ {
+		l := 0
+		for _, n := range needles {
+			if len(n) > l && hasPrefix(s[i:], n) {
+				l = len(n)
+			}
+		}
+
+		if l > 0 {
+			return i, l
+		}
+	}
+
+	return -1, 0
+}
+
+func hasPrefix(s, prefix []rune) bool {
+	if len(prefix) > len(s) {
+		return false
+	}
+
+	for i, r := range prefix {
+		if s[i] != r {
+			return false
+		}
+	}
+
+	return true
+}
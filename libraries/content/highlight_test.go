@@ -0,0 +1,34 @@
+package content
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTerms(t *testing.T) {
+	expected := []string{"golang", "lambda", "c"}
+	if v := Terms(`"Golang" lambda, golang C++`); !reflect.DeepEqual(v, expected) {
+		t.Errorf("expected %v but got %v", expected, v)
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	tests := []struct {
+		text string
+		terms []string
+		max int
+		expected string
+	}{
+		{"Deploying Go to AWS Lambda", []string{"lambda"}, 100, "Deploying Go to AWS <mark>Lambda</mark>"},
+		{"Fish & chips & fish", []string{"fish"}, 100, "<mark>Fish</mark> &amp; chips &amp; <mark>fish</mark>"},
+		{"no matches here", []string{"zebra"}, 7, "no matc…"},
+		{strings.Repeat("filler ", 20) + "needle in the haystack", []string{"needle"}, 20, "…<mark>needle</mark> in the haysta…"},
+	}
+
+	for _, test := range tests {
+		if v := Highlight(test.text, test.terms, test.max); v != test.expected {
+			t.Errorf("expected '%s' but got '%s'", test.expected, v)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package content
+
+import (
+	"bytes"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+)
+
+// markdown is the renderer used to convert Markdown to HTML. It supports
+// GitHub-flavoured Markdown, such as tables, and gives each heading an id,
+// so it can be linked to. Raw HTML and unsafe links are not rendered.
+var markdown = goldmark.New(
+	goldmark.WithExtensions(extension.GFM),
+	goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+)
+
+// RenderMarkdown converts the Markdown source, src, to HTML.
+func RenderMarkdown(src string) (string, error) {
+	var buf bytes.Buffer
+	err := markdown.Convert([]byte(src), &buf)
+	if err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
@@ -0,0 +1,49 @@
+package content
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	tests := map[string]string{
+		"# Getting Started": `<h1 id="getting-started">Getting Started</h1>`,
+		"Some **bold** text": "<p>Some <strong>bold</strong> text</p>",
+		"| A | B |\n|---|---|\n| 1 | 2 |": "<td>1</td>",
+		"```go\nfmt.Println()\n```": `<pre><code class="language-go">fmt.Println()`,
+		"~~gone~~": "<del>gone</del>",
+	}
+
+	for src, expected := range tests {
+		h, err := RenderMarkdown(src)
+		if err != nil {
+			t.Errorf("expected no error but got: %v", err)
+			continue
+		}
+
+		if !strings.Contains(h, expected) {
+			t.Errorf("expected '%s' to contain '%s'", h, expected)
+		}
+	}
+}
+
+func TestRenderMarkdownWithUnsafeContent(t *testing.T) {
+	tests := []string{
+		"<script>alert(1)</script>",
+		"[click](javascript:alert(1))",
+		"<img src=x onerror=alert(1)>",
+	}
+
+	for _, src := range tests {
+		h, err := RenderMarkdown(src)
+		if err != nil {
+			t.Errorf("expected no error but got: %v", err)
+			continue
+		}
+
+		l := strings.ToLower(h)
+		if strings.Contains(l, "<script") || strings.Contains(l, "javascript:") || strings.Contains(l, "onerror") {
+			t.Errorf("expected '%s' to be rendered safely but got '%s'", src, h)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package content
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// WordsPerMinute is the average reading speed used to estimate reading time.
+const WordsPerMinute = 200
+
+// Heading is a heading in HTML content, which can be linked to using its id.
+type Heading struct {
+	Level int `json:"level"`
+	ID string `json:"id"`
+	Text string `json:"text"`
+}
+
+var (
+	headingRegex = regexp.MustCompile(`(?is)<h([1-6])(\s[^>]*)?>(.*?)</h([1-6])>`)
+	idAttrRegex = regexp.MustCompile(`(?i)\sid\s*=\s*"([^"]*)"`)
+)
+
+// AnchorHeadings gives each heading in the HTML content, s, an id, so it can be
+// linked to, returning the updated content and an outline of the headings. Ids
+// are generated from the heading's text, so they stay the same unless the text
+// changes. Headings which already have an id keep it, and duplicate ids are
+// suffixed with a number, for example "setup-2".
+func AnchorHeadings(s string) (string, []*Heading) {
+	var outline []*Heading
+	used := make(map[string]bool)
+
+	// reserve existing ids first, so a generated id never takes
+	// the place of one which was written by the author.
+	for _, m := range headingRegex.FindAllStringSubmatch(s, -1) {
+		if id := idAttrRegex.FindStringSubmatch(m[2]); id != nil {
+			used[html.UnescapeString(id[1])] = true
+		}
+	}
+
+	s = headingRegex.ReplaceAllStringFunc(s, func(tag string) string {
+		m := headingRegex.FindStringSubmatch(tag)
+		if m[1] != m[4] {
+			return tag
+		}
+
+		level, _ := strconv.Atoi(m[1])
+		h := &Heading{
+			Level: level,
+			Text: PlainText(m[3]),
+		}
+		outline = append(outline, h)
+
+		if id := idAttrRegex.FindStringSubmatch(m[2]); id != nil {
+			h.ID = html.UnescapeString(id[1])
+			return tag
+		}
+
+		h.ID = uniqueID(Slug(h.Text), used)
+		return fmt.Sprintf(`<h%d id="%s"%s>%s</h%d>`, level, html.EscapeString(h.ID), m[2], m[3], level)
+	})
+
+	return s, outline
+}
+
+func uniqueID(id string, used map[string]bool) string {
+	if id == "" {
+		id = "heading"
+	}
+
+	unique := id
+	for i := 2; used[unique]; i++ {
+		unique = id + "-" + strconv.Itoa(i)
+	}
+
+	used[unique] = true
+
+	return unique
+}
+
+// Slug returns a lowercase, hyphenated version of s, containing only
+// letters, numbers and hyphens. For example, "Getting Started!" is
+// returned as "getting-started".
+func Slug(s string) string {
+	var b strings.Builder
+	hyphen := false
+
+	for _, r := range strings.ToLower(s) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsNumber(r):
+			if hyphen && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+
+			b.WriteRune(r)
+			hyphen = false
+		default:
+			hyphen = true
+		}
+	}
+
+	return b.String()
+}
+
+// WordCount returns the number of words in the text of the HTML content, s.
+func WordCount(s string) int {
+	return len(strings.Fields(PlainText(s)))
+}
+
+// ReadingTime returns the estimated number of minutes it takes to read the given
+// number of words, rounded up. Content with any words takes at least a minute.
+func ReadingTime(words int) int {
+	return (words + WordsPerMinute - 1) / WordsPerMinute
+}
@@ -0,0 +1,62 @@
+package content
+
+import "testing"
+
+func TestAnchorHeadings(t *testing.T) {
+	s := `<h2>Getting Started</h2><p>Text</p><h3 class="sub">Install &amp; Run</h3>` +
+		`<h2>Setup</h2><h2 id="setup">Setup</h2><h2>Getting Started</h2>`
+
+	out, outline := AnchorHeadings(s)
+
+	expected := `<h2 id="getting-started">Getting Started</h2><p>Text</p><h3 id="install-run" class="sub">Install &amp; Run</h3>` +
+		`<h2 id="setup-2">Setup</h2><h2 id="setup">Setup</h2><h2 id="getting-started-2">Getting Started</h2>`
+	if out != expected {
+		t.Errorf("expected '%s' but got '%s'", expected, out)
+	}
+
+	ids := []string{"getting-started", "install-run", "setup-2", "setup", "getting-started-2"}
+	if len(outline) != len(ids) {
+		t.Fatalf("expected %d headings but got %d", len(ids), len(outline))
+	}
+
+	for i, h := range outline {
+		if h.ID != ids[i] {
+			t.Errorf("expected heading %d to have the id '%s' but got '%s'", i, ids[i], h.ID)
+		}
+	}
+
+	if outline[1].Level != 3 || outline[1].Text != "Install & Run" {
+		t.Errorf("expected a level 3 heading 'Install & Run' but got: %v", outline[1])
+	}
+}
+
+func TestSlug(t *testing.T) {
+	tests := map[string]string{
+		"Getting Started!": "getting-started",
+		"  What's new in v2.0? ": "what-s-new-in-v2-0",
+		"Café au lait": "café-au-lait",
+		"!!!": "",
+	}
+
+	for input, expected := range tests {
+		if v := Slug(input); v != expected {
+			t.Errorf("expected '%s' but got '%s'", expected, v)
+		}
+	}
+}
+
+func TestReadingTime(t *testing.T) {
+	tests := map[int]int{
+		0: 0,
+		1: 1,
+		200: 1,
+		201: 2,
+		1000: 5,
+	}
+
+	for words, expected := range tests {
+		if v := ReadingTime(words); v != expected {
+			t.Errorf("expected %d words to take %d minutes but got %d", words, expected, v)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package content
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// TitleWeight is the number of times each word in a document's title is
+// counted, so shared words in titles count for more than those in the text.
+const TitleWeight = 3
+
+// Document is a piece of content which can be compared with others, to find
+// the most related. Text should be plain text, rather than HTML.
+type Document struct {
+	ID string
+	Title string
+	Text string
+}
+
+// Match is a document which is related to another, with a score between 0 and 1.
+type Match struct {
+	ID string
+	Score float64
+}
+
+// common English words which say nothing about what a document is about.
+var stopWords = toSet(`a about above after again against all am an and any are as at be because been
+	before being below between both but by can could did do does doing down during each few for from
+	further had has have having he her here hers herself him himself his how i if in into is it its itself
+	just me more most my myself no nor not now of off on once only or other our ours ourselves out over own
+	same she should so some such than that the their theirs them themselves then there these they this
+	those through to too under until up very was we were what when where which while who whom why will
+	with would you your yours yourself yourselves`)
+
+func toSet(s string) map[string]bool {
+	set := make(map[string]bool)
+	for _, w := range strings.Fields(s) {
+		set[w] = true
+	}
+
+	return set
+}
+
+// Related scores how related each document is to each of the others, using the
+// cosine similarity of their TF-IDF vectors, returning up to n matches for each
+// document, keyed by its id, with the most related first. Documents which don't
+// share any words aren't matched.
+func Related(docs []*Document, n int) map[string][]*Match {
+	vectors := make([]map[string]float64, len(docs))
+	df := make(map[string]int)
+
+	for i, d := range docs {
+		tf := make(map[string]float64)
+		for _, w := range words(d.Title) {
+			tf[w] += TitleWeight
+		}
+
+		for _, w := range words(d.Text) {
+			tf[w]++
+		}
+
+		for w := range tf {
+			df[w]++
+		}
+
+		vectors[i] = tf
+	}
+
+	// weight each word by how rare it is across all of the documents, using
+	// a smoothed inverse document frequency, then normalize each vector.
+	for _, v := range vectors {
+		var norm float64
+		for w, f := range v {
+			v[w] = f * (math.Log(float64(1+len(docs))/float64(1+df[w])) + 1)
+			norm += v[w] * v[w]
+		}
+
+		norm = math.Sqrt(norm)
+		for w := range v {
+			v[w] /= norm
+		}
+	}
+
+	related := make(map[string][]*Match, len(docs))
+
+	for i, d := range docs {
+		var matches []*Match
+		for j, o := range docs {
+			if i == j {
+				continue
+			}
+
+			if s := dot(vectors[i], vectors[j]); s > 0 {
+				matches = append(matches, &Match{ID: o.ID, Score: s})
+			}
+		}
+
+		sort.Slice(matches, func(a, b int) bool {
+			if matches[a].Score == matches[b].Score {
+				return matches[a].ID < matches[b].ID
+			}
+
+			return matches[a].Score > matches[b].Score
+		})
+
+		if len(matches) > n {
+			matches = matches[:n]
+		}
+
+		related[d.ID] = matches
+	}
+
+	return related
+}
+
+// words splits s into lowercase words, without stop words, numbers or single letters.
+func words(s string) []string {
+	var ws []string
+	for _, f := range strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	}) {
+		if len([]rune(f)) < 2 || stopWords[f] || strings.IndexFunc(f, unicode.IsLetter) < 0 {
+			continue
+		}
+
+		ws = append(ws, f)
+	}
+
+	return ws
+}
+
+func dot(a, b map[string]float64) float64 {
+	if len(b) < len(a) {
+		a, b = b, a
+	}
+
+	var s float64
+	for w, v := range a {
+		s += v * b[w]
+	}
+
+	return s
+}
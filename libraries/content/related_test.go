@@ -0,0 +1,43 @@
+package content
+
+import "testing"
+
+func TestRelated(t *testing.T) {
+	docs := []*Document{
+		{ID: "go-lambda", Title: "Deploying Go to AWS Lambda", Text: "Build a Go binary and deploy it as a Lambda function."},
+		{ID: "go-testing", Title: "Testing in Go", Text: "Table driven tests are common in Go code."},
+		{ID: "lambda-layers", Title: "AWS Lambda layers", Text: "Layers let Lambda functions share code."},
+		{ID: "baking", Title: "Baking bread", Text: "Flour, water, salt and yeast."},
+	}
+
+	related := Related(docs, 2)
+
+	m := related["go-lambda"]
+	if len(m) != 2 || m[0].ID != "lambda-layers" || m[1].ID != "go-testing" {
+		t.Fatalf("expected 'lambda-layers' then 'go-testing' but got %v", m)
+	}
+
+	if m[0].Score <= m[1].Score || m[0].Score > 1 {
+		t.Errorf("expected scores to be descending and at most 1 but got %f and %f", m[0].Score, m[1].Score)
+	}
+
+	if len(related["baking"]) != 0 {
+		t.Errorf("expected no matches for an unrelated document but got %v", related["baking"])
+	}
+}
+
+func TestRelated_IgnoresStopWords(t *testing.T) {
+	docs := []*Document{
+		{ID: "1", Title: "The best of the year", Text: "and it was"},
+		{ID: "2", Title: "Fishing", Text: "The year and it was"},
+	}
+
+	if m := Related(docs, 5)["1"]; len(m) != 1 {
+		t.Fatalf("expected a single match on 'year' but got %v", m)
+	}
+
+	docs[1].Text = "and it was the of"
+	if m := Related(docs, 5)["1"]; len(m) != 0 {
+		t.Errorf("expected stop words not to match but got %v", m)
+	}
+}
@@ -0,0 +1,246 @@
+package diff
+
+import (
+	"html"
+	"strings"
+	"unicode"
+)
+
+// Operation determines how a piece of text has changed between
+// the two versions being compared.
+type Operation int
+
+// Supported diff operations.
+const (
+	Equal Operation = iota
+	Insert
+	Delete
+)
+
+// String returns a readable name for the operation.
+func (op Operation) String() string {
+	switch op {
+	case Insert:
+		return "insert"
+	case Delete:
+		return "delete"
+	default:
+		return "equal"
+	}
+}
+
+// Change is a single segment of a diff.
+type Change struct {
+	Op Operation
+	Text string
+}
+
+// Lines compares a and b line by line, returning the changes required
+// to turn a into b. Each line retains its trailing newline.
+func Lines(a, b string) []Change {
+	return compute(splitLines(a), splitLines(b))
+}
+
+// Words compares a and b word by word, returning the changes required
+// to turn a into b. Whitespace is treated as its own token, so joining
+// the text of every non-deleted change will always reproduce b.
+func Words(a, b string) []Change {
+	return compute(splitWords(a), splitWords(b))
+}
+
+// HTML renders the given changes as escaped HTML, wrapping inserted text in
+// <ins> tags and deleted text in <del> tags. The text is always escaped, so
+// any HTML in the compared values is displayed as source, not rendered.
+func HTML(changes []Change) string {
+	var b strings.Builder
+
+	for _, c := range changes {
+		text := html.EscapeString(c.Text)
+
+		switch c.Op {
+		case Insert:
+			b.WriteString("<ins>")
+			b.WriteString(text)
+			b.WriteString("</ins>")
+		case Delete:
+			b.WriteString("<del>")
+			b.WriteString(text)
+			b.WriteString("</del>")
+		default:
+			b.WriteString(text)
+		}
+	}
+
+	return b.String()
+}
+
+// HasChanges returns true if any of the changes are an insert or delete.
+func HasChanges(changes []Change) bool {
+	for _, c := range changes {
+		if c.Op != Equal {
+			return true
+		}
+	}
+
+	return false
+}
+
+// compute trims the common prefix and suffix of the two token slices,
+// then runs the Myers diff algorithm over what remains.
+func compute(a, b []string) []Change {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix &&
+		a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var changes []Change
+	changes = appendChange(changes, Equal, a[:prefix]...)
+	changes = append(changes, myers(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	changes = appendChange(changes, Equal, a[len(a)-suffix:]...)
+
+	return changes
+}
+
+// myers implements the greedy algorithm described in Eugene W. Myers'
+// "An O(ND) Difference Algorithm and Its Variations".
+func myers(a, b []string) []Change {
+	n, m := len(a), len(b)
+	if n == 0 && m == 0 {
+		return nil
+	}
+
+	max := n + m
+	offset := max + 1
+	v := make([]int, 2*max+2)
+	var trace [][]int
+
+	for d := 0; d <= max; d++ {
+		snapshot := make([]int, len(v))
+		copy(snapshot, v)
+		trace = append(trace, snapshot)
+
+		done := false
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+
+			v[offset+k] = x
+
+			if x >= n && y >= m {
+				done = true
+				break
+			}
+		}
+
+		if done {
+			break
+		}
+	}
+
+	return backtrack(trace, a, b, offset)
+}
+
+// backtrack walks the trace generated by myers backwards, to build
+// the list of changes in order.
+func backtrack(trace [][]int, a, b []string, offset int) []Change {
+	x, y := len(a), len(b)
+	var reversed []Change
+
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		k := x - y
+
+		var prevK int
+		if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+
+		prevX := v[offset+prevK]
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			x--
+			y--
+			reversed = append(reversed, Change{Op: Equal, Text: a[x]})
+		}
+
+		if d > 0 {
+			if x == prevX {
+				y--
+				reversed = append(reversed, Change{Op: Insert, Text: b[y]})
+			} else {
+				x--
+				reversed = append(reversed, Change{Op: Delete, Text: a[x]})
+			}
+		}
+	}
+
+	var changes []Change
+	for i := len(reversed) - 1; i >= 0; i-- {
+		changes = appendChange(changes, reversed[i].Op, reversed[i].Text)
+	}
+
+	return changes
+}
+
+// appendChange appends the tokens to changes, merging them into the
+// last change if it has the same operation.
+func appendChange(changes []Change, op Operation, tokens ...string) []Change {
+	if len(tokens) < 1 {
+		return changes
+	}
+
+	text := strings.Join(tokens, "")
+	if l := len(changes); l > 0 && changes[l-1].Op == op {
+		changes[l-1].Text += text
+		return changes
+	}
+
+	return append(changes, Change{Op: op, Text: text})
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return lines
+}
+
+func splitWords(s string) []string {
+	var tokens []string
+	start := 0
+	runes := []rune(s)
+
+	for i := 1; i <= len(runes); i++ {
+		if i == len(runes) || unicode.IsSpace(runes[i]) != unicode.IsSpace(runes[start]) {
+			tokens = append(tokens, string(runes[start:i]))
+			start = i
+		}
+	}
+
+	return tokens
+}
@@ -0,0 +1,110 @@
+package diff
+
+import "testing"
+
+// rebuild joins the changes back together, skipping either the
+// inserts or deletes, to reproduce one side of the diff.
+func rebuild(changes []Change, skip Operation) string {
+	var s string
+	for _, c := range changes {
+		if c.Op != skip {
+			s += c.Text
+		}
+	}
+
+	return s
+}
+
+func TestLines(t *testing.T) {
+	a := "one\ntwo\nthree\n"
+	b := "one\n2\nthree\nfour\n"
+
+	changes := Lines(a, b)
+
+	if v := rebuild(changes, Insert); v != a {
+		t.Errorf("expected the original to be '%s' but got '%s'", a, v)
+	}
+
+	if v := rebuild(changes, Delete); v != b {
+		t.Errorf("expected the new value to be '%s' but got '%s'", b, v)
+	}
+
+	expected := []Change{
+		{Op: Equal, Text: "one\n"},
+		{Op: Delete, Text: "two\n"},
+		{Op: Insert, Text: "2\n"},
+		{Op: Equal, Text: "three\n"},
+		{Op: Insert, Text: "four\n"},
+	}
+
+	if len(changes) != len(expected) {
+		t.Fatalf("expected %d changes but got %d: %v", len(expected), len(changes), changes)
+	}
+
+	for i, c := range changes {
+		if c != expected[i] {
+			t.Errorf("change %d: expected %v but got %v", i, expected[i], c)
+		}
+	}
+}
+
+func TestWords(t *testing.T) {
+	a := "the quick brown fox"
+	b := "the slow brown fox jumps"
+
+	changes := Words(a, b)
+
+	if v := rebuild(changes, Insert); v != a {
+		t.Errorf("expected the original to be '%s' but got '%s'", a, v)
+	}
+
+	if v := rebuild(changes, Delete); v != b {
+		t.Errorf("expected the new value to be '%s' but got '%s'", b, v)
+	}
+}
+
+func TestWordsWithNoChanges(t *testing.T) {
+	changes := Words("hello world", "hello world")
+	if HasChanges(changes) {
+		t.Errorf("expected no changes, but got: %v", changes)
+	}
+}
+
+func TestWordsWithEmptyValues(t *testing.T) {
+	changes := Words("", "hello")
+	if len(changes) != 1 || changes[0].Op != Insert {
+		t.Errorf("expected a single insert but got: %v", changes)
+	}
+
+	changes = Words("hello", "")
+	if len(changes) != 1 || changes[0].Op != Delete {
+		t.Errorf("expected a single delete but got: %v", changes)
+	}
+
+	if changes = Words("", ""); len(changes) != 0 {
+		t.Errorf("expected no changes but got: %v", changes)
+	}
+}
+
+func TestHTML(t *testing.T) {
+	changes := Words("<p>Hello world</p>", "<p>Hello there</p>")
+	expected := "&lt;p&gt;Hello <del>world&lt;/p&gt;</del><ins>there&lt;/p&gt;</ins>"
+
+	if v := HTML(changes); v != expected {
+		t.Errorf("expected '%s' but got '%s'", expected, v)
+	}
+}
+
+func TestOperation_String(t *testing.T) {
+	tests := map[Operation]string{
+		Equal:  "equal",
+		Insert: "insert",
+		Delete: "delete",
+	}
+
+	for op, expected := range tests {
+		if v := op.String(); v != expected {
+			t.Errorf("expected '%s' but got '%s'", expected, v)
+		}
+	}
+}
@@ -0,0 +1,276 @@
+package feed
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Format is a syndication format a feed can be rendered as.
+type Format string
+
+// Supported feed formats.
+const (
+	FormatRSS Format = "rss"
+	FormatAtom Format = "atom"
+	FormatJSON Format = "json"
+)
+
+// content types for each of the supported formats.
+var contentTypes = map[Format]string{
+	FormatRSS: "application/rss+xml; charset=utf-8",
+	FormatAtom: "application/atom+xml; charset=utf-8",
+	FormatJSON: "application/feed+json; charset=utf-8",
+}
+
+// ParseFormat returns the Format for the given name, which is case-insensitive.
+func ParseFormat(name string) (Format, error) {
+	f := Format(strings.ToLower(name))
+	if _, ok := contentTypes[f]; !ok {
+		return "", fmt.Errorf("feed format '%s' is not supported", name)
+	}
+
+	return f, nil
+}
+
+// ContentType returns the MIME type for the format.
+func (f Format) ContentType() string {
+	return contentTypes[f]
+}
+
+// Feed is a format agnostic representation of a feed.
+type Feed struct {
+	ID string
+	Title string
+	Link string
+	Description string
+	Updated time.Time
+	Items []*Item
+}
+
+// Item is a single entry in a feed.
+type Item struct {
+	ID string
+	Title string
+	Link string
+	Summary string
+	ImageURL string
+	Author string
+	Published time.Time
+	Updated time.Time
+}
+
+// Render renders the feed in the given format.
+func Render(f *Feed, format Format) ([]byte, error) {
+	switch format {
+	case FormatRSS:
+		return RSS(f)
+	case FormatAtom:
+		return Atom(f)
+	case FormatJSON:
+		return JSON(f)
+	default:
+		return nil, fmt.Errorf("feed format '%s' is not supported", format)
+	}
+}
+
+type rss struct {
+	XMLName xml.Name `xml:"rss"`
+	Version string `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title string `xml:"title"`
+	Link string `xml:"link"`
+	Description string `xml:"description"`
+	LastBuildDate string `xml:"lastBuildDate,omitempty"`
+	Items []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title string `xml:"title"`
+	Link string `xml:"link"`
+	Description string `xml:"description"`
+	Author string `xml:"dc:creator,omitempty"`
+	GUID rssGUID `xml:"guid"`
+	PubDate string `xml:"pubDate"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool `xml:"isPermaLink,attr"`
+	Value string `xml:",chardata"`
+}
+
+// RSS renders the feed as an RSS 2.0 document.
+func RSS(f *Feed) ([]byte, error) {
+	doc := rss{
+		Version: "2.0",
+		Channel: rssChannel{
+			Title: f.Title,
+			Link: f.Link,
+			Description: f.Description,
+			Items: make([]rssItem, len(f.Items)),
+		},
+	}
+
+	if !f.Updated.IsZero() {
+		doc.Channel.LastBuildDate = f.Updated.UTC().Format(time.RFC1123Z)
+	}
+
+	for i, item := range f.Items {
+		doc.Channel.Items[i] = rssItem{
+			Title: item.Title,
+			Link: item.Link,
+			Description: item.Summary,
+			Author: item.Author,
+			GUID: rssGUID{Value: item.ID},
+			PubDate: item.Published.UTC().Format(time.RFC1123Z),
+		}
+	}
+
+	data, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	// the dc namespace is required for the item's creator element.
+	data = []byte(strings.Replace(string(data), `<rss version="2.0">`,
+		`<rss version="2.0" xmlns:dc="http://purl.org/dc/elements/1.1/">`, 1))
+
+	return append([]byte(xml.Header), data...), nil
+}
+
+type atomFeed struct {
+	XMLName xml.Name `xml:"http://www.w3.org/2005/Atom feed"`
+	ID string `xml:"id"`
+	Title string `xml:"title"`
+	Subtitle string `xml:"subtitle,omitempty"`
+	Updated string `xml:"updated"`
+	Link atomLink `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel string `xml:"rel,attr,omitempty"`
+}
+
+type atomEntry struct {
+	ID string `xml:"id"`
+	Title string `xml:"title"`
+	Link atomLink `xml:"link"`
+	Summary string `xml:"summary,omitempty"`
+	Author *atomAuthor `xml:"author,omitempty"`
+	Published string `xml:"published"`
+	Updated string `xml:"updated"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+// Atom renders the feed as an Atom 1.0 document.
+func Atom(f *Feed) ([]byte, error) {
+	doc := atomFeed{
+		ID: f.ID,
+		Title: f.Title,
+		Subtitle: f.Description,
+		Updated: f.Updated.UTC().Format(time.RFC3339),
+		Link: atomLink{Href: f.Link, Rel: "alternate"},
+		Entries: make([]atomEntry, len(f.Items)),
+	}
+
+	for i, item := range f.Items {
+		e := atomEntry{
+			ID: item.ID,
+			Title: item.Title,
+			Link: atomLink{Href: item.Link, Rel: "alternate"},
+			Summary: item.Summary,
+			Published: item.Published.UTC().Format(time.RFC3339),
+			Updated: updatedOrPublished(item).UTC().Format(time.RFC3339),
+		}
+
+		if item.Author != "" {
+			e.Author = &atomAuthor{Name: item.Author}
+		}
+
+		doc.Entries[i] = e
+	}
+
+	data, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), data...), nil
+}
+
+type jsonFeed struct {
+	Version string `json:"version"`
+	Title string `json:"title"`
+	HomePageURL string `json:"home_page_url"`
+	Description string `json:"description,omitempty"`
+	Items []jsonFeedItem `json:"items"`
+}
+
+type jsonFeedItem struct {
+	ID string `json:"id"`
+	URL string `json:"url"`
+	Title string `json:"title"`
+	ContentText string `json:"content_text"`
+	Summary string `json:"summary,omitempty"`
+	Image string `json:"image,omitempty"`
+	DatePublished string `json:"date_published"`
+	DateModified string `json:"date_modified,omitempty"`
+	Authors []jsonFeedAuthor `json:"authors,omitempty"`
+}
+
+type jsonFeedAuthor struct {
+	Name string `json:"name"`
+}
+
+// JSON renders the feed as a JSON Feed 1.1 document.
+func JSON(f *Feed) ([]byte, error) {
+	doc := jsonFeed{
+		Version: "https://jsonfeed.org/version/1.1",
+		Title: f.Title,
+		HomePageURL: f.Link,
+		Description: f.Description,
+		Items: make([]jsonFeedItem, len(f.Items)),
+	}
+
+	for i, item := range f.Items {
+		ji := jsonFeedItem{
+			ID: item.ID,
+			URL: item.Link,
+			Title: item.Title,
+			ContentText: item.Summary,
+			Summary: item.Summary,
+			Image: item.ImageURL,
+			DatePublished: item.Published.UTC().Format(time.RFC3339),
+		}
+
+		if !item.Updated.IsZero() {
+			ji.DateModified = item.Updated.UTC().Format(time.RFC3339)
+		}
+
+		if item.Author != "" {
+			ji.Authors = []jsonFeedAuthor{{Name: item.Author}}
+		}
+
+		doc.Items[i] = ji
+	}
+
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+func updatedOrPublished(item *Item) time.Time {
+	if item.Updated.IsZero() {
+		return item.Published
+	}
+
+	return item.Updated
+}
@@ -0,0 +1,130 @@
+package feed
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testFeed() *Feed {
+	published := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	return &Feed{
+		ID: "https://example.com",
+		Title: "My Blog",
+		Link: "https://example.com",
+		Description: "Posts & things",
+		Updated: published,
+		Items: []*Item{
+			{
+				ID: "urn:uuid:1",
+				Title: "Hello <World>",
+				Link: "https://example.com/blog/hello-world",
+				Summary: "A first post.",
+				Author: "John Doe",
+				Published: published,
+			},
+		},
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	f, err := ParseFormat("ATOM")
+	if err != nil || f != FormatAtom {
+		t.Errorf("expected atom but got '%s' (%v)", f, err)
+	}
+
+	if _, err := ParseFormat("csv"); err == nil {
+		t.Errorf("expected an error for an unsupported format")
+	}
+}
+
+func TestRSS(t *testing.T) {
+	data, err := RSS(testFeed())
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	var doc struct {
+		Channel struct {
+			Title string `xml:"title"`
+			Items []struct {
+				Title string `xml:"title"`
+				PubDate string `xml:"pubDate"`
+			} `xml:"item"`
+		} `xml:"channel"`
+	}
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("expected valid XML but got: %v", err)
+	}
+
+	if doc.Channel.Title != "My Blog" || len(doc.Channel.Items) != 1 {
+		t.Fatalf("unexpected channel: %v", doc.Channel)
+	}
+
+	if doc.Channel.Items[0].Title != "Hello <World>" {
+		t.Errorf("expected the title to round trip but got '%s'", doc.Channel.Items[0].Title)
+	}
+
+	if doc.Channel.Items[0].PubDate != "Mon, 01 Jun 2020 12:00:00 +0000" {
+		t.Errorf("unexpected pubDate '%s'", doc.Channel.Items[0].PubDate)
+	}
+}
+
+func TestAtom(t *testing.T) {
+	data, err := Atom(testFeed())
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if !strings.Contains(string(data), `xmlns="http://www.w3.org/2005/Atom"`) {
+		t.Errorf("expected the Atom namespace")
+	}
+
+	var doc struct {
+		Entries []struct {
+			ID string `xml:"id"`
+			Updated string `xml:"updated"`
+		} `xml:"entry"`
+	}
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("expected valid XML but got: %v", err)
+	}
+
+	if len(doc.Entries) != 1 || doc.Entries[0].ID != "urn:uuid:1" {
+		t.Fatalf("unexpected entries: %v", doc.Entries)
+	}
+
+	// updated falls back to the published date.
+	if doc.Entries[0].Updated != "2020-06-01T12:00:00Z" {
+		t.Errorf("unexpected updated date '%s'", doc.Entries[0].Updated)
+	}
+}
+
+func TestJSON(t *testing.T) {
+	data, err := JSON(testFeed())
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("expected valid JSON but got: %v", err)
+	}
+
+	if doc["version"] != "https://jsonfeed.org/version/1.1" {
+		t.Errorf("unexpected version '%v'", doc["version"])
+	}
+
+	items := doc["items"].([]interface{})
+	item := items[0].(map[string]interface{})
+	if item["url"] != "https://example.com/blog/hello-world" {
+		t.Errorf("unexpected url '%v'", item["url"])
+	}
+
+	if _, ok := item["date_modified"]; ok {
+		t.Errorf("expected date_modified to be omitted")
+	}
+}
@@ -6,7 +6,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/reecerussell/distro-blog/auth"
+	"reflect"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/reecerussell/distro-blog/libraries/contextkey"
@@ -23,14 +26,11 @@ func PopulateContext(ctx context.Context, req events.APIGatewayProxyRequest) con
 		h, ok = req.Headers["authorization"]
 	}
 
+	// the token has already been verified by the authorizer.
 	if ok {
-		parts := strings.Split(h, ".")
-		if len(parts) == 3 {
-			bytes, _ := base64.StdEncoding.DecodeString(parts[1])
-			var payload map[string]interface{}
-			json.Unmarshal(bytes, &payload)
-
-			if v, ok := payload[auth.ClaimTypeUserId]; ok {
+		tkn := auth.Token(strings.TrimPrefix(h, "Bearer "))
+		if strings.Count(string(tkn), ".") == 2 {
+			if v := tkn.StringValue(auth.ClaimTypeUserId); v != "" {
 				ctx = context.WithValue(ctx, contextkey.ContextKey("user_id"), v)
 			}
 		}
@@ -59,5 +59,89 @@ func ReadBody(req events.APIGatewayProxyRequest, dst interface{}) error {
 		}
 	}
 
+	return nil
+}
+
+// ReadQuery populates dst, which must be a pointer to a struct, with values from the
+// request's query string. Fields are mapped using their "query" tag, and can be a string,
+// int, bool or time.Time (RFC3339 or YYYY-MM-DD), or a pointer to one of these types.
+func ReadQuery(req events.APIGatewayProxyRequest, dst interface{}) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("destination must be a pointer to a struct")
+	}
+
+	v = v.Elem()
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		name := t.Field(i).Tag.Get("query")
+		if name == "" {
+			continue
+		}
+
+		raw, ok := req.QueryStringParameters[name]
+		if !ok || raw == "" {
+			continue
+		}
+
+		f := v.Field(i)
+		if f.Kind() == reflect.Ptr {
+			p := reflect.New(f.Type().Elem())
+			err := setQueryValue(p.Elem(), raw)
+			if err != nil {
+				return fmt.Errorf("the value of '%s' is not valid", name)
+			}
+
+			f.Set(p)
+			continue
+		}
+
+		err := setQueryValue(f, raw)
+		if err != nil {
+			return fmt.Errorf("the value of '%s' is not valid", name)
+		}
+	}
+
+	return nil
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func setQueryValue(f reflect.Value, raw string) error {
+	if f.Type() == timeType {
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			t, err = time.Parse("2006-01-02", raw)
+			if err != nil {
+				return err
+			}
+		}
+
+		f.Set(reflect.ValueOf(t))
+		return nil
+	}
+
+	switch f.Kind() {
+	case reflect.String:
+		f.SetString(raw)
+	case reflect.Int:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return err
+		}
+
+		f.SetInt(int64(n))
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+
+		f.SetBool(b)
+	default:
+		return fmt.Errorf("unsupported type: %s", f.Type())
+	}
+
 	return nil
 }
\ No newline at end of file
@@ -5,6 +5,7 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-lambda-go/events"
 
@@ -25,6 +26,23 @@ func TestPopulateContext(t *testing.T) {
 	}
 }
 
+func TestPopulateContextWithToken(t *testing.T) {
+	// JWTs use unpadded, URL-safe, base64, which this payload doesn't decode as standard base64.
+	payload, _ := json.Marshal(map[string]interface{}{"uid": "d3b07384-d9a0>>?"})
+	tkn := "e30." + base64.RawURLEncoding.EncodeToString(payload) + ".c2ln"
+
+	req := events.APIGatewayProxyRequest{
+		Headers: map[string]string{
+			"Authorization": "Bearer " + tkn,
+		},
+	}
+	ctx := PopulateContext(context.Background(), req)
+
+	if v := ctx.Value(contextkey.ContextKey("user_id")); v != "d3b07384-d9a0>>?" {
+		t.Errorf("expected 'd3b07384-d9a0>>?' but got '%v'", v)
+	}
+}
+
 func TestReadBody(t *testing.T) {
 	encoding := base64.StdEncoding
 	testPayload := map[string]string{
@@ -109,4 +127,84 @@ func TestReadBody(t *testing.T) {
 			t.Errorf("expected to fail")
 		}
 	})
+}
+
+func TestReadQuery(t *testing.T) {
+	type query struct {
+		Name string `query:"name"`
+		Limit int `query:"limit"`
+		Desc bool `query:"desc"`
+		Active *bool `query:"active"`
+		From *time.Time `query:"from"`
+		To time.Time `query:"to"`
+		Ignored string
+	}
+
+	req := events.APIGatewayProxyRequest{
+		QueryStringParameters: map[string]string{
+			"name": "hello",
+			"limit": "10",
+			"desc": "true",
+			"active": "false",
+			"from": "2020-06-01",
+			"to": "2020-06-30T12:00:00Z",
+			"Ignored": "value",
+		},
+	}
+
+	var q query
+	err := ReadQuery(req, &q)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if q.Name != "hello" || q.Limit != 10 || !q.Desc {
+		t.Errorf("expected the values to be read but got: %+v", q)
+	}
+
+	if q.Active == nil || *q.Active {
+		t.Errorf("expected active to be false but got: %v", q.Active)
+	}
+
+	if q.From == nil || !q.From.Equal(time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected from to be 2020-06-01 but got: %v", q.From)
+	}
+
+	if !q.To.Equal(time.Date(2020, 6, 30, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected to to be 2020-06-30T12:00:00Z but got: %v", q.To)
+	}
+
+	if q.Ignored != "" {
+		t.Errorf("expected untagged fields to be ignored but got '%s'", q.Ignored)
+	}
+}
+
+func TestReadQueryWithInvalidValues(t *testing.T) {
+	type query struct {
+		Limit int `query:"limit"`
+		Active *bool `query:"active"`
+		From *time.Time `query:"from"`
+	}
+
+	tests := map[string]string{
+		"limit": "ten",
+		"active": "maybe",
+		"from": "yesterday",
+	}
+
+	for k, v := range tests {
+		req := events.APIGatewayProxyRequest{
+			QueryStringParameters: map[string]string{k: v},
+		}
+
+		var q query
+		if err := ReadQuery(req, &q); err == nil {
+			t.Errorf("expected an error for '%s' but got nil", k)
+		}
+	}
+
+	var s string
+	if err := ReadQuery(events.APIGatewayProxyRequest{}, &s); err == nil {
+		t.Errorf("expected an error for a non-struct destination")
+	}
 }
\ No newline at end of file
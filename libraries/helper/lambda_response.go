@@ -2,8 +2,13 @@ package helper
 
 import (
 	"context"
+	"crypto/sha1"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/reecerussell/distro-blog/libraries/contextkey"
 
@@ -44,6 +49,79 @@ func Response(ctx context.Context, res result.Result, req events.APIGatewayProxy
 	return resp
 }
 
+// ContentResponse creates a new APIGatewayProxyResponse with a raw, non-JSON, body, such as
+// a feed or sitemap. An ETag is generated from the body and, along with lastModified, is used
+// to answer conditional requests with a 304 Not Modified. maxAge sets the Cache-Control header.
+func ContentResponse(ctx context.Context, req events.APIGatewayProxyRequest, contentType string, body []byte, lastModified time.Time, maxAge int) events.APIGatewayProxyResponse {
+	resp := events.APIGatewayProxyResponse{
+		StatusCode: http.StatusOK,
+		Headers: map[string]string{
+			"Content-Type": contentType,
+			"ETag": ETag(body),
+			"Cache-Control": fmt.Sprintf("public, max-age=%d", maxAge),
+		},
+	}
+
+	if !lastModified.IsZero() {
+		resp.Headers["Last-Modified"] = lastModified.UTC().Format(http.TimeFormat)
+	}
+
+	if NotModified(req, resp.Headers["ETag"], lastModified) {
+		resp.StatusCode = http.StatusNotModified
+	} else {
+		resp.Body = string(body)
+	}
+
+	mapCORS(ctx, req, &resp)
+
+	return resp
+}
+
+// ETag returns a strong entity tag for the given content.
+func ETag(body []byte) string {
+	sum := sha1.Sum(body)
+	return "\"" + hex.EncodeToString(sum[:]) + "\""
+}
+
+// NotModified determines whether the client's cached copy, described by the request's
+// If-None-Match and If-Modified-Since headers, is still fresh. If-None-Match takes
+// precedence over If-Modified-Since when both are present.
+func NotModified(req events.APIGatewayProxyRequest, etag string, lastModified time.Time) bool {
+	if v, ok := Header(req, "If-None-Match"); ok {
+		for _, t := range strings.Split(v, ",") {
+			t = strings.TrimPrefix(strings.TrimSpace(t), "W/")
+			if t == "*" || t == etag {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	if v, ok := Header(req, "If-Modified-Since"); ok && !lastModified.IsZero() {
+		since, err := http.ParseTime(v)
+		if err != nil {
+			return false
+		}
+
+		// http dates only have a precision of seconds.
+		return !lastModified.Truncate(time.Second).After(since)
+	}
+
+	return false
+}
+
+// Header returns the value of the request header with the given name, ignoring case.
+func Header(req events.APIGatewayProxyRequest, name string) (string, bool) {
+	for k, v := range req.Headers {
+		if strings.EqualFold(k, name) {
+			return v, true
+		}
+	}
+
+	return "", false
+}
+
 func mapCORS(ctx context.Context, req events.APIGatewayProxyRequest, resp *events.APIGatewayProxyResponse) {
 	if resp.Headers == nil {
 		resp.Headers = make(map[string]string)
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-lambda-go/events"
 
@@ -197,3 +198,86 @@ func TestMapCORSWithoutKeys(t *testing.T) {
 		t.Errorf("expected method '%s' but got '%s'", req.HTTPMethod, v)
 	}
 }
+
+func TestContentResponse(t *testing.T) {
+	ctx := context.Background()
+	req := events.APIGatewayProxyRequest{
+		HTTPMethod: http.MethodGet,
+	}
+	body := []byte("<rss></rss>")
+	modified := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	resp := ContentResponse(ctx, req, "application/rss+xml", body, modified, 300)
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected a status code of 200, but got: %d", resp.StatusCode)
+	}
+
+	if resp.Body != string(body) {
+		t.Errorf("expected the response body to be '%s' but got '%s'", body, resp.Body)
+	}
+
+	if v := resp.Headers["Content-Type"]; v != "application/rss+xml" {
+		t.Errorf("expected 'application/rss+xml' but got '%s'", v)
+	}
+
+	if v := resp.Headers["Last-Modified"]; v != "Mon, 01 Jun 2020 12:00:00 GMT" {
+		t.Errorf("unexpected Last-Modified header '%s'", v)
+	}
+
+	if v := resp.Headers["ETag"]; v != ETag(body) {
+		t.Errorf("expected an ETag of '%s' but got '%s'", ETag(body), v)
+	}
+}
+
+func TestContentResponseNotModified(t *testing.T) {
+	ctx := context.Background()
+	body := []byte("<rss></rss>")
+	modified := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := map[string]map[string]string{
+		"etag": {"If-None-Match": ETag(body)},
+		"weak etag": {"if-none-match": "\"other\", W/" + ETag(body)},
+		"modified since": {"If-Modified-Since": "Mon, 01 Jun 2020 12:00:00 GMT"},
+	}
+
+	for name, headers := range tests {
+		req := events.APIGatewayProxyRequest{
+			HTTPMethod: http.MethodGet,
+			Headers: headers,
+		}
+
+		resp := ContentResponse(ctx, req, "application/rss+xml", body, modified, 300)
+		if resp.StatusCode != http.StatusNotModified {
+			t.Errorf("%s: expected a status code of 304, but got: %d", name, resp.StatusCode)
+		}
+
+		if resp.Body != "" {
+			t.Errorf("%s: expected an empty body but got '%s'", name, resp.Body)
+		}
+	}
+}
+
+func TestContentResponseModified(t *testing.T) {
+	ctx := context.Background()
+	body := []byte("<rss></rss>")
+	modified := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := map[string]map[string]string{
+		"etag": {"If-None-Match": "\"other\""},
+		"modified since": {"If-Modified-Since": "Sun, 31 May 2020 12:00:00 GMT"},
+		// If-None-Match takes precedence.
+		"both": {"If-None-Match": "\"other\"", "If-Modified-Since": "Mon, 01 Jun 2020 12:00:00 GMT"},
+	}
+
+	for name, headers := range tests {
+		req := events.APIGatewayProxyRequest{
+			HTTPMethod: http.MethodGet,
+			Headers: headers,
+		}
+
+		resp := ContentResponse(ctx, req, "application/rss+xml", body, modified, 300)
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("%s: expected a status code of 200, but got: %d", name, resp.StatusCode)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package locale
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// MaxPreferences is the maximum number of locales read from an Accept-Language header.
+const MaxPreferences = 10
+
+// a language code, optionally followed by a script and/or region, such as "fr", "pt-br" or "zh-hant-tw".
+var localeRegex = regexp.MustCompile("^[a-z]{2,3}(-[a-z]{4})?(-([a-z]{2}|[0-9]{3}))?$")
+
+// Normalize returns the locale in lowercase, using hyphens as separators. An error
+// is returned if the locale isn't a language code, optionally followed by a region.
+func Normalize(locale string) (string, error) {
+	l := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+	if !localeRegex.MatchString(l) {
+		return "", fmt.Errorf("'%s' is not a valid locale", locale)
+	}
+
+	return l, nil
+}
+
+// Language returns the language of the locale, without its region. For example, "fr" for "fr-ca".
+func Language(locale string) string {
+	if i := strings.Index(locale, "-"); i > -1 {
+		return locale[:i]
+	}
+
+	return locale
+}
+
+// Fallbacks returns the locales to try, in order, when looking for content in the given
+// locales. Each locale is followed by its language, if it isn't already in the list, so
+// "fr-ca" falls back to "fr". Invalid locales are ignored.
+func Fallbacks(locales ...string) []string {
+	var list []string
+	seen := make(map[string]bool)
+	add := func(l string) {
+		if !seen[l] {
+			seen[l] = true
+			list = append(list, l)
+		}
+	}
+
+	for i, l := range locales {
+		l, err := Normalize(l)
+		if err != nil {
+			continue
+		}
+
+		add(l)
+
+		// only fall back to the language once there are no more specific
+		// locales of the same language, so "fr-ca,fr-fr" doesn't become "fr-ca,fr,fr-fr".
+		lang := Language(l)
+		if lang != l && !hasLanguage(locales[i+1:], lang) {
+			add(lang)
+		}
+	}
+
+	return list
+}
+
+func hasLanguage(locales []string, lang string) bool {
+	for _, l := range locales {
+		if l, err := Normalize(l); err == nil && Language(l) == lang {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ParseAcceptLanguage returns the locales in an Accept-Language header, ordered by their
+// quality values, followed by their fallbacks. Wildcards and locales with a quality of 0
+// are ignored, as is anything after the first MaxPreferences locales.
+func ParseAcceptLanguage(header string) []string {
+	type preference struct {
+		locale string
+		q float64
+	}
+
+	var prefs []preference
+
+	for _, part := range strings.Split(header, ",") {
+		if len(prefs) >= MaxPreferences {
+			break
+		}
+
+		fields := strings.Split(part, ";")
+		l := strings.TrimSpace(fields[0])
+		if l == "" || l == "*" {
+			continue
+		}
+
+		q := 1.0
+		for _, f := range fields[1:] {
+			f = strings.TrimSpace(f)
+			if strings.HasPrefix(f, "q=") {
+				v, err := strconv.ParseFloat(f[2:], 64)
+				if err != nil {
+					v = 0
+				}
+
+				q = v
+			}
+		}
+
+		if q > 0 {
+			prefs = append(prefs, preference{l, q})
+		}
+	}
+
+	// a stable sort keeps locales with equal quality values in the order they were given.
+	sort.SliceStable(prefs, func(i, j int) bool {
+		return prefs[i].q > prefs[j].q
+	})
+
+	locales := make([]string, len(prefs))
+	for i, p := range prefs {
+		locales[i] = p.locale
+	}
+
+	return Fallbacks(locales...)
+}
@@ -0,0 +1,57 @@
+package locale
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := map[string]string{
+		"en": "en",
+		" FR_ca ": "fr-ca",
+		"zh-Hant-TW": "zh-hant-tw",
+		"es-419": "es-419",
+	}
+
+	for in, exp := range tests {
+		l, err := Normalize(in)
+		if err != nil {
+			t.Errorf("%s: expected no error but got: %v", in, err)
+			continue
+		}
+
+		if l != exp {
+			t.Errorf("%s: expected '%s' but got '%s'", in, exp, l)
+		}
+	}
+
+	for _, in := range []string{"", "english", "en-", "e1", "en-us-x"} {
+		if _, err := Normalize(in); err == nil {
+			t.Errorf("%s: expected an error but got nil", in)
+		}
+	}
+}
+
+func TestFallbacks(t *testing.T) {
+	l := strings.Join(Fallbacks("fr-CA", "fr-fr", "de", "invalid"), ",")
+	if l != "fr-ca,fr-fr,fr,de" {
+		t.Errorf("expected 'fr-ca,fr-fr,fr,de' but got '%s'", l)
+	}
+
+	// a language which is given explicitly keeps its place.
+	l = strings.Join(Fallbacks("fr-ca", "de", "fr"), ",")
+	if l != "fr-ca,de,fr" {
+		t.Errorf("expected 'fr-ca,de,fr' but got '%s'", l)
+	}
+}
+
+func TestParseAcceptLanguage(t *testing.T) {
+	l := strings.Join(ParseAcceptLanguage("de;q=0.7, fr-CH, *;q=0.5, en;q=0.8, es;q=0"), ",")
+	if l != "fr-ch,fr,en,de" {
+		t.Errorf("expected 'fr-ch,fr,en,de' but got '%s'", l)
+	}
+
+	if l := ParseAcceptLanguage(""); len(l) != 0 {
+		t.Errorf("expected no locales but got %v", l)
+	}
+}
@@ -0,0 +1,97 @@
+package mail
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// FileSender is an implementation of Sender, which writes each email to a
+// .eml file in a directory, rather than sending it. This can be used when
+// developing locally, without an SMTP server.
+type FileSender struct {
+	dir string
+	from string
+}
+
+// NewFileSender returns a new FileSender, which writes emails to the directory,
+// creating it if it doesn't exist.
+func NewFileSender(dir, from string) (*FileSender, error) {
+	err := os.MkdirAll(dir, 0700)
+	if err != nil {
+		return nil, err
+	}
+
+	if from == "" {
+		from = "noreply@localhost"
+	}
+
+	return &FileSender{
+		dir: dir,
+		from: from,
+	}, nil
+}
+
+// Send writes the message to a new file, named using the current time.
+func (s *FileSender) Send(ctx context.Context, msg *Message) error {
+	now := time.Now()
+	data, err := msg.encode(s.from, now)
+	if err != nil {
+		return err
+	}
+
+	b := make([]byte, 4)
+	_, err = rand.Read(b)
+	if err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%d-%s.eml", now.UnixNano(), hex.EncodeToString(b))
+
+	return ioutil.WriteFile(filepath.Join(s.dir, name), data, 0600)
+}
+
+// MemorySender is an implementation of Sender, which keeps the emails it's
+// sent in memory, so they can be read by tests.
+type MemorySender struct {
+	mu sync.Mutex
+	messages []*Message
+}
+
+// NewMemorySender returns a new, empty, MemorySender.
+func NewMemorySender() *MemorySender {
+	return &MemorySender{}
+}
+
+// Send stores a copy of the message.
+func (s *MemorySender) Send(ctx context.Context, msg *Message) error {
+	_, err := msg.encode("", time.Now())
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m := *msg
+	s.messages = append(s.messages, &m)
+
+	return nil
+}
+
+// Messages returns the messages which have been sent, in the order they were sent.
+func (s *MemorySender) Messages() []*Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	messages := make([]*Message, len(s.messages))
+	copy(messages, s.messages)
+
+	return messages
+}
@@ -0,0 +1,93 @@
+// Package mail is used to send plain text emails, such as password reset links,
+// using a Sender, which can either send them over SMTP, or write them to files.
+package mail
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"os"
+	"strings"
+	"time"
+)
+
+// Environment variables used to configure a Sender.
+const (
+	EnvSMTPHost = "SMTP_HOST"
+	EnvSMTPPort = "SMTP_PORT"
+	EnvSMTPUsername = "SMTP_USERNAME"
+	EnvSMTPPassword = "SMTP_PASSWORD"
+	EnvFrom = "MAIL_FROM"
+	EnvDir = "MAIL_DIR"
+)
+
+// DefaultSMTPPort is the port used if one isn't configured, which is the submission port.
+const DefaultSMTPPort = "587"
+
+// ErrNoSender is returned by NewSenderFromEnv if neither an SMTP
+// server or directory has been configured.
+var ErrNoSender = errors.New("no mail sender has been configured")
+
+// Message is a plain text email.
+type Message struct {
+	To string
+	Subject string
+	Body string
+}
+
+// Sender is used to send emails.
+type Sender interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+// NewSenderFromEnv returns an SMTPSender, if the SMTP_HOST environment variable is set,
+// otherwise a FileSender, if MAIL_DIR is set. Emails are sent from the MAIL_FROM address.
+func NewSenderFromEnv() (Sender, error) {
+	from := os.Getenv(EnvFrom)
+
+	if host := os.Getenv(EnvSMTPHost); host != "" {
+		port := os.Getenv(EnvSMTPPort)
+		if port == "" {
+			port = DefaultSMTPPort
+		}
+
+		return NewSMTPSender(host, port, os.Getenv(EnvSMTPUsername), os.Getenv(EnvSMTPPassword), from)
+	}
+
+	if dir := os.Getenv(EnvDir); dir != "" {
+		return NewFileSender(dir, from)
+	}
+
+	return nil, ErrNoSender
+}
+
+// encode returns the message in RFC 5322 format, sent from the given address.
+// An error is returned if any of the headers contain a line break, to prevent
+// headers being injected.
+func (m *Message) encode(from string, date time.Time) ([]byte, error) {
+	if m.To == "" {
+		return nil, errors.New("message has no recipient")
+	}
+
+	for _, v := range []string{from, m.To, m.Subject} {
+		if strings.ContainsAny(v, "\r\n") {
+			return nil, errors.New("message headers cannot contain line breaks")
+		}
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", m.To)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", m.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: 8bit\r\n\r\n")
+
+	body := strings.ReplaceAll(m.Body, "\r\n", "\n")
+	buf.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,91 @@
+package mail
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMessage_Encode(t *testing.T) {
+	msg := &Message{
+		To: "user@example.com",
+		Subject: "Reset your password",
+		Body: "Hello,\nUse the link below.",
+	}
+
+	data, err := msg.encode("noreply@example.com", time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := "From: noreply@example.com\r\n" +
+		"To: user@example.com\r\n" +
+		"Subject: Reset your password\r\n" +
+		"Date: Mon, 01 Jun 2020 12:00:00 +0000\r\n" +
+		"MIME-Version: 1.0\r\n" +
+		"Content-Type: text/plain; charset=utf-8\r\n" +
+		"Content-Transfer-Encoding: 8bit\r\n\r\n" +
+		"Hello,\r\nUse the link below."
+	if string(data) != expected {
+		t.Errorf("expected:\n%s\nbut got:\n%s", expected, data)
+	}
+
+	t.Run("Header Injection", func(t *testing.T) {
+		msg := &Message{To: "user@example.com\r\nBcc: other@example.com", Subject: "Hello"}
+		if _, err := msg.encode("noreply@example.com", time.Now()); err == nil {
+			t.Errorf("expected an error")
+		}
+	})
+
+	t.Run("No Recipient", func(t *testing.T) {
+		msg := &Message{Subject: "Hello"}
+		if _, err := msg.encode("noreply@example.com", time.Now()); err == nil {
+			t.Errorf("expected an error")
+		}
+	})
+}
+
+func TestFileSender(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mail")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	s, _ := NewFileSender(dir, "noreply@example.com")
+	err = s.Send(context.Background(), &Message{To: "user@example.com", Subject: "Hello", Body: "World"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.eml"))
+	if len(files) != 1 {
+		t.Fatalf("expected 1 file but got %d", len(files))
+	}
+
+	data, _ := ioutil.ReadFile(files[0])
+	if !strings.HasPrefix(string(data), "From: noreply@example.com\r\nTo: user@example.com\r\n") {
+		t.Errorf("unexpected file contents: %s", data)
+	}
+}
+
+func TestMemorySender(t *testing.T) {
+	s := NewMemorySender()
+	msg := &Message{To: "user@example.com", Subject: "Hello", Body: "World"}
+	_ = s.Send(context.Background(), msg)
+
+	msg.Body = "Changed"
+
+	messages := s.Messages()
+	if len(messages) != 1 || messages[0].Body != "World" {
+		t.Errorf("expected a copy of the message to be stored")
+	}
+
+	if err := s.Send(context.Background(), &Message{}); err == nil {
+		t.Errorf("expected a message without a recipient to fail")
+	}
+}
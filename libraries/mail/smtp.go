@@ -0,0 +1,46 @@
+package mail
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/smtp"
+	"time"
+)
+
+// SMTPSender is an implementation of Sender, which sends emails using an SMTP
+// server. The connection is upgraded to TLS, if the server supports it.
+type SMTPSender struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+// NewSMTPSender returns a new SMTPSender for the server at the given host and port.
+// If a username is given, PLAIN authentication is used, which requires TLS.
+func NewSMTPSender(host, port, username, password, from string) (*SMTPSender, error) {
+	if from == "" {
+		return nil, errors.New("a from address is required to send emails")
+	}
+
+	s := &SMTPSender{
+		addr: net.JoinHostPort(host, port),
+		from: from,
+	}
+
+	if username != "" {
+		s.auth = smtp.PlainAuth("", username, password, host)
+	}
+
+	return s, nil
+}
+
+// Send sends the message using the SMTP server.
+func (s *SMTPSender) Send(ctx context.Context, msg *Message) error {
+	data, err := msg.encode(s.from, time.Now())
+	if err != nil {
+		return err
+	}
+
+	return smtp.SendMail(s.addr, s.auth, s.from, []string{msg.To}, data)
+}
@@ -0,0 +1,74 @@
+package paging
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Limits applied to the number of items returned in a single page.
+const (
+	DefaultLimit = 20
+	MaxLimit = 100
+)
+
+const cursorPrefix = "offset:"
+
+// Normalize returns a limit and offset which are within the allowed
+// bounds. A limit less than 1 is replaced with DefaultLimit.
+func Normalize(limit, offset int) (int, int) {
+	switch true {
+	case limit < 1:
+		limit = DefaultLimit
+	case limit > MaxLimit:
+		limit = MaxLimit
+	}
+
+	if offset < 0 {
+		offset = 0
+	}
+
+	return limit, offset
+}
+
+// EncodeCursor returns an opaque cursor which points to the given offset.
+func EncodeCursor(offset int) string {
+	v := cursorPrefix + strconv.Itoa(offset)
+	return base64.RawURLEncoding.EncodeToString([]byte(v))
+}
+
+// DecodeCursor returns the offset the cursor points to. An error is
+// returned if the cursor was not created by EncodeCursor.
+func DecodeCursor(cursor string) (int, error) {
+	errInvalid := fmt.Errorf("cursor '%s' is not valid", cursor)
+
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, errInvalid
+	}
+
+	v := string(data)
+	if !strings.HasPrefix(v, cursorPrefix) {
+		return 0, errInvalid
+	}
+
+	offset, err := strconv.Atoi(v[len(cursorPrefix):])
+	if err != nil || offset < 0 {
+		return 0, errInvalid
+	}
+
+	return offset, nil
+}
+
+// NextCursor returns a cursor to the page after the one at offset, or
+// nil if there are no more items after the current page.
+func NextCursor(limit, offset int, total int64) *string {
+	next := offset + limit
+	if int64(next) >= total {
+		return nil
+	}
+
+	c := EncodeCursor(next)
+	return &c
+}
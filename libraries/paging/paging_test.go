@@ -0,0 +1,59 @@
+package paging
+
+import "testing"
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		limit, offset int
+		expLimit, expOffset int
+	}{
+		{0, 0, DefaultLimit, 0},
+		{-5, -1, DefaultLimit, 0},
+		{10, 30, 10, 30},
+		{MaxLimit + 1, 0, MaxLimit, 0},
+	}
+
+	for _, tt := range tests {
+		l, o := Normalize(tt.limit, tt.offset)
+		if l != tt.expLimit || o != tt.expOffset {
+			t.Errorf("Normalize(%d, %d): expected (%d, %d) but got (%d, %d)", tt.limit, tt.offset, tt.expLimit, tt.expOffset, l, o)
+		}
+	}
+}
+
+func TestCursor(t *testing.T) {
+	c := EncodeCursor(40)
+
+	offset, err := DecodeCursor(c)
+	if err != nil {
+		t.Errorf("expected no error but got: %v", err)
+	}
+
+	if offset != 40 {
+		t.Errorf("expected 40 but got %d", offset)
+	}
+}
+
+func TestDecodeCursorWithInvalidValues(t *testing.T) {
+	for _, c := range []string{"", "not base64!", "aGVsbG8", EncodeCursor(-1)} {
+		_, err := DecodeCursor(c)
+		if err == nil {
+			t.Errorf("expected an error for '%s' but got nil", c)
+		}
+	}
+}
+
+func TestNextCursor(t *testing.T) {
+	if c := NextCursor(20, 0, 20); c != nil {
+		t.Errorf("expected no cursor but got '%s'", *c)
+	}
+
+	c := NextCursor(20, 20, 41)
+	if c == nil {
+		t.Fatalf("expected a cursor but got nil")
+	}
+
+	if offset, _ := DecodeCursor(*c); offset != 40 {
+		t.Errorf("expected the cursor to point to 40 but got %d", offset)
+	}
+}
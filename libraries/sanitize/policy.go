@@ -0,0 +1,142 @@
+package sanitize
+
+import "strings"
+
+// Policy is an allowlist of the elements, attributes and URL schemes
+// which are kept when sanitizing HTML. Anything not allowed is removed.
+type Policy struct {
+	elements map[string]map[string]bool
+	globalAttrs map[string]bool
+	urlSchemes map[string]bool
+	embedHosts map[string]bool
+}
+
+// Elements whose content is removed along with them, when they aren't allowed,
+// as their content is not meant to be shown as text, or is unsafe.
+var removeWithContent = map[string]bool{
+	"script": true,
+	"style": true,
+	"iframe": true,
+	"object": true,
+	"embed": true,
+	"svg": true,
+	"math": true,
+	"template": true,
+	"noscript": true,
+	"textarea": true,
+	"select": true,
+	"title": true,
+	"head": true,
+}
+
+// Attributes which contain a URL, which must use an allowed scheme.
+var urlAttrs = map[string]bool{
+	"href": true,
+	"src": true,
+	"cite": true,
+	"poster": true,
+}
+
+// Attributes allowed on iframes, if their source is an allowed embed.
+var embedAttrs = []string{"src", "width", "height", "title", "allow", "allowfullscreen", "frameborder"}
+
+// NewPolicy returns an empty policy, which doesn't allow anything.
+func NewPolicy() *Policy {
+	return &Policy{
+		elements: make(map[string]map[string]bool),
+		globalAttrs: make(map[string]bool),
+		urlSchemes: make(map[string]bool),
+		embedHosts: make(map[string]bool),
+	}
+}
+
+// DefaultPolicy returns a policy which allows the elements commonly used
+// to format written content, such as headings, lists, links, images and
+// tables. Links may use http, https, mailto and tel URLs. No embeds are allowed.
+func DefaultPolicy() *Policy {
+	p := NewPolicy()
+	p.AllowElements("p", "br", "hr", "h1", "h2", "h3", "h4", "h5", "h6",
+		"strong", "b", "em", "i", "u", "s", "del", "ins", "sub", "sup", "mark", "small",
+		"abbr", "code", "pre", "kbd", "blockquote", "ul", "ol", "li", "dl", "dt", "dd",
+		"span", "div", "figure", "figcaption", "table", "caption", "thead", "tbody",
+		"tfoot", "tr", "th", "td", "a", "img")
+	p.AllowAttributes("a", "href", "title", "target", "rel")
+	p.AllowAttributes("img", "src", "alt", "title", "width", "height")
+	p.AllowAttributes("blockquote", "cite")
+	p.AllowAttributes("ol", "start")
+	p.AllowAttributes("th", "colspan", "rowspan", "align")
+	p.AllowAttributes("td", "colspan", "rowspan", "align")
+	p.AllowGlobalAttributes("id", "class")
+	p.AllowURLSchemes("http", "https", "mailto", "tel")
+
+	return p
+}
+
+// AllowElements allows the given elements, without any attributes.
+func (p *Policy) AllowElements(names ...string) *Policy {
+	for _, n := range names {
+		n = strings.ToLower(n)
+		if _, ok := p.elements[n]; !ok {
+			p.elements[n] = make(map[string]bool)
+		}
+	}
+
+	return p
+}
+
+// AllowAttributes allows the given attributes on an element,
+// which also allows the element itself.
+func (p *Policy) AllowAttributes(element string, attrs ...string) *Policy {
+	p.AllowElements(element)
+
+	element = strings.ToLower(element)
+	for _, a := range attrs {
+		p.elements[element][strings.ToLower(a)] = true
+	}
+
+	return p
+}
+
+// AllowGlobalAttributes allows the given attributes on all allowed elements.
+func (p *Policy) AllowGlobalAttributes(attrs ...string) *Policy {
+	for _, a := range attrs {
+		p.globalAttrs[strings.ToLower(a)] = true
+	}
+
+	return p
+}
+
+// AllowURLSchemes allows URLs with the given schemes. Relative URLs are always allowed.
+func (p *Policy) AllowURLSchemes(schemes ...string) *Policy {
+	for _, s := range schemes {
+		p.urlSchemes[strings.ToLower(s)] = true
+	}
+
+	return p
+}
+
+// AllowEmbeds allows iframes whose source is a https URL on one of the
+// given hosts, for example, "www.youtube.com". Iframes from any other
+// source are removed, along with their content.
+func (p *Policy) AllowEmbeds(hosts ...string) *Policy {
+	for _, h := range hosts {
+		p.embedHosts[strings.ToLower(h)] = true
+	}
+
+	return p
+}
+
+// allowsAttr determines whether the attribute is allowed on the element.
+func (p *Policy) allowsAttr(element, attr string) bool {
+	if element == "iframe" {
+		for _, a := range embedAttrs {
+			if a == attr {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	return p.elements[element][attr] || p.globalAttrs[attr]
+}
@@ -0,0 +1,228 @@
+package sanitize
+
+import (
+	"html"
+	"net/url"
+	"strings"
+
+	parser "golang.org/x/net/html"
+)
+
+// Removal describes an element, or an attribute of an element, which
+// was removed from the HTML as it is not allowed by the policy.
+type Removal struct {
+	Element string `json:"element"`
+	Attribute string `json:"attribute,omitempty"`
+}
+
+// String returns the removal as a tag, for example "<script>" or "<img onerror>".
+func (r Removal) String() string {
+	if r.Attribute == "" {
+		return "<" + r.Element + ">"
+	}
+
+	return "<" + r.Element + " " + r.Attribute + ">"
+}
+
+// Result contains the sanitized HTML and a list of what was removed. Each
+// element and attribute is only reported once, no matter how often it appeared.
+type Result struct {
+	HTML string
+	Removed []Removal
+}
+
+func (r *Result) remove(element, attr string) {
+	rm := Removal{Element: element, Attribute: attr}
+	for _, e := range r.Removed {
+		if e == rm {
+			return
+		}
+	}
+
+	r.Removed = append(r.Removed, rm)
+}
+
+// Elements which never have any content or an end tag.
+var voidElements = map[string]bool{
+	"area": true,
+	"base": true,
+	"br": true,
+	"col": true,
+	"embed": true,
+	"hr": true,
+	"img": true,
+	"input": true,
+	"link": true,
+	"meta": true,
+	"param": true,
+	"source": true,
+	"track": true,
+	"wbr": true,
+}
+
+// Sanitize parses the HTML, s, and returns it with only the elements, attributes
+// and URLs allowed by the policy. The text of removed elements is kept, apart from
+// elements such as scripts and styles, which are removed with their content.
+// Comments are always removed, and any unclosed elements are closed.
+func (p *Policy) Sanitize(s string) *Result {
+	var (
+		b strings.Builder
+		res = &Result{}
+		open []string
+		skipping string
+		depth int
+	)
+
+	z := parser.NewTokenizer(strings.NewReader(s))
+
+	for {
+		tt := z.Next()
+		if tt == parser.ErrorToken {
+			break
+		}
+
+		switch tt {
+		case parser.TextToken:
+			if skipping == "" {
+				b.WriteString(html.EscapeString(string(z.Text())))
+			}
+		case parser.StartTagToken, parser.SelfClosingTagToken:
+			t := z.Token()
+			if skipping != "" {
+				if t.Data == skipping && tt == parser.StartTagToken {
+					depth++
+				}
+				continue
+			}
+
+			if !p.allowsElement(t) {
+				res.remove(t.Data, "")
+
+				if removeWithContent[t.Data] && tt == parser.StartTagToken && !voidElements[t.Data] {
+					skipping = t.Data
+					depth = 1
+				}
+				continue
+			}
+
+			p.writeStartTag(&b, t, res)
+
+			switch {
+			case voidElements[t.Data]:
+			case tt == parser.SelfClosingTagToken:
+				b.WriteString("</" + t.Data + ">")
+			default:
+				open = append(open, t.Data)
+			}
+		case parser.EndTagToken:
+			t := z.Token()
+			if skipping != "" {
+				if t.Data == skipping {
+					depth--
+					if depth == 0 {
+						skipping = ""
+					}
+				}
+				continue
+			}
+
+			// only close elements which are open, closing
+			// any elements which were opened inside of it.
+			for i := len(open) - 1; i >= 0; i-- {
+				if open[i] != t.Data {
+					continue
+				}
+
+				for j := len(open) - 1; j >= i; j-- {
+					b.WriteString("</" + open[j] + ">")
+				}
+
+				open = open[:i]
+				break
+			}
+		}
+	}
+
+	for i := len(open) - 1; i >= 0; i-- {
+		b.WriteString("</" + open[i] + ">")
+	}
+
+	res.HTML = b.String()
+
+	return res
+}
+
+// writeStartTag writes the start tag of an allowed element, with only
+// its allowed attributes. Any removed attributes are added to res.
+func (p *Policy) writeStartTag(b *strings.Builder, t parser.Token, res *Result) {
+	b.WriteString("<" + t.Data)
+
+	seen := make(map[string]bool)
+	for _, a := range t.Attr {
+		if a.Namespace != "" || !p.allowsAttr(t.Data, a.Key) {
+			res.remove(t.Data, a.Key)
+			continue
+		}
+
+		if urlAttrs[a.Key] && !p.allowsURL(a.Val) {
+			res.remove(t.Data, a.Key)
+			continue
+		}
+
+		if seen[a.Key] {
+			continue
+		}
+
+		seen[a.Key] = true
+		b.WriteString(" " + a.Key + "=\"" + html.EscapeString(a.Val) + "\"")
+	}
+
+	if voidElements[t.Data] {
+		b.WriteString(" />")
+	} else {
+		b.WriteString(">")
+	}
+}
+
+// allowsElement determines whether the element is allowed. Iframes
+// are only allowed if their source is one of the allowed embeds.
+func (p *Policy) allowsElement(t parser.Token) bool {
+	if t.Data == "iframe" {
+		return p.allowsEmbed(t)
+	}
+
+	_, ok := p.elements[t.Data]
+	return ok
+}
+
+func (p *Policy) allowsEmbed(t parser.Token) bool {
+	for _, a := range t.Attr {
+		if a.Key != "src" {
+			continue
+		}
+
+		u, err := url.Parse(strings.TrimSpace(a.Val))
+		if err != nil || u.Scheme != "https" {
+			return false
+		}
+
+		return p.embedHosts[strings.ToLower(u.Hostname())]
+	}
+
+	return false
+}
+
+// allowsURL determines whether the URL is relative or uses an allowed scheme. URLs
+// which can't be parsed, such as those containing control characters, are not allowed.
+func (p *Policy) allowsURL(v string) bool {
+	u, err := url.Parse(strings.TrimSpace(v))
+	if err != nil {
+		return false
+	}
+
+	if u.Scheme == "" {
+		return true
+	}
+
+	return p.urlSchemes[strings.ToLower(u.Scheme)]
+}
@@ -0,0 +1,77 @@
+package sanitize
+
+import "testing"
+
+func TestPolicy_Sanitize(t *testing.T) {
+	tests := map[string]string{
+		"<p>Hello <strong>world</strong></p>": "<p>Hello <strong>world</strong></p>",
+		"<p>Hi<script>alert(1)</script></p>": "<p>Hi</p>",
+		"<img src=\"a.png\" onerror=\"alert(1)\">": "<img src=\"a.png\" />",
+		"<a href=\"javascript:alert(1)\">x</a>": "<a>x</a>",
+		"<a href=\"JaVaScRiPt&colon;alert(1)\">x</a>": "<a>x</a>",
+		"<a href=\"java\tscript:alert(1)\">x</a>": "<a>x</a>",
+		"<a href=\"/about\" title=\"About\">About</a>": "<a href=\"/about\" title=\"About\">About</a>",
+		"<a href=\"mailto:hi@example.com\">Email</a>": "<a href=\"mailto:hi@example.com\">Email</a>",
+		"<style>p { color: red; }</style><p>Text</p>": "<p>Text</p>",
+		"<svg><script>alert(1)</script><text>x</text></svg>ok": "ok",
+		"<object data=\"x.swf\"><embed src=\"x.swf\"></object>ok": "ok",
+		"<iframe src=\"https://evil.com\"></iframe>ok": "ok",
+		"<p style=\"color:red\" class=\"lead\">x</p>": "<p class=\"lead\">x</p>",
+		"<custom>kept text</custom>": "kept text",
+		"<!-- comment --><p>x</p>": "<p>x</p>",
+		"<p><em>unclosed": "<p><em>unclosed</em></p>",
+		"</div>stray": "stray",
+		"<p>1 &lt; 2 &amp; 3</p>": "<p>1 &lt; 2 &amp; 3</p>",
+		"<p title=\"&quot;&gt;&lt;script&gt;\">x</p>": "<p>x</p>",
+	}
+
+	p := DefaultPolicy()
+	for input, expected := range tests {
+		if v := p.Sanitize(input).HTML; v != expected {
+			t.Errorf("%s: expected '%s' but got '%s'", input, expected, v)
+		}
+	}
+}
+
+func TestPolicy_SanitizeReportsRemovals(t *testing.T) {
+	res := DefaultPolicy().Sanitize("<script>a</script><script>b</script><img src=\"a.png\" onerror=\"x\">")
+
+	expected := []string{"<script>", "<img onerror>"}
+	if len(res.Removed) != len(expected) {
+		t.Fatalf("expected %d removals but got %d: %v", len(expected), len(res.Removed), res.Removed)
+	}
+
+	for i, r := range res.Removed {
+		if r.String() != expected[i] {
+			t.Errorf("expected '%s' but got '%s'", expected[i], r.String())
+		}
+	}
+}
+
+func TestPolicy_AllowEmbeds(t *testing.T) {
+	p := DefaultPolicy().AllowEmbeds("www.youtube.com")
+
+	tests := map[string]string{
+		"<iframe src=\"https://www.youtube.com/embed/abc\" allowfullscreen onload=\"x\"></iframe>": "<iframe src=\"https://www.youtube.com/embed/abc\" allowfullscreen=\"\"></iframe>",
+		"<iframe src=\"http://www.youtube.com/embed/abc\"></iframe>": "",
+		"<iframe src=\"https://www.youtube.com.evil.com/embed/abc\"></iframe>": "",
+	}
+
+	for input, expected := range tests {
+		if v := p.Sanitize(input).HTML; v != expected {
+			t.Errorf("%s: expected '%s' but got '%s'", input, expected, v)
+		}
+	}
+}
+
+func TestPolicy_AllowURLSchemes(t *testing.T) {
+	p := NewPolicy().AllowAttributes("a", "href").AllowURLSchemes("https")
+
+	if v := p.Sanitize("<a href=\"http://example.com\">x</a>").HTML; v != "<a>x</a>" {
+		t.Errorf("expected the http link to be removed but got '%s'", v)
+	}
+
+	if v := p.Sanitize("<a href=\"https://example.com\">x</a>").HTML; v != "<a href=\"https://example.com\">x</a>" {
+		t.Errorf("expected the https link to be kept but got '%s'", v)
+	}
+}
@@ -0,0 +1,120 @@
+package shortcode
+
+import (
+	"context"
+	"html"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/reecerussell/distro-blog/libraries/logging"
+)
+
+var (
+	mu       = sync.RWMutex{}
+	handlers = make(map[string]Handler)
+)
+
+// Shortcode is a tag written in content, such as [image id="..."], which is
+// replaced with HTML when the content is read.
+type Shortcode struct {
+	Name string
+	Attrs map[string]string
+
+	// Content is the whole HTML document the shortcode was found in.
+	Content string
+}
+
+// Attr returns the value of the named attribute, or an empty string.
+func (s *Shortcode) Attr(name string) string {
+	return s.Attrs[name]
+}
+
+// Handler is an interface used to render specific shortcodes to HTML.
+type Handler interface {
+	Render(ctx context.Context, s *Shortcode) (string, error)
+}
+
+// HandlerFunc allows ordinary functions to be used as a Handler.
+type HandlerFunc func(ctx context.Context, s *Shortcode) (string, error)
+
+// Render calls f(ctx, s).
+func (f HandlerFunc) Render(ctx context.Context, s *Shortcode) (string, error) {
+	return f(ctx, s)
+}
+
+// RegisterHandler registers a mapping between a shortcode's name and its handler.
+func RegisterHandler(name string, h Handler) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	handlers[strings.ToLower(name)] = h
+}
+
+// Attribute values can be quoted, or not, as well as quoted with HTML-escaped
+// quotes, as content is stored as HTML, where quotes in text may be escaped.
+const attrPattern = `([a-z][a-z0-9-]*)=(?:"([^"]*)"|&#34;(.*?)&#34;|&quot;(.*?)&quot;|([^\s"\]&]+))`
+
+var (
+	tagRegex = regexp.MustCompile(`\[([a-z][a-z0-9-]*)((?:\s+` + attrPattern + `)*)\s*/?\]`)
+	attrRegex = regexp.MustCompile(attrPattern)
+)
+
+// Parse returns the shortcodes found in content, in order.
+func Parse(content string) []*Shortcode {
+	matches := tagRegex.FindAllStringSubmatch(content, -1)
+	codes := make([]*Shortcode, len(matches))
+
+	for i, m := range matches {
+		codes[i] = parse(m, content)
+	}
+
+	return codes
+}
+
+func parse(m []string, content string) *Shortcode {
+	s := &Shortcode{
+		Name: m[1],
+		Attrs: make(map[string]string),
+		Content: content,
+	}
+
+	for _, a := range attrRegex.FindAllStringSubmatch(m[2], -1) {
+		for _, v := range a[2:] {
+			if v != "" {
+				s.Attrs[a[1]] = html.UnescapeString(v)
+				break
+			}
+		}
+
+		if _, ok := s.Attrs[a[1]]; !ok {
+			s.Attrs[a[1]] = ""
+		}
+	}
+
+	return s
+}
+
+// Expand replaces each shortcode in content with the HTML rendered by its handler.
+// Shortcodes without a registered handler are left as they are, whereas shortcodes
+// whose handler fails are removed, so a broken shortcode isn't shown to readers.
+func Expand(ctx context.Context, content string) string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	return tagRegex.ReplaceAllStringFunc(content, func(tag string) string {
+		s := parse(tagRegex.FindStringSubmatch(tag), content)
+		h, ok := handlers[s.Name]
+		if !ok {
+			return tag
+		}
+
+		out, err := h.Render(ctx, s)
+		if err != nil {
+			logging.Errorf("Failed to render shortcode '%s': %v\n", tag, err)
+			return ""
+		}
+
+		return out
+	})
+}
@@ -0,0 +1,50 @@
+package shortcode
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := map[string]string{
+		`[image id="abc" alt="A cat"]`: "abc",
+		`<p>[image id=&#34;abc&#34; alt=&#34;A cat&#34;]</p>`: "abc",
+		`[image id=&quot;abc&quot; alt=&quot;A cat&quot;]`: "abc",
+		`[image id=abc alt="A cat" /]`: "abc",
+	}
+
+	for input, expected := range tests {
+		codes := Parse(input)
+		if len(codes) != 1 {
+			t.Errorf("%s: expected 1 shortcode but got %d", input, len(codes))
+			continue
+		}
+
+		s := codes[0]
+		if s.Name != "image" || s.Attr("id") != expected || s.Attr("alt") != "A cat" {
+			t.Errorf("%s: unexpected shortcode: %v", input, s)
+		}
+	}
+}
+
+func TestExpand(t *testing.T) {
+	RegisterHandler("test-greeting", HandlerFunc(func(ctx context.Context, s *Shortcode) (string, error) {
+		return "Hello " + s.Attr("name"), nil
+	}))
+	RegisterHandler("test-broken", HandlerFunc(func(ctx context.Context, s *Shortcode) (string, error) {
+		return "", fmt.Errorf("broken")
+	}))
+
+	tests := map[string]string{
+		`<p>[test-greeting name="Reece"]</p>`: "<p>Hello Reece</p>",
+		`<p>[test-broken]</p>`: "<p></p>",
+		`<p>[unknown id="1"] and [1]</p>`: `<p>[unknown id="1"] and [1]</p>`,
+	}
+
+	for input, expected := range tests {
+		if v := Expand(context.Background(), input); v != expected {
+			t.Errorf("expected '%s' but got '%s'", expected, v)
+		}
+	}
+}
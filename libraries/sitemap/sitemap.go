@@ -0,0 +1,115 @@
+package sitemap
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+// MaxURLs is the maximum number of urls a single sitemap can contain,
+// as defined by the sitemaps.org protocol.
+const MaxURLs = 50000
+
+const namespace = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+// URL is a single location in a sitemap.
+type URL struct {
+	Loc string
+	LastMod time.Time
+}
+
+// Sitemap is a reference to a sitemap, used in a sitemap index.
+type Sitemap struct {
+	Loc string
+	LastMod time.Time
+}
+
+type urlSet struct {
+	XMLName xml.Name `xml:"urlset"`
+	Namespace string `xml:"xmlns,attr"`
+	URLs []location `xml:"url"`
+}
+
+type sitemapIndex struct {
+	XMLName xml.Name `xml:"sitemapindex"`
+	Namespace string `xml:"xmlns,attr"`
+	Sitemaps []location `xml:"sitemap"`
+}
+
+type location struct {
+	Loc string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+// Build renders the given urls as a sitemap.
+func Build(urls []URL) ([]byte, error) {
+	doc := urlSet{
+		Namespace: namespace,
+		URLs: make([]location, len(urls)),
+	}
+
+	for i, u := range urls {
+		doc.URLs[i] = newLocation(u.Loc, u.LastMod)
+	}
+
+	return marshal(doc)
+}
+
+// BuildIndex renders a sitemap index, referencing the given sitemaps.
+func BuildIndex(sitemaps []Sitemap) ([]byte, error) {
+	doc := sitemapIndex{
+		Namespace: namespace,
+		Sitemaps: make([]location, len(sitemaps)),
+	}
+
+	for i, s := range sitemaps {
+		doc.Sitemaps[i] = newLocation(s.Loc, s.LastMod)
+	}
+
+	return marshal(doc)
+}
+
+// Split splits urls into chunks of at most size urls each. If size is
+// not positive, MaxURLs is used.
+func Split(urls []URL, size int) [][]URL {
+	if size <= 0 {
+		size = MaxURLs
+	}
+
+	var chunks [][]URL
+	for len(urls) > size {
+		chunks = append(chunks, urls[:size])
+		urls = urls[size:]
+	}
+
+	return append(chunks, urls)
+}
+
+// LastModified returns the latest modification date of the given urls.
+func LastModified(urls []URL) time.Time {
+	var latest time.Time
+	for _, u := range urls {
+		if u.LastMod.After(latest) {
+			latest = u.LastMod
+		}
+	}
+
+	return latest
+}
+
+func newLocation(loc string, lastMod time.Time) location {
+	l := location{Loc: loc}
+	if !lastMod.IsZero() {
+		l.LastMod = lastMod.UTC().Format(time.RFC3339)
+	}
+
+	return l
+}
+
+func marshal(v interface{}) ([]byte, error) {
+	data, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), data...), nil
+}
@@ -0,0 +1,78 @@
+package sitemap
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuild(t *testing.T) {
+	urls := []URL{
+		{Loc: "https://example.com/", LastMod: time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)},
+		{Loc: "https://example.com/blog/a&b"},
+	}
+
+	data, err := Build(urls)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	s := string(data)
+	expected := []string{
+		`<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">`,
+		"<loc>https://example.com/</loc>",
+		"<lastmod>2020-06-01T12:00:00Z</lastmod>",
+		"<loc>https://example.com/blog/a&amp;b</loc>",
+	}
+	for _, e := range expected {
+		if !strings.Contains(s, e) {
+			t.Errorf("expected the sitemap to contain '%s' but got:\n%s", e, s)
+		}
+	}
+
+	if strings.Count(s, "<lastmod>") != 1 {
+		t.Errorf("expected lastmod to be omitted for urls without a date")
+	}
+}
+
+func TestBuildIndex(t *testing.T) {
+	data, err := BuildIndex([]Sitemap{{Loc: "https://example.com/sitemap.xml?page=1"}})
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	s := string(data)
+	if !strings.Contains(s, "<sitemapindex") || !strings.Contains(s, "<loc>https://example.com/sitemap.xml?page=1</loc>") {
+		t.Errorf("unexpected sitemap index:\n%s", s)
+	}
+}
+
+func TestSplit(t *testing.T) {
+	urls := make([]URL, 5)
+
+	chunks := Split(urls, 2)
+	if len(chunks) != 3 {
+		t.Fatalf("expected 3 chunks but got %d", len(chunks))
+	}
+
+	if len(chunks[0]) != 2 || len(chunks[2]) != 1 {
+		t.Errorf("unexpected chunk sizes: %d, %d", len(chunks[0]), len(chunks[2]))
+	}
+
+	if l := len(Split(urls, 0)); l != 1 {
+		t.Errorf("expected a single chunk by default but got %d", l)
+	}
+}
+
+func TestLastModified(t *testing.T) {
+	latest := time.Date(2020, 6, 2, 0, 0, 0, 0, time.UTC)
+	urls := []URL{
+		{LastMod: time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)},
+		{LastMod: latest},
+		{},
+	}
+
+	if lm := LastModified(urls); !lm.Equal(latest) {
+		t.Errorf("expected '%v' but got '%v'", latest, lm)
+	}
+}
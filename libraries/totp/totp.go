@@ -0,0 +1,122 @@
+// Package totp implements RFC 6238 time-based one-time passwords, as used
+// by authenticator apps, with SHA-1, 6 digit codes and a 30 second period.
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// Digits is the number of digits in each code.
+	Digits = 6
+
+	// Period is the number of seconds each code is valid for.
+	Period = 30
+
+	// SecretSize is the number of random bytes in a secret, which is
+	// the size recommended by RFC 4226 for HMAC-SHA1.
+	SecretSize = 20
+
+	// Skew is the number of periods either side of the current one, which
+	// codes are also accepted from, to allow for clock drift.
+	Skew = 1
+)
+
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateSecret returns a new, random, base32 encoded secret.
+func GenerateSecret() (string, error) {
+	b := make([]byte, SecretSize)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+
+	return encoding.EncodeToString(b), nil
+}
+
+// Step returns the time step of t, which is the number of periods since the epoch.
+func Step(t time.Time) int64 {
+	return t.Unix() / Period
+}
+
+// Code returns the code for the secret at the given time.
+func Code(secret string, t time.Time) (string, error) {
+	key, err := decodeSecret(secret)
+	if err != nil {
+		return "", err
+	}
+
+	return code(key, Step(t), Digits), nil
+}
+
+// Validate determines whether the code is valid for the secret at the given time,
+// returning the time step it was valid for. Codes for steps at or before lastStep
+// have already been used, so are rejected, ensuring each code can only be used once.
+func Validate(secret, c string, t time.Time, lastStep int64) (int64, bool) {
+	key, err := decodeSecret(secret)
+	if err != nil || len(c) != Digits {
+		return 0, false
+	}
+
+	now := Step(t)
+	for step := now - Skew; step <= now+Skew; step++ {
+		if step <= lastStep {
+			continue
+		}
+
+		if subtle.ConstantTimeCompare([]byte(code(key, step, Digits)), []byte(c)) == 1 {
+			return step, true
+		}
+	}
+
+	return 0, false
+}
+
+// URI returns an otpauth:// URI for the secret, which authenticator apps can
+// read from a QR code. The account is usually the user's email address.
+func URI(issuer, account, secret string) string {
+	label := url.PathEscape(issuer) + ":" + url.PathEscape(account)
+	q := url.Values{
+		"secret": {secret},
+		"issuer": {issuer},
+		"algorithm": {"SHA1"},
+		"digits": {fmt.Sprint(Digits)},
+		"period": {fmt.Sprint(Period)},
+	}
+
+	return "otpauth://totp/" + label + "?" + q.Encode()
+}
+
+func decodeSecret(secret string) ([]byte, error) {
+	return encoding.DecodeString(strings.ToUpper(strings.TrimRight(secret, "=")))
+}
+
+// code implements the RFC 4226 HOTP algorithm, with the step as the counter.
+func code(key []byte, step int64, digits int) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(step))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	v := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for i := 0; i < digits; i++ {
+		mod *= 10
+	}
+
+	return fmt.Sprintf("%0*d", digits, v%mod)
+}
@@ -0,0 +1,72 @@
+package totp
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// the SHA-1 test vectors from RFC 6238, appendix B.
+func TestCode_RFC6238(t *testing.T) {
+	key := []byte("12345678901234567890")
+	tests := map[int64]string{
+		59: "94287082",
+		1111111109: "07081804",
+		1111111111: "14050471",
+		1234567890: "89005924",
+		2000000000: "69279037",
+		20000000000: "65353130",
+	}
+
+	for unix, expected := range tests {
+		if c := code(key, Step(time.Unix(unix, 0)), 8); c != expected {
+			t.Errorf("at %d expected '%s' but got '%s'", unix, expected, c)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	now := time.Date(2020, 7, 1, 12, 0, 0, 0, time.UTC)
+	c, _ := Code(secret, now)
+
+	step, ok := Validate(secret, c, now, 0)
+	if !ok || step != Step(now) {
+		t.Fatalf("expected the code to be valid")
+	}
+
+	if _, ok = Validate(secret, c, now.Add(Period*time.Second), 0); !ok {
+		t.Errorf("expected the previous code to be valid, to allow for clock drift")
+	}
+
+	if _, ok = Validate(secret, c, now.Add(Period*3*time.Second), 0); ok {
+		t.Errorf("expected an old code to be invalid")
+	}
+
+	if _, ok = Validate(secret, c, now, step); ok {
+		t.Errorf("expected a used code to be invalid")
+	}
+
+	if _, ok = Validate(secret, "12345", now, 0); ok {
+		t.Errorf("expected a short code to be invalid")
+	}
+}
+
+func TestURI(t *testing.T) {
+	uri := URI("Distro Blog", "jane@example.com", "JBSWY3DPEHPK3PXP")
+
+	expected := "otpauth://totp/Distro%20Blog:jane@example.com?"
+	if !strings.HasPrefix(uri, expected) {
+		t.Errorf("expected '%s' to start with '%s'", uri, expected)
+	}
+
+	for _, p := range []string{"secret=JBSWY3DPEHPK3PXP", "issuer=Distro+Blog", "digits=6", "period=30"} {
+		if !strings.Contains(uri, p) {
+			t.Errorf("expected '%s' to contain '%s'", uri, p)
+		}
+	}
+}
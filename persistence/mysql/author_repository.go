@@ -0,0 +1,135 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/reecerussell/distro-blog/domain/dto"
+	"github.com/reecerussell/distro-blog/domain/repository"
+	"github.com/reecerussell/distro-blog/libraries/database"
+	"github.com/reecerussell/distro-blog/libraries/logging"
+	"github.com/reecerussell/distro-blog/libraries/result"
+)
+
+const errMsgAuthorDbError = "AUTHOR_SERVER_ERROR"
+
+type authorRepository struct {
+	db *database.MySQL
+}
+
+// NewAuthorRepository returns a new instance of AuthorRepository for a MySQL database.
+func NewAuthorRepository(db *database.MySQL) repository.AuthorRepository {
+	return &authorRepository{
+		db: db,
+	}
+}
+
+// GetPageAuthors returns a list of *dto.BlogAuthor for each of
+// the page's authors, in the order they're shown in its byline.
+func (r *authorRepository) GetPageAuthors(ctx context.Context, pageID string) result.Result {
+	const query string = "CALL `get_page_authors`(?);"
+	items, err := r.db.Multiple(ctx, query, blogAuthorReader, pageID)
+	if err != nil {
+		logging.Error(err)
+		return result.Failure(errMsgAuthorDbError)
+	}
+
+	dtos := make([]*dto.BlogAuthor, len(items))
+
+	for i, item := range items {
+		dtos[i] = item.(*dto.BlogAuthor)
+	}
+
+	return result.Ok().WithValue(dtos)
+}
+
+// SetPageAuthors replaces the authors of the page, in a single transaction. The
+// authors are shown in the same order as the given user ids.
+func (r *authorRepository) SetPageAuthors(ctx context.Context, pageID string, userIDs []string) result.Result {
+	tx, err := r.db.Tx(ctx)
+	defer func() {
+		tx.Finish(err)
+	}()
+	if err != nil {
+		logging.Error(err)
+		return result.Failure(errMsgAuthorDbError)
+	}
+
+	err = tx.Execute(ctx, "CALL `clear_page_authors`(?);", pageID)
+	if err != nil {
+		logging.Error(err)
+		return result.Failure(errMsgAuthorDbError)
+	}
+
+	for i, id := range userIDs {
+		err = tx.Execute(ctx, "CALL `add_page_author`(?,?,?);", pageID, id, i)
+		if err != nil {
+			logging.Error(err)
+			return result.Failure(errMsgAuthorDbError)
+		}
+	}
+
+	return result.Ok()
+}
+
+// ListPosts returns a page of the active blogs written by the user, as a *dto.PagedList
+// of *dto.BlogListItem, with the most recently published first. Blogs without any
+// authors are attributed to the user who created them.
+func (r *authorRepository) ListPosts(ctx context.Context, userID string, limit, offset int) result.Result {
+	const query string = "CALL `get_author_posts`(?,?,?);"
+	args := []interface{}{userID, limit, offset}
+	sets, err := r.db.MultipleSets(ctx, query, args, blogListItemReader, countReader)
+	if err != nil {
+		logging.Error(err)
+		return result.Failure(errMsgAuthorDbError)
+	}
+
+	dtos := make([]*dto.BlogListItem, len(sets[0]))
+
+	for i, item := range sets[0] {
+		dtos[i] = item.(*dto.BlogListItem)
+	}
+
+	var total int64
+	if len(sets[1]) > 0 {
+		total = sets[1][0].(int64)
+	}
+
+	return result.Ok().WithValue(&dto.PagedList{
+		Items: dtos,
+		Total: total,
+		Limit: limit,
+		Offset: offset,
+	})
+}
+
+func blogAuthorReader(s database.ScannerFunc) (interface{}, error) {
+	var a dto.BlogAuthor
+	err := s(
+		&a.ID,
+		&a.Name,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &a, nil
+}
+
+// readBylines reads the JSON array of authors selected from the
+// view_page_bylines view. Nil is returned for pages without any.
+func readBylines(v sql.NullString) []*dto.BlogAuthor {
+	if !v.Valid {
+		return nil
+	}
+
+	var authors []*dto.BlogAuthor
+	err := json.Unmarshal([]byte(v.String), &authors)
+	if err != nil {
+		logging.Errorf("failed to read bylines: %v\n", err)
+		return nil
+	}
+
+	return authors
+}
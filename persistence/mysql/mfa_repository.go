@@ -0,0 +1,148 @@
+package mysql
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/reecerussell/distro-blog/domain/datamodel"
+	"github.com/reecerussell/distro-blog/domain/model"
+	"github.com/reecerussell/distro-blog/domain/repository"
+	"github.com/reecerussell/distro-blog/libraries/database"
+	"github.com/reecerussell/distro-blog/libraries/logging"
+	"github.com/reecerussell/distro-blog/libraries/result"
+)
+
+const (
+	errMsgMFANotFound = "MFA_NOT_FOUND"
+	errMsgMFADbError = "MFA_SERVER_ERROR"
+	errMsgMFALocked = "MFA_LOCKED"
+)
+
+type mfaRepository struct {
+	db *database.MySQL
+}
+
+// NewMFARepository returns a new instance of MFARepository for a MySQL database.
+func NewMFARepository(db *database.MySQL) repository.MFARepository {
+	return &mfaRepository{
+		db: db,
+	}
+}
+
+// Get returns the user's *model.MFA, including the hashes of their recovery codes.
+func (r *mfaRepository) Get(ctx context.Context, userID string) result.Result {
+	const query string = "CALL `get_user_mfa`(?);"
+	args := []interface{}{userID}
+	sets, err := r.db.MultipleSets(ctx, query, args, mfaReader, mfaRecoveryCodeReader)
+	if err != nil {
+		logging.Error(err)
+		return result.Failure(errMsgMFADbError)
+	}
+
+	if len(sets) < 1 || len(sets[0]) < 1 {
+		return result.Failure(errMsgMFANotFound).WithStatusCode(http.StatusNotFound)
+	}
+
+	dm := sets[0][0].(*datamodel.MFA)
+	if len(sets) > 1 {
+		dm.RecoveryCodes = make([]*datamodel.MFARecoveryCode, len(sets[1]))
+		for i, c := range sets[1] {
+			dm.RecoveryCodes[i] = c.(*datamodel.MFARecoveryCode)
+		}
+	}
+
+	return result.Ok().WithValue(model.MFAFromDataModel(dm))
+}
+
+func mfaReader(s database.ScannerFunc) (interface{}, error) {
+	var dm datamodel.MFA
+	err := s(
+		&dm.UserID,
+		&dm.Secret,
+		&dm.ConfirmedAt,
+		&dm.LastUsedStep,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dm, nil
+}
+
+func mfaRecoveryCodeReader(s database.ScannerFunc) (interface{}, error) {
+	var dm datamodel.MFARecoveryCode
+	err := s(
+		&dm.CodeHash,
+		&dm.UsedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dm, nil
+}
+
+// Save creates or updates the user's MFA, replacing their recovery codes. As it's only
+// saved once a code has been verified, saving it also clears the user's failed attempts.
+func (r *mfaRepository) Save(ctx context.Context, m *model.MFA) result.Result {
+	dm := m.DataModel()
+
+	tx, err := r.db.Tx(ctx)
+	defer func() {
+		tx.Finish(err)
+	}()
+	if err != nil {
+		logging.Error(err)
+		return result.Failure(errMsgMFADbError)
+	}
+
+	err = tx.Execute(ctx, "CALL `save_user_mfa`(?,?,?,?);", dm.UserID, dm.Secret, dm.ConfirmedAt, dm.LastUsedStep)
+	if err != nil {
+		logging.Error(err)
+		return result.Failure(errMsgMFADbError)
+	}
+
+	for _, c := range dm.RecoveryCodes {
+		err = tx.Execute(ctx, "CALL `add_user_mfa_recovery_code`(?,?,?);", dm.UserID, c.CodeHash, c.UsedAt)
+		if err != nil {
+			logging.Error(err)
+			return result.Failure(errMsgMFADbError)
+		}
+	}
+
+	return result.Ok()
+}
+
+// Attempt records an attempt to verify a code for the user, before it's checked. Once
+// model.MFAMaxAttempts have been made without a valid code, the user's MFA is locked for
+// model.MFALockoutDuration, and a result with a too many requests status code is returned.
+func (r *mfaRepository) Attempt(ctx context.Context, userID string, now time.Time) result.Result {
+	const query string = "CALL `use_user_mfa_attempt`(?,?,?,?);"
+	c, err := r.db.Count(ctx, query, userID, now, model.MFAMaxAttempts, now.Add(model.MFALockoutDuration))
+	if err != nil {
+		logging.Error(err)
+		return result.Failure(errMsgMFADbError)
+	}
+
+	if c < 1 {
+		return result.Failure(errMsgMFALocked).WithStatusCode(http.StatusTooManyRequests)
+	}
+
+	return result.Ok()
+}
+
+func (r *mfaRepository) Delete(ctx context.Context, userID string) result.Result {
+	const query string = "DELETE FROM `user_mfa` WHERE `user_id` = ?;"
+	ra, err := r.db.Execute(ctx, query, userID)
+	if err != nil {
+		logging.Error(err)
+		return result.Failure(errMsgMFADbError)
+	}
+
+	if ra < 1 {
+		return result.Failure(errMsgMFANotFound).WithStatusCode(http.StatusNotFound)
+	}
+
+	return result.Ok()
+}
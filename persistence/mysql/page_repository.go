@@ -2,7 +2,11 @@ package mysql
 
 import (
 	"context"
+	"database/sql"
 	"net/http"
+	"time"
+
+	driver "github.com/go-sql-driver/mysql"
 
 	"github.com/reecerussell/distro-blog/domain/datamodel"
 	"github.com/reecerussell/distro-blog/domain/dto"
@@ -17,6 +21,13 @@ const (
 	errMsgPageNotFound = "PAGE_NOT_FOUND"
 	errMsgPageDbError = "PAGE_SERVER_ERROR"
 	errMsgPageAuditDbError = "PAGE_AUDIT_SERVER_ERROR"
+	errMsgPageRevisionNotFound = "PAGE_REVISION_NOT_FOUND"
+	errMsgPageRevisionDbError = "PAGE_REVISION_SERVER_ERROR"
+	errMsgPageHasChildren = "PAGE_HAS_CHILDREN"
+
+	// errNumRowIsReferenced is the MySQL error number for a
+	// foreign key constraint failing on a delete.
+	errNumRowIsReferenced = 1451
 )
 
 type pageRepository struct {
@@ -37,12 +48,21 @@ func (r *pageRepository) Get(ctx context.Context, id string) result.Result {
 		return result.Failure(errMsgPageDbError)
 	}
 
+	if dm == nil {
+		return result.Failure(errMsgPageNotFound).WithStatusCode(http.StatusNotFound)
+	}
+
 	page := dm.(*datamodel.Page)
 	page.Seo, err = r.getPageSeo(ctx, id)
 	if err != nil {
 		logging.Errorf("Error getting page SEO: %v", err)
 	}
 
+	page.Draft, err = r.getPageDraft(ctx, id)
+	if err != nil {
+		logging.Errorf("Error getting page draft: %v", err)
+	}
+
 	p := model.PageFromDataModel(page)
 	return result.Ok().WithValue(p)
 }
@@ -54,10 +74,22 @@ func pageReader(s database.ScannerFunc) (interface{}, error) {
 		&dm.Title,
 		&dm.Description,
 		&dm.Content,
+		&dm.ContentFormat,
+		&dm.ContentSource,
+		&dm.Outline,
+		&dm.WordCount,
+		&dm.ReadingTime,
+		&dm.Excerpt,
 		&dm.IsBlog,
 		&dm.IsActive,
 		&dm.ImageID,
 		&dm.URL,
+		&dm.ParentID,
+		&dm.Path,
+		&dm.PublishAt,
+		&dm.UnpublishAt,
+		&dm.PublishedAt,
+		&dm.DeletedAt,
 	)
 	if err != nil {
 		return nil, err
@@ -91,30 +123,109 @@ func seoReader(s database.ScannerFunc) (interface{}, error) {
 	return &dm, err
 }
 
-func (r *pageRepository) ListPages(ctx context.Context) result.Result {
-	const query string = "SELECT * FROM `view_page_list`;"
-	return r.getList(ctx, query)
+func (r *pageRepository) getPageDraft(ctx context.Context, pageId string) (*datamodel.PageDraft, error) {
+	const query string = "CALL `get_page_draft`(?);"
+	dm, err := r.db.Read(ctx, query, pageDraftReader, pageId)
+	if err != nil {
+		return nil, err
+	}
+
+	if dm == nil {
+		return nil, nil
+	}
+
+	return dm.(*datamodel.PageDraft), nil
 }
 
-func (r *pageRepository) ListBlogs(ctx context.Context) result.Result {
-	const query string = "SELECT * FROM `view_blog_list`;"
-	return r.getList(ctx, query)
+func pageDraftReader(s database.ScannerFunc) (interface{}, error) {
+	var (
+		dm datamodel.PageDraft
+		seoTitle sql.NullString
+		seoDescription sql.NullString
+		seoIndex sql.NullBool
+		seoFollow sql.NullBool
+	)
+
+	err := s(
+		&dm.PageID,
+		&dm.UserID,
+		&dm.UserFullname,
+		&dm.Date,
+		&dm.Title,
+		&dm.Description,
+		&dm.Content,
+		&dm.ContentFormat,
+		&dm.URL,
+		&seoTitle,
+		&seoDescription,
+		&seoIndex,
+		&seoFollow,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if seoIndex.Valid {
+		dm.Seo = &datamodel.SEO{
+			Title: seoTitle,
+			Description: seoDescription,
+			Index: seoIndex.Bool,
+			Follow: seoFollow.Bool,
+		}
+	}
+
+	return &dm, nil
+}
+
+// ListPages returns a *dto.PagedList containing a page of *dto.PageListItem
+// for the pages matching the query, as well as the total number of matches.
+func (r *pageRepository) ListPages(ctx context.Context, q *dto.PageListQuery) result.Result {
+	return r.getList(ctx, false, q)
 }
 
-func (r *pageRepository) getList(ctx context.Context, query string) result.Result {
-	items, err := r.db.Multiple(ctx, query, listItemReader)
+// ListBlogs returns a *dto.PagedList containing a page of *dto.PageListItem
+// for the blogs matching the query, as well as the total number of matches.
+func (r *pageRepository) ListBlogs(ctx context.Context, q *dto.PageListQuery) result.Result {
+	return r.getList(ctx, true, q)
+}
+
+func (r *pageRepository) getList(ctx context.Context, isBlog bool, q *dto.PageListQuery) result.Result {
+	const query string = "CALL `get_page_list`(?,?,?,?,?,?,?,?,?);"
+	args := []interface{}{
+		isBlog,
+		nullBool(q.IsActive),
+		sql.NullString{Valid: q.Search != "", String: q.Search},
+		nullTime(q.From),
+		nullTime(q.To),
+		q.SortBy,
+		q.SortDesc,
+		q.Limit,
+		q.Offset,
+	}
+
+	sets, err := r.db.MultipleSets(ctx, query, args, listItemReader, countReader)
 	if err != nil {
 		logging.Error(err)
 		return result.Failure(errMsgPageDbError)
 	}
 
-	dtos := make([]*dto.PageListItem, len(items))
+	dtos := make([]*dto.PageListItem, len(sets[0]))
 
-	for i, dm := range items {
+	for i, dm := range sets[0] {
 		dtos[i] = dm.(*dto.PageListItem)
 	}
 
-	return result.Ok().WithValue(dtos)
+	var total int64
+	if len(sets[1]) > 0 {
+		total = sets[1][0].(int64)
+	}
+
+	return result.Ok().WithValue(&dto.PagedList{
+		Items: dtos,
+		Total: total,
+		Limit: q.Limit,
+		Offset: q.Offset,
+	})
 }
 
 func listItemReader(s database.ScannerFunc) (interface{}, error) {
@@ -123,6 +234,10 @@ func listItemReader(s database.ScannerFunc) (interface{}, error) {
 		&dm.ID,
 		&dm.Title,
 		&dm.Description,
+		&dm.URL,
+		&dm.IsActive,
+		&dm.CreatedAt,
+		&dm.UpdatedAt,
 	)
 	if err != nil {
 		return nil, err
@@ -131,32 +246,78 @@ func listItemReader(s database.ScannerFunc) (interface{}, error) {
 	return &dm, nil
 }
 
+func countReader(s database.ScannerFunc) (interface{}, error) {
+	var c int64
+	err := s(&c)
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func nullBool(b *bool) sql.NullBool {
+	if b == nil {
+		return sql.NullBool{}
+	}
+
+	return sql.NullBool{Valid: true, Bool: *b}
+}
+
+func nullTime(t *time.Time) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{}
+	}
+
+	return sql.NullTime{Valid: true, Time: t.UTC()}
+}
+
 func (r *pageRepository) Create(ctx context.Context, p *model.Page) result.Result {
-	const query string = "CALL `create_page`(?,?,?,?,?,?);"
+	const query string = "CALL `create_page`(?,?,?,?,?,?,?,?,?,?,?,?,?,?);"
 	dm := p.DataModel()
 	args := []interface{}{
 		dm.ID,
 		dm.Title,
 		dm.Description,
 		dm.Content,
+		dm.ContentFormat,
+		dm.ContentSource,
+		dm.Outline,
+		dm.WordCount,
+		dm.ReadingTime,
+		dm.Excerpt,
 		dm.IsBlog,
 		dm.URL,
+		dm.ParentID,
+		dm.Path,
 	}
 
 	return r.executePage(ctx, p, query, args)
 }
 
 func (r *pageRepository) Update(ctx context.Context, p *model.Page) result.Result {
-	const query string = "CALL `update_page`(?,?,?,?,?,?,?);"
+	const query string = "CALL `update_page`(?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?);"
 	dm := p.DataModel()
 	args := []interface{}{
 		dm.ID,
 		dm.Title,
 		dm.Description,
 		dm.Content,
+		dm.ContentFormat,
+		dm.ContentSource,
+		dm.Outline,
+		dm.WordCount,
+		dm.ReadingTime,
+		dm.Excerpt,
 		dm.IsActive,
 		dm.ImageID,
 		dm.URL,
+		dm.ParentID,
+		dm.Path,
+		dm.PublishAt,
+		dm.UnpublishAt,
+		dm.PublishedAt,
+		dm.DeletedAt,
 	}
 
 	return r.executePage(ctx, p, query, args)
@@ -188,20 +349,6 @@ func (r *pageRepository) executePage(ctx context.Context, p *model.Page, query s
 	return result.Ok()
 }
 
-func (r *pageRepository) Delete(ctx context.Context, id string) result.Result {
-	const query string = "DELETE FROM `pages` WHERE `id` = ?;"
-	ra, err := r.db.Execute(ctx, query, id)
-	if err != nil {
-		return result.Failure(errMsgPageDbError)
-	}
-
-	if ra < 1 {
-		return result.Failure(errMsgPageNotFound).WithStatusCode(http.StatusNotFound)
-	}
-
-	return result.Ok()
-}
-
 func (r *pageRepository) GetAudit(ctx context.Context, id string) result.Result {
 	const query string = "CALL `get_page_audit`(?);"
 	items, err := r.db.Multiple(ctx, query, pageAuditReader, id)
@@ -242,11 +389,13 @@ func pageAuditReader(s database.ScannerFunc) (interface{}, error) {
 	return &dm, nil
 }
 
+// CountByURL returns the number of other pages which share the page's url, at the
+// same level of the page tree, i.e. with the same parent, that would share its path.
 func (r *pageRepository) CountByURL(ctx context.Context, p *model.Page) result.Result {
-	const query string = "CALL `count_pages_by_url`(?, ?);"
+	const query string = "CALL `count_pages_by_url`(?, ?, ?, ?);"
 
 	dm := p.DataModel()
-	c, err := r.db.Count(ctx, query, dm.URL, dm.ID)
+	c, err := r.db.Count(ctx, query, dm.URL, dm.ParentID, dm.IsBlog, dm.ID)
 	if err != nil {
 		return result.Failure(err)
 	}
@@ -286,4 +435,323 @@ func pageOptionReader(s database.ScannerFunc) (interface{}, error) {
 	}
 
 	return &dto, nil
-}
\ No newline at end of file
+}
+
+// ListRevisions returns a list of *dto.PageRevision for the page, without
+// each revision's content, ordered by the newest revision first.
+func (r *pageRepository) ListRevisions(ctx context.Context, pageID string) result.Result {
+	const query string = "CALL `get_page_revisions`(?);"
+	items, err := r.db.Multiple(ctx, query, pageRevisionListReader, pageID)
+	if err != nil {
+		logging.Error(err)
+		return result.Failure(errMsgPageRevisionDbError)
+	}
+
+	dtos := make([]*dto.PageRevision, len(items))
+
+	for i, item := range items {
+		dtos[i] = model.PageRevisionFromDataModel(item.(*datamodel.PageRevision)).DTO()
+	}
+
+	return result.Ok().WithValue(dtos)
+}
+
+func pageRevisionListReader(s database.ScannerFunc) (interface{}, error) {
+	var dm datamodel.PageRevision
+	err := s(
+		&dm.ID,
+		&dm.PageID,
+		&dm.Number,
+		&dm.UserID,
+		&dm.UserFullname,
+		&dm.Date,
+		&dm.Title,
+		&dm.Description,
+		&dm.URL,
+		&dm.IsDraft,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dm, nil
+}
+
+// GetRevision returns a *model.PageRevision for the revision with the given id.
+func (r *pageRepository) GetRevision(ctx context.Context, id string) result.Result {
+	const query string = "CALL `get_page_revision`(?);"
+	dm, err := r.db.Read(ctx, query, pageRevisionReader, id)
+	if err != nil {
+		logging.Error(err)
+		return result.Failure(errMsgPageRevisionDbError)
+	}
+
+	if dm == nil {
+		return result.Failure(errMsgPageRevisionNotFound).WithStatusCode(http.StatusNotFound)
+	}
+
+	rev := model.PageRevisionFromDataModel(dm.(*datamodel.PageRevision))
+	return result.Ok().WithValue(rev)
+}
+
+func pageRevisionReader(s database.ScannerFunc) (interface{}, error) {
+	var (
+		dm datamodel.PageRevision
+		seoTitle sql.NullString
+		seoDescription sql.NullString
+		seoIndex sql.NullBool
+		seoFollow sql.NullBool
+	)
+
+	err := s(
+		&dm.ID,
+		&dm.PageID,
+		&dm.Number,
+		&dm.UserID,
+		&dm.UserFullname,
+		&dm.Date,
+		&dm.Title,
+		&dm.Description,
+		&dm.Content,
+		&dm.ContentFormat,
+		&dm.URL,
+		&dm.IsDraft,
+		&seoTitle,
+		&seoDescription,
+		&seoIndex,
+		&seoFollow,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// SEO columns are NULL if the page had no SEO data at the time.
+	if seoIndex.Valid {
+		dm.Seo = &datamodel.SEO{
+			Title: seoTitle,
+			Description: seoDescription,
+			Index: seoIndex.Bool,
+			Follow: seoFollow.Bool,
+		}
+	}
+
+	return &dm, nil
+}
+
+// GetScheduledIDs returns the ids of pages which have a publish or
+// unpublish date on or before the given date.
+func (r *pageRepository) GetScheduledIDs(ctx context.Context, date time.Time) result.Result {
+	const query string = "CALL `get_scheduled_page_ids`(?);"
+	items, err := r.db.Multiple(ctx, query, pageIDReader, date.UTC())
+	if err != nil {
+		logging.Error(err)
+		return result.Failure(errMsgPageDbError)
+	}
+
+	ids := make([]string, len(items))
+
+	for i, item := range items {
+		ids[i] = item.(string)
+	}
+
+	return result.Ok().WithValue(ids)
+}
+
+func pageIDReader(s database.ScannerFunc) (interface{}, error) {
+	var id string
+	err := s(&id)
+	if err != nil {
+		return nil, err
+	}
+
+	return id, nil
+}
+
+// ListPublishedBlogs returns a *dto.PagedList containing a page of *dto.BlogListItem
+// for the active blogs, ordered by the newest first.
+func (r *pageRepository) ListPublishedBlogs(ctx context.Context, limit, offset int) result.Result {
+	const query string = "CALL `get_published_blogs`(?,?);"
+	args := []interface{}{limit, offset}
+	sets, err := r.db.MultipleSets(ctx, query, args, blogListItemReader, countReader)
+	if err != nil {
+		logging.Error(err)
+		return result.Failure(errMsgPageDbError)
+	}
+
+	dtos := make([]*dto.BlogListItem, len(sets[0]))
+
+	for i, item := range sets[0] {
+		dtos[i] = item.(*dto.BlogListItem)
+	}
+
+	var total int64
+	if len(sets[1]) > 0 {
+		total = sets[1][0].(int64)
+	}
+
+	return result.Ok().WithValue(&dto.PagedList{
+		Items: dtos,
+		Total: total,
+		Limit: limit,
+		Offset: offset,
+	})
+}
+
+func blogListItemReader(s database.ScannerFunc) (interface{}, error) {
+	var (
+		item dto.BlogListItem
+		imageID sql.NullString
+		excerpt sql.NullString
+		authors sql.NullString
+	)
+
+	err := s(
+		&item.ID,
+		&item.Title,
+		&item.Description,
+		&item.URL,
+		&imageID,
+		&excerpt,
+		&item.PublishedAt,
+		&item.UpdatedAt,
+		&authors,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if imageID.Valid {
+		item.ImageID = &imageID.String
+	}
+
+	// fallback to the description for blogs without any content.
+	item.Excerpt = item.Description
+	if excerpt.Valid && excerpt.String != "" {
+		item.Excerpt = excerpt.String
+	}
+
+	item.Authors = readBylines(authors)
+	if len(item.Authors) > 0 {
+		item.Author = item.Authors[0]
+	}
+
+	return &item, nil
+}
+
+// ListSitemapEntries returns a []*dto.SitemapEntry for each active page
+// which should be indexed by search engines, according to its SEO settings.
+func (r *pageRepository) ListSitemapEntries(ctx context.Context) result.Result {
+	const query string = "CALL `get_sitemap_entries`();"
+	items, err := r.db.Multiple(ctx, query, sitemapEntryReader)
+	if err != nil {
+		logging.Error(err)
+		return result.Failure(errMsgPageDbError)
+	}
+
+	entries := make([]*dto.SitemapEntry, len(items))
+
+	for i, item := range items {
+		entries[i] = item.(*dto.SitemapEntry)
+	}
+
+	return result.Ok().WithValue(entries)
+}
+
+func sitemapEntryReader(s database.ScannerFunc) (interface{}, error) {
+	var e dto.SitemapEntry
+	err := s(
+		&e.Path,
+		&e.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &e, nil
+}
+
+// ListTrash returns a []*dto.TrashedPage for each page in the
+// trash, with the most recently deleted first.
+func (r *pageRepository) ListTrash(ctx context.Context) result.Result {
+	const query string = "CALL `get_trashed_pages`();"
+	items, err := r.db.Multiple(ctx, query, trashedPageReader)
+	if err != nil {
+		logging.Error(err)
+		return result.Failure(errMsgPageDbError)
+	}
+
+	dtos := make([]*dto.TrashedPage, len(items))
+
+	for i, item := range items {
+		dtos[i] = item.(*dto.TrashedPage)
+	}
+
+	return result.Ok().WithValue(dtos)
+}
+
+func trashedPageReader(s database.ScannerFunc) (interface{}, error) {
+	var d dto.TrashedPage
+	err := s(
+		&d.ID,
+		&d.Title,
+		&d.Path,
+		&d.IsBlog,
+		&d.DeletedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &d, nil
+}
+
+// GetTrashedIDs returns the ids of pages which were moved
+// to the trash on or before the given date.
+func (r *pageRepository) GetTrashedIDs(ctx context.Context, before time.Time) result.Result {
+	const query string = "CALL `get_trashed_page_ids`(?);"
+	items, err := r.db.Multiple(ctx, query, pageIDReader, before.UTC())
+	if err != nil {
+		logging.Error(err)
+		return result.Failure(errMsgPageDbError)
+	}
+
+	ids := make([]string, len(items))
+
+	for i, item := range items {
+		ids[i] = item.(string)
+	}
+
+	return result.Ok().WithValue(ids)
+}
+
+// CountChildren returns the number of pages directly beneath the page with
+// the given id, which aren't in the trash.
+func (r *pageRepository) CountChildren(ctx context.Context, id string) result.Result {
+	const query string = "SELECT COUNT(*) FROM `pages` WHERE `parent_id` = ? AND `deleted_at` IS NULL;"
+	c, err := r.db.Count(ctx, query, id)
+	if err != nil {
+		logging.Error(err)
+		return result.Failure(errMsgPageDbError)
+	}
+
+	return result.Ok().WithValue(c)
+}
+
+// Purge permanently deletes a page which is in the trash, along with its SEO data.
+// Pages can't be purged until the child pages in the trash with them have been.
+// The page's audit history is kept, with the page's title and a PAGE_PURGED
+// audit made by the given user.
+func (r *pageRepository) Purge(ctx context.Context, id, userID string, date time.Time) result.Result {
+	const query string = "CALL `purge_page`(?,?,?);"
+	_, err := r.db.Execute(ctx, query, id, userID, date)
+	if err != nil {
+		if me, ok := err.(*driver.MySQLError); ok && me.Number == errNumRowIsReferenced {
+			return result.Failure(errMsgPageHasChildren).WithStatusCode(http.StatusBadRequest)
+		}
+
+		logging.Error(err)
+		return result.Failure(errMsgPageDbError)
+	}
+
+	return result.Ok()
+}
@@ -0,0 +1,134 @@
+package mysql
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/reecerussell/distro-blog/domain/datamodel"
+	"github.com/reecerussell/distro-blog/domain/dto"
+	"github.com/reecerussell/distro-blog/domain/model"
+	"github.com/reecerussell/distro-blog/domain/repository"
+	"github.com/reecerussell/distro-blog/libraries/database"
+	"github.com/reecerussell/distro-blog/libraries/logging"
+	"github.com/reecerussell/distro-blog/libraries/result"
+)
+
+const (
+	errMsgPageTranslationNotFound = "PAGE_TRANSLATION_NOT_FOUND"
+	errMsgPageTranslationDbError = "PAGE_TRANSLATION_SERVER_ERROR"
+)
+
+type pageTranslationRepository struct {
+	db *database.MySQL
+}
+
+// NewPageTranslationRepository returns a new instance of PageTranslationRepository for a MySQL database.
+func NewPageTranslationRepository(db *database.MySQL) repository.PageTranslationRepository {
+	return &pageTranslationRepository{
+		db: db,
+	}
+}
+
+// List returns a list of *dto.PageTranslation for each of the page's translations, ordered by locale.
+func (r *pageTranslationRepository) List(ctx context.Context, pageID string) result.Result {
+	const query string = "CALL `get_page_translations`(?);"
+	items, err := r.db.Multiple(ctx, query, pageTranslationReader, pageID)
+	if err != nil {
+		logging.Error(err)
+		return result.Failure(errMsgPageTranslationDbError)
+	}
+
+	dtos := make([]*dto.PageTranslation, len(items))
+
+	for i, item := range items {
+		dtos[i] = model.PageTranslationFromDataModel(item.(*datamodel.PageTranslation)).DTO()
+	}
+
+	return result.Ok().WithValue(dtos)
+}
+
+func pageTranslationReader(s database.ScannerFunc) (interface{}, error) {
+	var dm datamodel.PageTranslation
+	err := s(
+		&dm.PageID,
+		&dm.Locale,
+		&dm.Title,
+		&dm.Description,
+		&dm.Content,
+		&dm.ContentFormat,
+		&dm.ContentSource,
+		&dm.Outline,
+		&dm.WordCount,
+		&dm.ReadingTime,
+		&dm.Excerpt,
+		&dm.URL,
+		&dm.Path,
+		&dm.SeoTitle,
+		&dm.SeoDescription,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dm, nil
+}
+
+// Save creates the translation, or replaces it if the page already has a translation in its locale.
+func (r *pageTranslationRepository) Save(ctx context.Context, t *model.PageTranslation) result.Result {
+	const query string = "CALL `save_page_translation`(?,?,?,?,?,?,?,?,?,?,?,?,?,?);"
+	dm := t.DataModel()
+	args := []interface{}{
+		dm.PageID,
+		dm.Locale,
+		dm.Title,
+		dm.Description,
+		dm.Content,
+		dm.ContentFormat,
+		dm.ContentSource,
+		dm.Outline,
+		dm.WordCount,
+		dm.ReadingTime,
+		dm.Excerpt,
+		dm.URL,
+		dm.SeoTitle,
+		dm.SeoDescription,
+	}
+
+	_, err := r.db.Execute(ctx, query, args...)
+	if err != nil {
+		logging.Error(err)
+		return result.Failure(errMsgPageTranslationDbError)
+	}
+
+	return result.Ok()
+}
+
+func (r *pageTranslationRepository) Delete(ctx context.Context, pageID, locale string) result.Result {
+	const query string = "DELETE FROM `page_translations` WHERE `page_id` = ? AND `locale` = ?;"
+	ra, err := r.db.Execute(ctx, query, pageID, locale)
+	if err != nil {
+		logging.Error(err)
+		return result.Failure(errMsgPageTranslationDbError)
+	}
+
+	if ra < 1 {
+		return result.Failure(errMsgPageTranslationNotFound).WithStatusCode(http.StatusNotFound)
+	}
+
+	return result.Ok()
+}
+
+// CountByPath returns the number of pages, and translations of other pages, or in
+// other locales, which are served from the same path as the given translation.
+func (r *pageTranslationRepository) CountByPath(ctx context.Context, t *model.PageTranslation) result.Result {
+	const query string = "CALL `count_page_translations_by_path`(?, ?, ?);"
+
+	dm := t.DataModel()
+	c, err := r.db.Count(ctx, query, dm.Path, dm.PageID, dm.Locale)
+	if err != nil {
+		logging.Error(err)
+		return result.Failure(errMsgPageTranslationDbError)
+	}
+
+	return result.Ok().WithValue(c)
+}
@@ -0,0 +1,95 @@
+package mysql
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/reecerussell/distro-blog/domain/datamodel"
+	"github.com/reecerussell/distro-blog/domain/model"
+	"github.com/reecerussell/distro-blog/domain/repository"
+	"github.com/reecerussell/distro-blog/libraries/database"
+	"github.com/reecerussell/distro-blog/libraries/logging"
+	"github.com/reecerussell/distro-blog/libraries/result"
+)
+
+const (
+	errMsgPasswordResetTokenNotFound = "PASSWORD_RESET_TOKEN_NOT_FOUND"
+	errMsgPasswordResetTokenUsed = "PASSWORD_RESET_TOKEN_USED"
+	errMsgPasswordResetDbError = "PASSWORD_RESET_SERVER_ERROR"
+)
+
+type passwordResetRepository struct {
+	db *database.MySQL
+}
+
+// NewPasswordResetRepository returns a new instance of PasswordResetRepository for a MySQL database.
+func NewPasswordResetRepository(db *database.MySQL) repository.PasswordResetRepository {
+	return &passwordResetRepository{
+		db: db,
+	}
+}
+
+// Create stores the token, removing any of the user's previous
+// tokens which haven't been used, so only the latest can be used.
+func (r *passwordResetRepository) Create(ctx context.Context, t *model.PasswordResetToken) result.Result {
+	const query string = "CALL `create_password_reset_token`(?,?,?,?);"
+	dm := t.DataModel()
+	_, err := r.db.Execute(ctx, query, dm.ID, dm.UserID, dm.TokenHash, dm.ExpiresAt)
+	if err != nil {
+		logging.Error(err)
+		return result.Failure(errMsgPasswordResetDbError)
+	}
+
+	return result.Ok()
+}
+
+// Get returns the *model.PasswordResetToken with the given hash.
+func (r *passwordResetRepository) Get(ctx context.Context, tokenHash string) result.Result {
+	const query string = "CALL `get_password_reset_token`(?);"
+	dm, err := r.db.Read(ctx, query, passwordResetTokenReader, tokenHash)
+	if err != nil {
+		logging.Error(err)
+		return result.Failure(errMsgPasswordResetDbError)
+	}
+
+	if dm == nil {
+		return result.Failure(errMsgPasswordResetTokenNotFound).WithStatusCode(http.StatusNotFound)
+	}
+
+	return result.Ok().WithValue(model.PasswordResetTokenFromDataModel(dm.(*datamodel.PasswordResetToken)))
+}
+
+func passwordResetTokenReader(s database.ScannerFunc) (interface{}, error) {
+	var dm datamodel.PasswordResetToken
+	err := s(
+		&dm.ID,
+		&dm.UserID,
+		&dm.TokenHash,
+		&dm.ExpiresAt,
+		&dm.UsedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dm, nil
+}
+
+// Use marks the token as used, if it hasn't already been used. This is done in a single
+// statement, so only one request can use each token. If the token has already been
+// used since it was read, a result with a conflict status code is returned.
+func (r *passwordResetRepository) Use(ctx context.Context, t *model.PasswordResetToken) result.Result {
+	const query string = "CALL `use_password_reset_token`(?,?);"
+	dm := t.DataModel()
+	c, err := r.db.Count(ctx, query, dm.ID, dm.UsedAt)
+	if err != nil {
+		logging.Error(err)
+		return result.Failure(errMsgPasswordResetDbError)
+	}
+
+	if c < 1 {
+		return result.Failure(errMsgPasswordResetTokenUsed).WithStatusCode(http.StatusConflict)
+	}
+
+	return result.Ok()
+}
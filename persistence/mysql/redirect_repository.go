@@ -0,0 +1,132 @@
+package mysql
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/reecerussell/distro-blog/domain/datamodel"
+	"github.com/reecerussell/distro-blog/domain/dto"
+	"github.com/reecerussell/distro-blog/domain/model"
+	"github.com/reecerussell/distro-blog/domain/repository"
+	"github.com/reecerussell/distro-blog/libraries/database"
+	"github.com/reecerussell/distro-blog/libraries/logging"
+	"github.com/reecerussell/distro-blog/libraries/result"
+)
+
+const (
+	errMsgRedirectNotFound = "REDIRECT_NOT_FOUND"
+	errMsgRedirectDbError = "REDIRECT_SERVER_ERROR"
+)
+
+type redirectRepository struct {
+	db *database.MySQL
+}
+
+func NewRedirectRepository(db *database.MySQL) repository.RedirectRepository {
+	return &redirectRepository{
+		db: db,
+	}
+}
+
+// List returns a list of *dto.Redirect, ordered by their source path.
+func (r *redirectRepository) List(ctx context.Context) result.Result {
+	const query string = "CALL `get_redirects`();"
+	items, err := r.db.Multiple(ctx, query, redirectReader)
+	if err != nil {
+		logging.Error(err)
+		return result.Failure(errMsgRedirectDbError)
+	}
+
+	dtos := make([]*dto.Redirect, len(items))
+
+	for i, item := range items {
+		dtos[i] = model.RedirectFromDataModel(item.(*datamodel.Redirect)).DTO()
+	}
+
+	return result.Ok().WithValue(dtos)
+}
+
+// Get returns a *model.Redirect for the redirect with the given id.
+func (r *redirectRepository) Get(ctx context.Context, id string) result.Result {
+	const query string = "CALL `get_redirect`(?);"
+	return r.read(ctx, query, id)
+}
+
+// GetBySource returns a *model.Redirect for the redirect from the given path.
+func (r *redirectRepository) GetBySource(ctx context.Context, source string) result.Result {
+	const query string = "CALL `get_redirect_by_source`(?);"
+	return r.read(ctx, query, source)
+}
+
+func (r *redirectRepository) read(ctx context.Context, query string, arg interface{}) result.Result {
+	dm, err := r.db.Read(ctx, query, redirectReader, arg)
+	if err != nil {
+		logging.Error(err)
+		return result.Failure(errMsgRedirectDbError)
+	}
+
+	if dm == nil {
+		return result.Failure(errMsgRedirectNotFound).WithStatusCode(http.StatusNotFound)
+	}
+
+	return result.Ok().WithValue(model.RedirectFromDataModel(dm.(*datamodel.Redirect)))
+}
+
+func redirectReader(s database.ScannerFunc) (interface{}, error) {
+	var dm datamodel.Redirect
+	err := s(
+		&dm.ID,
+		&dm.SourcePath,
+		&dm.TargetPath,
+		&dm.StatusCode,
+		&dm.PageID,
+		&dm.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dm, nil
+}
+
+// Create inserts the redirect, or replaces an existing redirect from the same
+// source. Any redirects which targeted the new redirect's source are updated
+// to point straight to its target, preventing chains of redirects.
+func (r *redirectRepository) Create(ctx context.Context, rd *model.Redirect) result.Result {
+	const query string = "CALL `create_redirect`(?,?,?,?,?);"
+	dm := rd.DataModel()
+	_, err := r.db.Execute(ctx, query, dm.ID, dm.SourcePath, dm.TargetPath, dm.StatusCode, dm.PageID)
+	if err != nil {
+		logging.Error(err)
+		return result.Failure(errMsgRedirectDbError)
+	}
+
+	return result.Ok()
+}
+
+func (r *redirectRepository) Update(ctx context.Context, rd *model.Redirect) result.Result {
+	const query string = "CALL `update_redirect`(?,?,?);"
+	dm := rd.DataModel()
+	_, err := r.db.Execute(ctx, query, dm.ID, dm.TargetPath, dm.StatusCode)
+	if err != nil {
+		logging.Error(err)
+		return result.Failure(errMsgRedirectDbError)
+	}
+
+	return result.Ok()
+}
+
+func (r *redirectRepository) Delete(ctx context.Context, id string) result.Result {
+	const query string = "DELETE FROM `redirects` WHERE `id` = ?;"
+	ra, err := r.db.Execute(ctx, query, id)
+	if err != nil {
+		logging.Error(err)
+		return result.Failure(errMsgRedirectDbError)
+	}
+
+	if ra < 1 {
+		return result.Failure(errMsgRedirectNotFound).WithStatusCode(http.StatusNotFound)
+	}
+
+	return result.Ok()
+}
@@ -0,0 +1,146 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/reecerussell/distro-blog/domain/dto"
+	"github.com/reecerussell/distro-blog/domain/repository"
+	"github.com/reecerussell/distro-blog/libraries/database"
+	"github.com/reecerussell/distro-blog/libraries/logging"
+	"github.com/reecerussell/distro-blog/libraries/result"
+)
+
+const errMsgRelatedDbError = "RELATED_SERVER_ERROR"
+
+type relatedPostRepository struct {
+	db *database.MySQL
+}
+
+// NewRelatedPostRepository returns a new instance of RelatedPostRepository for a MySQL database.
+func NewRelatedPostRepository(db *database.MySQL) repository.RelatedPostRepository {
+	return &relatedPostRepository{
+		db: db,
+	}
+}
+
+// ListDocuments returns a []*dto.RelatedDocument for each active blog.
+func (r *relatedPostRepository) ListDocuments(ctx context.Context) result.Result {
+	const query string = "CALL `get_related_documents`();"
+	items, err := r.db.Multiple(ctx, query, relatedDocumentReader)
+	if err != nil {
+		logging.Error(err)
+		return result.Failure(errMsgRelatedDbError)
+	}
+
+	docs := make([]*dto.RelatedDocument, len(items))
+
+	for i, item := range items {
+		docs[i] = item.(*dto.RelatedDocument)
+	}
+
+	return result.Ok().WithValue(docs)
+}
+
+func relatedDocumentReader(s database.ScannerFunc) (interface{}, error) {
+	var (
+		d dto.RelatedDocument
+		content sql.NullString
+	)
+
+	err := s(
+		&d.ID,
+		&d.Title,
+		&d.Description,
+		&content,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if content.Valid {
+		d.Content = &content.String
+	}
+
+	return &d, nil
+}
+
+// Replace replaces all of the stored related posts, in a single transaction, recording
+// the time the refresh started, so requests made since are refreshed next time.
+func (r *relatedPostRepository) Replace(ctx context.Context, posts []*dto.RelatedPost, refreshedAt time.Time) result.Result {
+	tx, err := r.db.Tx(ctx)
+	defer func() {
+		tx.Finish(err)
+	}()
+	if err != nil {
+		logging.Error(err)
+		return result.Failure(errMsgRelatedDbError)
+	}
+
+	err = tx.Execute(ctx, "CALL `clear_related_posts`();")
+	if err != nil {
+		logging.Error(err)
+		return result.Failure(errMsgRelatedDbError)
+	}
+
+	for _, p := range posts {
+		err = tx.Execute(ctx, "CALL `add_related_post`(?,?,?,?);", p.PageID, p.RelatedPageID, p.Score, p.Position)
+		if err != nil {
+			logging.Error(err)
+			return result.Failure(errMsgRelatedDbError)
+		}
+	}
+
+	err = tx.Execute(ctx, "CALL `set_related_posts_refreshed`(?);", refreshedAt.UTC())
+	if err != nil {
+		logging.Error(err)
+		return result.Failure(errMsgRelatedDbError)
+	}
+
+	return result.Ok()
+}
+
+// RequestRefresh records that the related posts need to be refreshed, as a blog has changed.
+func (r *relatedPostRepository) RequestRefresh(ctx context.Context, date time.Time) result.Result {
+	const query string = "CALL `request_related_posts_refresh`(?);"
+	_, err := r.db.Execute(ctx, query, date.UTC())
+	if err != nil {
+		logging.Error(err)
+		return result.Failure(errMsgRelatedDbError)
+	}
+
+	return result.Ok()
+}
+
+// NeedsRefresh returns true as the result's value, if a refresh has
+// been requested since the related posts were last refreshed.
+func (r *relatedPostRepository) NeedsRefresh(ctx context.Context) result.Result {
+	const query string = "CALL `get_related_posts_refresh_needed`();"
+	c, err := r.db.Count(ctx, query)
+	if err != nil {
+		logging.Error(err)
+		return result.Failure(errMsgRelatedDbError)
+	}
+
+	return result.Ok().WithValue(c > 0)
+}
+
+// List returns a []*dto.BlogListItem of up to limit active blogs
+// related to the page, with the most related first.
+func (r *relatedPostRepository) List(ctx context.Context, pageID string, limit int) result.Result {
+	const query string = "CALL `get_related_posts`(?,?);"
+	items, err := r.db.Multiple(ctx, query, blogListItemReader, pageID, limit)
+	if err != nil {
+		logging.Error(err)
+		return result.Failure(errMsgRelatedDbError)
+	}
+
+	dtos := make([]*dto.BlogListItem, len(items))
+
+	for i, item := range items {
+		dtos[i] = item.(*dto.BlogListItem)
+	}
+
+	return result.Ok().WithValue(dtos)
+}
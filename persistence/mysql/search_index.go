@@ -0,0 +1,85 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/reecerussell/distro-blog/domain/dto"
+	"github.com/reecerussell/distro-blog/domain/repository"
+	"github.com/reecerussell/distro-blog/libraries/database"
+	"github.com/reecerussell/distro-blog/libraries/logging"
+	"github.com/reecerussell/distro-blog/libraries/result"
+)
+
+const errMsgSearchDbError = "SEARCH_SERVER_ERROR"
+
+// searchIndex is an implementation of repository.SearchIndex, which uses
+// MySQL FULLTEXT indexes over the title, description and content of pages
+// and, when inactive pages are included, of their drafts.
+type searchIndex struct {
+	db *database.MySQL
+}
+
+// NewSearchIndex returns a new instance of SearchIndex for a MySQL database.
+func NewSearchIndex(db *database.MySQL) repository.SearchIndex {
+	return &searchIndex{
+		db: db,
+	}
+}
+
+// Search returns a page of the pages matching the query, in natural language mode,
+// ordered by relevance. Matches in a page's title are weighted above the rest.
+func (i *searchIndex) Search(ctx context.Context, query string, includeInactive bool, limit, offset int) result.Result {
+	const q string = "CALL `search_pages`(?,?,?,?);"
+	args := []interface{}{query, includeInactive, limit, offset}
+	sets, err := i.db.MultipleSets(ctx, q, args, searchHitReader, countReader)
+	if err != nil {
+		logging.Error(err)
+		return result.Failure(errMsgSearchDbError)
+	}
+
+	hits := make([]*dto.SearchHit, len(sets[0]))
+
+	for i, item := range sets[0] {
+		hits[i] = item.(*dto.SearchHit)
+	}
+
+	var total int64
+	if len(sets[1]) > 0 {
+		total = sets[1][0].(int64)
+	}
+
+	return result.Ok().WithValue(&dto.PagedList{
+		Items: hits,
+		Total: total,
+		Limit: limit,
+		Offset: offset,
+	})
+}
+
+func searchHitReader(s database.ScannerFunc) (interface{}, error) {
+	var (
+		h dto.SearchHit
+		content sql.NullString
+	)
+
+	err := s(
+		&h.ID,
+		&h.Title,
+		&h.Description,
+		&h.Path,
+		&content,
+		&h.IsBlog,
+		&h.IsActive,
+		&h.Score,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if content.Valid {
+		h.Content = &content.String
+	}
+
+	return &h, nil
+}
@@ -0,0 +1,213 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+
+	"github.com/reecerussell/distro-blog/domain/datamodel"
+	"github.com/reecerussell/distro-blog/domain/dto"
+	"github.com/reecerussell/distro-blog/domain/model"
+	"github.com/reecerussell/distro-blog/domain/repository"
+	"github.com/reecerussell/distro-blog/libraries/database"
+	"github.com/reecerussell/distro-blog/libraries/logging"
+	"github.com/reecerussell/distro-blog/libraries/result"
+)
+
+const (
+	errMsgSeriesNotFound = "SERIES_NOT_FOUND"
+	errMsgSeriesDbError = "SERIES_SERVER_ERROR"
+)
+
+type seriesRepository struct {
+	db *database.MySQL
+}
+
+// NewSeriesRepository returns a new instance of SeriesRepository for a MySQL database.
+func NewSeriesRepository(db *database.MySQL) repository.SeriesRepository {
+	return &seriesRepository{
+		db: db,
+	}
+}
+
+// List returns a list of *dto.SeriesListItem, including the number of blogs in each series.
+func (r *seriesRepository) List(ctx context.Context) result.Result {
+	const query string = "CALL `get_series_list`();"
+	items, err := r.db.Multiple(ctx, query, seriesListItemReader)
+	if err != nil {
+		logging.Error(err)
+		return result.Failure(errMsgSeriesDbError)
+	}
+
+	dtos := make([]*dto.SeriesListItem, len(items))
+
+	for i, item := range items {
+		dtos[i] = item.(*dto.SeriesListItem)
+	}
+
+	return result.Ok().WithValue(dtos)
+}
+
+func seriesListItemReader(s database.ScannerFunc) (interface{}, error) {
+	var (
+		item dto.SeriesListItem
+		description sql.NullString
+	)
+
+	err := s(
+		&item.ID,
+		&item.Title,
+		&description,
+		&item.PageCount,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if description.Valid {
+		item.Description = &description.String
+	}
+
+	return &item, nil
+}
+
+// Get returns a *model.Series for the series with the given id, including its blogs.
+func (r *seriesRepository) Get(ctx context.Context, id string) result.Result {
+	const query string = "CALL `get_series`(?);"
+	args := []interface{}{id}
+	sets, err := r.db.MultipleSets(ctx, query, args, seriesReader, seriesPageReader)
+	if err != nil {
+		logging.Error(err)
+		return result.Failure(errMsgSeriesDbError)
+	}
+
+	if len(sets) < 1 || len(sets[0]) < 1 {
+		return result.Failure(errMsgSeriesNotFound).WithStatusCode(http.StatusNotFound)
+	}
+
+	dm := sets[0][0].(*datamodel.Series)
+	if len(sets) > 1 {
+		dm.Pages = make([]*datamodel.SeriesPage, len(sets[1]))
+		for i, p := range sets[1] {
+			dm.Pages[i] = p.(*datamodel.SeriesPage)
+		}
+	}
+
+	return result.Ok().WithValue(model.SeriesFromDataModel(dm))
+}
+
+func seriesReader(s database.ScannerFunc) (interface{}, error) {
+	var dm datamodel.Series
+	err := s(
+		&dm.ID,
+		&dm.Title,
+		&dm.Description,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dm, nil
+}
+
+func seriesPageReader(s database.ScannerFunc) (interface{}, error) {
+	var dm datamodel.SeriesPage
+	err := s(
+		&dm.PageID,
+		&dm.Title,
+		&dm.Path,
+		&dm.IsActive,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dm, nil
+}
+
+// GetSeriesIDByPage returns the id of the series the page is part of. If the
+// page isn't part of a series, a result with a 404 status code is returned.
+func (r *seriesRepository) GetSeriesIDByPage(ctx context.Context, pageID string) result.Result {
+	const query string = "CALL `get_page_series_id`(?);"
+	v, err := r.db.Read(ctx, query, seriesIDReader, pageID)
+	if err != nil && err != sql.ErrNoRows {
+		logging.Error(err)
+		return result.Failure(errMsgSeriesDbError)
+	}
+
+	if v == nil || err == sql.ErrNoRows {
+		return result.Failure(errMsgSeriesNotFound).WithStatusCode(http.StatusNotFound)
+	}
+
+	return result.Ok().WithValue(v)
+}
+
+func seriesIDReader(s database.ScannerFunc) (interface{}, error) {
+	var id string
+	err := s(&id)
+	if err != nil {
+		return nil, err
+	}
+
+	return id, nil
+}
+
+func (r *seriesRepository) Create(ctx context.Context, s *model.Series) result.Result {
+	return r.execute(ctx, s, "CALL `create_series`(?,?,?);")
+}
+
+func (r *seriesRepository) Update(ctx context.Context, s *model.Series) result.Result {
+	return r.execute(ctx, s, "CALL `update_series`(?,?,?);")
+}
+
+// execute writes the series with the given query, then replaces
+// its blogs, in their current order, in a single transaction.
+func (r *seriesRepository) execute(ctx context.Context, s *model.Series, query string) result.Result {
+	dm := s.DataModel()
+
+	tx, err := r.db.Tx(ctx)
+	defer func() {
+		tx.Finish(err)
+	}()
+	if err != nil {
+		logging.Error(err)
+		return result.Failure(errMsgSeriesDbError)
+	}
+
+	err = tx.Execute(ctx, query, dm.ID, dm.Title, dm.Description)
+	if err != nil {
+		logging.Error(err)
+		return result.Failure(errMsgSeriesDbError)
+	}
+
+	err = tx.Execute(ctx, "CALL `clear_series_pages`(?);", dm.ID)
+	if err != nil {
+		logging.Error(err)
+		return result.Failure(errMsgSeriesDbError)
+	}
+
+	for i, p := range dm.Pages {
+		err = tx.Execute(ctx, "CALL `add_series_page`(?,?,?);", dm.ID, p.PageID, i)
+		if err != nil {
+			logging.Error(err)
+			return result.Failure(errMsgSeriesDbError)
+		}
+	}
+
+	return result.Ok()
+}
+
+func (r *seriesRepository) Delete(ctx context.Context, id string) result.Result {
+	const query string = "DELETE FROM `series` WHERE `id` = ?;"
+	ra, err := r.db.Execute(ctx, query, id)
+	if err != nil {
+		logging.Error(err)
+		return result.Failure(errMsgSeriesDbError)
+	}
+
+	if ra < 1 {
+		return result.Failure(errMsgSeriesNotFound).WithStatusCode(http.StatusNotFound)
+	}
+
+	return result.Ok()
+}
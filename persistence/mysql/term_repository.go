@@ -0,0 +1,229 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+
+	"github.com/reecerussell/distro-blog/domain/datamodel"
+	"github.com/reecerussell/distro-blog/domain/dto"
+	"github.com/reecerussell/distro-blog/domain/model"
+	"github.com/reecerussell/distro-blog/domain/repository"
+	"github.com/reecerussell/distro-blog/libraries/database"
+	"github.com/reecerussell/distro-blog/libraries/logging"
+	"github.com/reecerussell/distro-blog/libraries/result"
+)
+
+const (
+	errMsgTermNotFound = "TERM_NOT_FOUND"
+	errMsgTermDbError = "TERM_SERVER_ERROR"
+)
+
+type termRepository struct {
+	db *database.MySQL
+}
+
+func NewTermRepository(db *database.MySQL) repository.TermRepository {
+	return &termRepository{
+		db: db,
+	}
+}
+
+// List returns a list of *dto.Term of the given type, including the number of pages
+// assigned to each term. If termType is empty, terms of all types are returned.
+func (r *termRepository) List(ctx context.Context, termType string) result.Result {
+	const query string = "CALL `get_terms`(?);"
+	t := sql.NullString{
+		Valid: termType != "",
+		String: termType,
+	}
+
+	items, err := r.db.Multiple(ctx, query, termListReader, t)
+	if err != nil {
+		logging.Error(err)
+		return result.Failure(errMsgTermDbError)
+	}
+
+	dtos := make([]*dto.Term, len(items))
+
+	for i, item := range items {
+		dtos[i] = item.(*dto.Term)
+	}
+
+	return result.Ok().WithValue(dtos)
+}
+
+func termListReader(s database.ScannerFunc) (interface{}, error) {
+	var dto dto.Term
+	err := s(
+		&dto.ID,
+		&dto.Type,
+		&dto.Name,
+		&dto.Slug,
+		&dto.PageCount,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto, nil
+}
+
+// Get returns a *model.Term for the term with the given id.
+func (r *termRepository) Get(ctx context.Context, id string) result.Result {
+	const query string = "CALL `get_term`(?);"
+	dm, err := r.db.Read(ctx, query, termReader, id)
+	if err != nil {
+		logging.Error(err)
+		return result.Failure(errMsgTermDbError)
+	}
+
+	if dm == nil {
+		return result.Failure(errMsgTermNotFound).WithStatusCode(http.StatusNotFound)
+	}
+
+	t := model.TermFromDataModel(dm.(*datamodel.Term))
+	return result.Ok().WithValue(t)
+}
+
+func termReader(s database.ScannerFunc) (interface{}, error) {
+	var dm datamodel.Term
+	err := s(
+		&dm.ID,
+		&dm.Type,
+		&dm.Name,
+		&dm.Slug,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dm, nil
+}
+
+func (r *termRepository) Create(ctx context.Context, t *model.Term) result.Result {
+	const query string = "CALL `create_term`(?,?,?,?);"
+	dm := t.DataModel()
+	_, err := r.db.Execute(ctx, query, dm.ID, dm.Type, dm.Name, dm.Slug)
+	if err != nil {
+		logging.Error(err)
+		return result.Failure(errMsgTermDbError)
+	}
+
+	return result.Ok()
+}
+
+func (r *termRepository) Update(ctx context.Context, t *model.Term) result.Result {
+	const query string = "CALL `update_term`(?,?,?);"
+	dm := t.DataModel()
+	_, err := r.db.Execute(ctx, query, dm.ID, dm.Name, dm.Slug)
+	if err != nil {
+		logging.Error(err)
+		return result.Failure(errMsgTermDbError)
+	}
+
+	return result.Ok()
+}
+
+func (r *termRepository) Delete(ctx context.Context, id string) result.Result {
+	const query string = "DELETE FROM `terms` WHERE `id` = ?;"
+	ra, err := r.db.Execute(ctx, query, id)
+	if err != nil {
+		logging.Error(err)
+		return result.Failure(errMsgTermDbError)
+	}
+
+	if ra < 1 {
+		return result.Failure(errMsgTermNotFound).WithStatusCode(http.StatusNotFound)
+	}
+
+	return result.Ok()
+}
+
+// Merge moves all pages assigned to source onto target, then deletes source.
+func (r *termRepository) Merge(ctx context.Context, source, target *model.Term) result.Result {
+	const query string = "CALL `merge_terms`(?,?);"
+	_, err := r.db.Execute(ctx, query, source.GetID(), target.GetID())
+	if err != nil {
+		logging.Error(err)
+		return result.Failure(errMsgTermDbError)
+	}
+
+	return result.Ok()
+}
+
+func (r *termRepository) CountBySlug(ctx context.Context, t *model.Term) result.Result {
+	const query string = "CALL `count_terms_by_slug`(?,?,?);"
+	dm := t.DataModel()
+	c, err := r.db.Count(ctx, query, dm.Type, dm.Slug, dm.ID)
+	if err != nil {
+		return result.Failure(err)
+	}
+
+	return result.Ok().WithValue(c)
+}
+
+// GetPageTerms returns a list of *dto.Term which the page is assigned to.
+func (r *termRepository) GetPageTerms(ctx context.Context, pageID string) result.Result {
+	const query string = "CALL `get_page_terms`(?);"
+	items, err := r.db.Multiple(ctx, query, termListReader, pageID)
+	if err != nil {
+		logging.Error(err)
+		return result.Failure(errMsgTermDbError)
+	}
+
+	dtos := make([]*dto.Term, len(items))
+
+	for i, item := range items {
+		dtos[i] = item.(*dto.Term)
+	}
+
+	return result.Ok().WithValue(dtos)
+}
+
+// SetPageTerms replaces the terms the page is assigned to, in a single transaction.
+func (r *termRepository) SetPageTerms(ctx context.Context, pageID string, termIDs []string) result.Result {
+	tx, err := r.db.Tx(ctx)
+	defer func() {
+		tx.Finish(err)
+	}()
+	if err != nil {
+		logging.Error(err)
+		return result.Failure(errMsgTermDbError)
+	}
+
+	err = tx.Execute(ctx, "CALL `clear_page_terms`(?);", pageID)
+	if err != nil {
+		logging.Error(err)
+		return result.Failure(errMsgTermDbError)
+	}
+
+	for _, id := range termIDs {
+		err = tx.Execute(ctx, "CALL `add_page_term`(?,?);", pageID, id)
+		if err != nil {
+			logging.Error(err)
+			return result.Failure(errMsgTermDbError)
+		}
+	}
+
+	return result.Ok()
+}
+
+// ListBlogsByTerm returns a list of *dto.BlogListItem for each active blog
+// assigned to the term with the given type and slug.
+func (r *termRepository) ListBlogsByTerm(ctx context.Context, termType, slug string) result.Result {
+	const query string = "CALL `get_blogs_by_term`(?,?);"
+	items, err := r.db.Multiple(ctx, query, blogListItemReader, termType, slug)
+	if err != nil {
+		logging.Error(err)
+		return result.Failure(errMsgTermDbError)
+	}
+
+	dtos := make([]*dto.BlogListItem, len(items))
+
+	for i, item := range items {
+		dtos[i] = item.(*dto.BlogListItem)
+	}
+
+	return result.Ok().WithValue(dtos)
+}
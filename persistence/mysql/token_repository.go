@@ -0,0 +1,147 @@
+package mysql
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/reecerussell/distro-blog/domain/datamodel"
+	"github.com/reecerussell/distro-blog/domain/model"
+	"github.com/reecerussell/distro-blog/domain/repository"
+	"github.com/reecerussell/distro-blog/libraries/database"
+	"github.com/reecerussell/distro-blog/libraries/logging"
+	"github.com/reecerussell/distro-blog/libraries/result"
+)
+
+const (
+	errMsgRefreshTokenNotFound = "REFRESH_TOKEN_NOT_FOUND"
+	errMsgRefreshTokenUsed = "REFRESH_TOKEN_USED"
+	errMsgTokenDbError = "TOKEN_SERVER_ERROR"
+)
+
+type tokenRepository struct {
+	db *database.MySQL
+}
+
+// NewTokenRepository returns a new instance of TokenRepository for a MySQL database.
+func NewTokenRepository(db *database.MySQL) repository.TokenRepository {
+	return &tokenRepository{
+		db: db,
+	}
+}
+
+func (r *tokenRepository) CreateRefreshToken(ctx context.Context, t *model.RefreshToken) result.Result {
+	const query string = "CALL `create_refresh_token`(?,?,?,?,?,?);"
+	dm := t.DataModel()
+	_, err := r.db.Execute(ctx, query, dm.ID, dm.UserID, dm.FamilyID, dm.TokenHash, dm.MFAVerified, dm.ExpiresAt)
+	if err != nil {
+		logging.Error(err)
+		return result.Failure(errMsgTokenDbError)
+	}
+
+	return result.Ok()
+}
+
+// GetRefreshToken returns the *model.RefreshToken with the given hash.
+func (r *tokenRepository) GetRefreshToken(ctx context.Context, tokenHash string) result.Result {
+	const query string = "CALL `get_refresh_token`(?);"
+	dm, err := r.db.Read(ctx, query, refreshTokenReader, tokenHash)
+	if err != nil {
+		logging.Error(err)
+		return result.Failure(errMsgTokenDbError)
+	}
+
+	if dm == nil {
+		return result.Failure(errMsgRefreshTokenNotFound).WithStatusCode(http.StatusNotFound)
+	}
+
+	return result.Ok().WithValue(model.RefreshTokenFromDataModel(dm.(*datamodel.RefreshToken)))
+}
+
+func refreshTokenReader(s database.ScannerFunc) (interface{}, error) {
+	var dm datamodel.RefreshToken
+	err := s(
+		&dm.ID,
+		&dm.UserID,
+		&dm.FamilyID,
+		&dm.TokenHash,
+		&dm.MFAVerified,
+		&dm.ExpiresAt,
+		&dm.UsedAt,
+		&dm.RevokedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dm, nil
+}
+
+// RotateRefreshToken marks the used token as used, and stores the token which replaces it,
+// in a single transaction. If the used token has already been used, or revoked, since it
+// was read, a result with a conflict status code is returned, as it's been used more than once.
+func (r *tokenRepository) RotateRefreshToken(ctx context.Context, used, next *model.RefreshToken) result.Result {
+	const query string = "CALL `rotate_refresh_token`(?,?,?,?,?,?,?,?);"
+	u, n := used.DataModel(), next.DataModel()
+	c, err := r.db.Count(ctx, query, u.ID, u.UsedAt, n.ID, n.UserID, n.FamilyID, n.TokenHash, n.MFAVerified, n.ExpiresAt)
+	if err != nil {
+		logging.Error(err)
+		return result.Failure(errMsgTokenDbError)
+	}
+
+	if c < 1 {
+		return result.Failure(errMsgRefreshTokenUsed).WithStatusCode(http.StatusConflict)
+	}
+
+	return result.Ok()
+}
+
+func (r *tokenRepository) RevokeRefreshTokenFamily(ctx context.Context, familyID string) result.Result {
+	const query string = "CALL `revoke_refresh_token_family`(?,?);"
+	_, err := r.db.Execute(ctx, query, familyID, time.Now().UTC())
+	if err != nil {
+		logging.Error(err)
+		return result.Failure(errMsgTokenDbError)
+	}
+
+	return result.Ok()
+}
+
+// RevokeToken adds the access token's id to the denylist, until it expires.
+func (r *tokenRepository) RevokeToken(ctx context.Context, tokenID string, expiresAt time.Time) result.Result {
+	const query string = "CALL `revoke_token`(?,?);"
+	_, err := r.db.Execute(ctx, query, tokenID, expiresAt)
+	if err != nil {
+		logging.Error(err)
+		return result.Failure(errMsgTokenDbError)
+	}
+
+	return result.Ok()
+}
+
+// RevokeUserTokens revokes all of the user's refresh tokens, and
+// any access tokens which have been issued to them until now.
+func (r *tokenRepository) RevokeUserTokens(ctx context.Context, userID string) result.Result {
+	const query string = "CALL `revoke_user_tokens`(?,?);"
+	_, err := r.db.Execute(ctx, query, userID, time.Now().UTC())
+	if err != nil {
+		logging.Error(err)
+		return result.Failure(errMsgTokenDbError)
+	}
+
+	return result.Ok()
+}
+
+// IsRevoked determines whether an access token has been revoked, either by its id, or
+// because all of the user's tokens have been revoked since it was issued. Tokens issued
+// to users which no longer exist are also treated as revoked. The result's value is a bool.
+func (r *tokenRepository) IsRevoked(ctx context.Context, tokenID, userID string, issuedAt time.Time) result.Result {
+	const query string = "CALL `is_token_revoked`(?,?,?);"
+	c, err := r.db.Count(ctx, query, tokenID, userID, issuedAt)
+	if err != nil {
+		logging.Error(err)
+		return result.Failure(errMsgTokenDbError)
+	}
+
+	return result.Ok().WithValue(c > 0)
+}
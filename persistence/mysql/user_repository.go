@@ -107,6 +107,10 @@ func userReader(s database.ScannerFunc) (interface{}, error) {
 		&dm.Email,
 		&dm.NormalizedEmail,
 		&dm.PasswordHash,
+		&dm.DisplayName,
+		&dm.Bio,
+		&dm.AvatarImageID,
+		&dm.SocialLinks,
 	)
 	if err != nil {
 		return nil, err
@@ -179,7 +183,7 @@ func (r *userRepository) CountByEmail(ctx context.Context, u *model.User) result
 
 // Update modifies an existing user record in the database, with the updated domain model.
 func (r *userRepository) Update(ctx context.Context, u *model.User) result.Result {
-	const query string = "CALL `update_user`(?,?,?,?,?,?);"
+	const query string = "CALL `update_user`(?,?,?,?,?,?,?,?,?,?);"
 	dm := u.DataModel()
 	args := []interface{}{
 		dm.ID,
@@ -188,6 +192,10 @@ func (r *userRepository) Update(ctx context.Context, u *model.User) result.Resul
 		dm.Email,
 		dm.NormalizedEmail,
 		dm.PasswordHash,
+		dm.DisplayName,
+		dm.Bio,
+		dm.AvatarImageID,
+		dm.SocialLinks,
 	}
 
 	tx, err := r.db.Tx(ctx)
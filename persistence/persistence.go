@@ -64,4 +64,104 @@ func NewNavigationRepository(db interface{}) repository.NavigationRepository {
 	default:
 		panic("unsupported database type")
 	}
-}
\ No newline at end of file
+}
+
+// NewTermRepository returns and instance of TermRepository for the given database type.
+func NewTermRepository(db interface{}) repository.TermRepository {
+	switch db.(type) {
+	case *database.MySQL:
+		return mysql.NewTermRepository(db.(*database.MySQL))
+	default:
+		panic("unsupported database type")
+	}
+}
+
+// NewRedirectRepository returns and instance of RedirectRepository for the given database type.
+func NewRedirectRepository(db interface{}) repository.RedirectRepository {
+	switch db.(type) {
+	case *database.MySQL:
+		return mysql.NewRedirectRepository(db.(*database.MySQL))
+	default:
+		panic("unsupported database type")
+	}
+}
+
+// NewSearchIndex returns an instance of SearchIndex for the given database type.
+func NewSearchIndex(db interface{}) repository.SearchIndex {
+	switch db.(type) {
+	case *database.MySQL:
+		return mysql.NewSearchIndex(db.(*database.MySQL))
+	default:
+		panic("unsupported database type")
+	}
+}
+
+// NewAuthorRepository returns an instance of AuthorRepository for the given database type.
+func NewAuthorRepository(db interface{}) repository.AuthorRepository {
+	switch db.(type) {
+	case *database.MySQL:
+		return mysql.NewAuthorRepository(db.(*database.MySQL))
+	default:
+		panic("unsupported database type")
+	}
+}
+
+// NewRelatedPostRepository returns an instance of RelatedPostRepository for the given database type.
+func NewRelatedPostRepository(db interface{}) repository.RelatedPostRepository {
+	switch db.(type) {
+	case *database.MySQL:
+		return mysql.NewRelatedPostRepository(db.(*database.MySQL))
+	default:
+		panic("unsupported database type")
+	}
+}
+
+// NewSeriesRepository returns an instance of SeriesRepository for the given database type.
+func NewSeriesRepository(db interface{}) repository.SeriesRepository {
+	switch db.(type) {
+	case *database.MySQL:
+		return mysql.NewSeriesRepository(db.(*database.MySQL))
+	default:
+		panic("unsupported database type")
+	}
+}
+
+// NewPageTranslationRepository returns an instance of PageTranslationRepository for the given database type.
+func NewPageTranslationRepository(db interface{}) repository.PageTranslationRepository {
+	switch db.(type) {
+	case *database.MySQL:
+		return mysql.NewPageTranslationRepository(db.(*database.MySQL))
+	default:
+		panic("unsupported database type")
+	}
+}
+
+// NewTokenRepository returns an instance of TokenRepository for the given database type.
+func NewTokenRepository(db interface{}) repository.TokenRepository {
+	switch db.(type) {
+	case *database.MySQL:
+		return mysql.NewTokenRepository(db.(*database.MySQL))
+	default:
+		panic("unsupported database type")
+	}
+}
+
+// NewMFARepository returns an instance of MFARepository for the given database type.
+func NewMFARepository(db interface{}) repository.MFARepository {
+	switch db.(type) {
+	case *database.MySQL:
+		return mysql.NewMFARepository(db.(*database.MySQL))
+	default:
+		panic("unsupported database type")
+	}
+}
+
+// NewPasswordResetRepository returns an instance of PasswordResetRepository for the given database type.
+func NewPasswordResetRepository(db interface{}) repository.PasswordResetRepository {
+	switch db.(type) {
+	case *database.MySQL:
+		return mysql.NewPasswordResetRepository(db.(*database.MySQL))
+	default:
+		panic("unsupported database type")
+	}
+}
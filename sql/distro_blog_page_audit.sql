@@ -34,14 +34,15 @@ DROP TABLE IF EXISTS `page_audit`;
 /*!50503 SET character_set_client = utf8mb4 */;
 CREATE TABLE `page_audit` (
   `id` varchar(128) NOT NULL,
-  `page_id` varchar(128) NOT NULL,
+  `page_id` varchar(128) DEFAULT NULL,
+  `page_title` varchar(255) DEFAULT NULL,
   `user_id` varchar(128) NOT NULL,
   `date` datetime NOT NULL,
   `message` varchar(255) NOT NULL,
   PRIMARY KEY (`id`),
   KEY `fk_page_audit_page_idx` (`page_id`),
   KEY `fk_page_audit_user_idx` (`user_id`),
-  CONSTRAINT `fk_page_audit_page` FOREIGN KEY (`page_id`) REFERENCES `pages` (`id`) ON DELETE CASCADE,
+  CONSTRAINT `fk_page_audit_page` FOREIGN KEY (`page_id`) REFERENCES `pages` (`id`) ON DELETE SET NULL,
   CONSTRAINT `fk_page_audit_user` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
 /*!40101 SET character_set_client = @saved_cs_client */;
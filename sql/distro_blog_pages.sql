@@ -37,15 +37,39 @@ CREATE TABLE `pages` (
   `title` varchar(255) NOT NULL,
   `description` varchar(255) NOT NULL,
   `content` text,
+  `content_format` varchar(16) NOT NULL DEFAULT 'html',
+  `content_source` text,
+  `outline` json DEFAULT NULL,
+  `word_count` int NOT NULL DEFAULT '0',
+  `reading_time` int NOT NULL DEFAULT '0',
+  `excerpt` varchar(255) DEFAULT NULL,
   `is_blog` bit(1) NOT NULL DEFAULT b'0',
   `is_active` bit(1) NOT NULL DEFAULT b'0',
   `image_id` varchar(128) DEFAULT NULL,
   `url` varchar(255) NOT NULL,
+  `parent_id` varchar(128) DEFAULT NULL,
+  `path` varchar(255) NOT NULL,
   `seo_id` varchar(128) DEFAULT NULL,
+  `publish_at` datetime DEFAULT NULL,
+  `unpublish_at` datetime DEFAULT NULL,
+  `published_at` datetime DEFAULT NULL,
+  `deleted_at` datetime DEFAULT NULL,
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
   PRIMARY KEY (`id`,`is_blog`,`is_active`),
-  UNIQUE KEY `url_UNIQUE` (`url`),
+  UNIQUE KEY `path_UNIQUE` (`path`),
+  UNIQUE KEY `idx_page_parent_url` (`parent_id`,`is_blog`,`url`),
   KEY `fk_page_image_idx` (`image_id`),
   KEY `fk_page_Seo_idx` (`seo_id`),
+  KEY `idx_page_publish_at` (`publish_at`),
+  KEY `idx_page_unpublish_at` (`unpublish_at`),
+  KEY `idx_page_created_at` (`created_at`),
+  KEY `idx_page_updated_at` (`updated_at`),
+  KEY `idx_page_published_at` (`published_at`),
+  KEY `idx_page_deleted_at` (`deleted_at`),
+  FULLTEXT KEY `ft_page_title` (`title`),
+  FULLTEXT KEY `ft_page_search` (`title`,`description`,`content`),
+  CONSTRAINT `fk_page_parent` FOREIGN KEY (`parent_id`) REFERENCES `pages` (`id`) ON DELETE RESTRICT,
   CONSTRAINT `fk_page_image` FOREIGN KEY (`image_id`) REFERENCES `images` (`id`) ON DELETE SET NULL,
   CONSTRAINT `fk_page_seo` FOREIGN KEY (`seo_id`) REFERENCES `seo` (`id`) ON DELETE CASCADE
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
@@ -80,6 +80,33 @@ SET @saved_cs_client     = @@character_set_client;
  1 AS `Description`*/;
 SET character_set_client = @saved_cs_client;
 
+--
+-- Temporary view structure for view `view_page_bylines`
+--
+
+DROP TABLE IF EXISTS `view_page_bylines`;
+/*!50001 DROP VIEW IF EXISTS `view_page_bylines`*/;
+SET @saved_cs_client     = @@character_set_client;
+/*!50503 SET character_set_client = utf8mb4 */;
+/*!50001 CREATE VIEW `view_page_bylines` AS SELECT 
+ 1 AS `PageId`,
+ 1 AS `Authors`*/;
+SET character_set_client = @saved_cs_client;
+
+--
+-- Temporary view structure for view `view_page_translation_paths`
+--
+
+DROP TABLE IF EXISTS `view_page_translation_paths`;
+/*!50001 DROP VIEW IF EXISTS `view_page_translation_paths`*/;
+SET @saved_cs_client     = @@character_set_client;
+/*!50503 SET character_set_client = utf8mb4 */;
+/*!50001 CREATE VIEW `view_page_translation_paths` AS SELECT 
+ 1 AS `PageId`,
+ 1 AS `Locale`,
+ 1 AS `Path`*/;
+SET character_set_client = @saved_cs_client;
+
 --
 -- Final view structure for view `view_setting_list`
 --
@@ -152,6 +179,42 @@ SET character_set_client = @saved_cs_client;
 /*!50001 SET character_set_results     = @saved_cs_results */;
 /*!50001 SET collation_connection      = @saved_col_connection */;
 
+--
+-- Final view structure for view `view_page_bylines`
+--
+
+/*!50001 DROP VIEW IF EXISTS `view_page_bylines`*/;
+/*!50001 SET @saved_cs_client          = @@character_set_client */;
+/*!50001 SET @saved_cs_results         = @@character_set_results */;
+/*!50001 SET @saved_col_connection     = @@collation_connection */;
+/*!50001 SET character_set_client      = utf8mb4 */;
+/*!50001 SET character_set_results     = utf8mb4 */;
+/*!50001 SET collation_connection      = utf8mb4_0900_ai_ci */;
+/*!50001 CREATE ALGORITHM=UNDEFINED */
+/*!50013 DEFINER=`distro-user`@`%` SQL SECURITY DEFINER */
+/*!50001 VIEW `view_page_bylines` AS select `p`.`id` AS `PageId`,ifnull((select concat('[',group_concat(json_object('id',`u`.`id`,'name',ifnull(`u`.`display_name`,concat(`u`.`first_name`,' ',`u`.`last_name`))) order by `pa`.`position` ASC separator ','),']') from (`page_authors` `pa` join `users` `u` on((`u`.`id` = `pa`.`user_id`))) where (`pa`.`page_id` = `p`.`id`)),(select concat('[',json_object('id',`u`.`id`,'name',ifnull(`u`.`display_name`,concat(`u`.`first_name`,' ',`u`.`last_name`))),']') from (`page_audit` `a` join `users` `u` on((`u`.`id` = `a`.`user_id`))) where ((`a`.`page_id` = `p`.`id`) and (`a`.`message` = 'PAGE_CREATED')) limit 1)) AS `Authors` from `pages` `p` */;
+/*!50001 SET character_set_client      = @saved_cs_client */;
+/*!50001 SET character_set_results     = @saved_cs_results */;
+/*!50001 SET collation_connection      = @saved_col_connection */;
+
+--
+-- Final view structure for view `view_page_translation_paths`
+--
+
+/*!50001 DROP VIEW IF EXISTS `view_page_translation_paths`*/;
+/*!50001 SET @saved_cs_client          = @@character_set_client */;
+/*!50001 SET @saved_cs_results         = @@character_set_results */;
+/*!50001 SET @saved_col_connection     = @@collation_connection */;
+/*!50001 SET character_set_client      = utf8mb4 */;
+/*!50001 SET character_set_results     = utf8mb4 */;
+/*!50001 SET collation_connection      = utf8mb4_0900_ai_ci */;
+/*!50001 CREATE ALGORITHM=UNDEFINED */
+/*!50013 DEFINER=`distro-user`@`%` SQL SECURITY DEFINER */
+/*!50001 VIEW `view_page_translation_paths` AS select `t`.`page_id` AS `PageId`,`t`.`locale` AS `Locale`,trim(trailing '/' from concat(`t`.`locale`,'/',left(`p`.`path`,(char_length(`p`.`path`) - char_length(`p`.`url`))),ifnull(`t`.`url`,`p`.`url`))) AS `Path` from (`page_translations` `t` join `pages` `p` on((`p`.`id` = `t`.`page_id`))) */;
+/*!50001 SET character_set_client      = @saved_cs_client */;
+/*!50001 SET character_set_results     = @saved_cs_results */;
+/*!50001 SET collation_connection      = @saved_col_connection */;
+
 --
 -- Dumping events for database 'distro_blog'
 --
@@ -179,7 +242,7 @@ DELIMITER ;
 /*!50003 SET character_set_client  = @saved_cs_client */ ;
 /*!50003 SET character_set_results = @saved_cs_results */ ;
 /*!50003 SET collation_connection  = @saved_col_connection */ ;
-/*!50003 DROP PROCEDURE IF EXISTS `add_user_audit` */;
+/*!50003 DROP PROCEDURE IF EXISTS `add_page_author` */;
 /*!50003 SET @saved_cs_client      = @@character_set_client */ ;
 /*!50003 SET @saved_cs_results     = @@character_set_results */ ;
 /*!50003 SET @saved_col_connection = @@collation_connection */ ;
@@ -189,22 +252,16 @@ DELIMITER ;
 /*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
 /*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
 DELIMITER ;;
-CREATE DEFINER=`distro-user`@`%` PROCEDURE `add_user_audit`(
-	IN message VARCHAR(255), 
-    IN createdDate DATETIME, 
-    IN userId VARCHAR(128), 
-    IN performingUserId VARCHAR(128),
-    IN stateJson TEXT)
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `add_page_author`(IN pageId VARCHAR(128), IN userId VARCHAR(128), IN authorPosition INT)
 BEGIN
-	INSERT INTO `user_audit` (`id`, `user_id`, `performed_by_id`, `date`, `message`,`state`)
-		VALUES (UUID(), userId, performingUserId, createdDate, message, stateJson);
+	INSERT INTO `page_authors` (`page_id`, `user_id`, `position`) VALUES (pageId, userId, authorPosition);
 END ;;
 DELIMITER ;
 /*!50003 SET sql_mode              = @saved_sql_mode */ ;
 /*!50003 SET character_set_client  = @saved_cs_client */ ;
 /*!50003 SET character_set_results = @saved_cs_results */ ;
 /*!50003 SET collation_connection  = @saved_col_connection */ ;
-/*!50003 DROP PROCEDURE IF EXISTS `count_pages_by_url` */;
+/*!50003 DROP PROCEDURE IF EXISTS `add_page_revision` */;
 /*!50003 SET @saved_cs_client      = @@character_set_client */ ;
 /*!50003 SET @saved_cs_results     = @@character_set_results */ ;
 /*!50003 SET @saved_col_connection = @@collation_connection */ ;
@@ -214,16 +271,24 @@ DELIMITER ;
 /*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
 /*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
 DELIMITER ;;
-CREATE DEFINER=`distro-user`@`%` PROCEDURE `count_pages_by_url`(IN pageUrl VARCHAR(255), IN excludePageId VARCHAR(128))
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `add_page_revision`(IN revisionId VARCHAR(128), IN pageId VARCHAR(128), IN userId VARCHAR(128), IN revisionDate DATETIME,
+	IN pageTitle VARCHAR(255), IN pageDescription VARCHAR(255), IN pageContent TEXT, IN contentFormat VARCHAR(16), IN pageUrl VARCHAR(255),
+	IN isDraft TINYINT(1), IN seoTitle VARCHAR(255), IN seoDescription VARCHAR(255), IN seoIndex TINYINT(1), IN seoFollow TINYINT(1))
 BEGIN
-	SELECT COUNT(*) FROM `pages` WHERE `url` = pageUrl AND `id` != excludePageId;
+	DECLARE revisionNumber INT;
+	SELECT IFNULL(MAX(`number`), 0) + 1 INTO revisionNumber FROM `page_revisions` WHERE `page_id` = pageId;
+
+	INSERT INTO `page_revisions` (`id`, `page_id`, `number`, `user_id`, `date`, `title`, `description`, `content`, `content_format`, `url`,
+		`is_draft`, `seo_title`, `seo_description`, `seo_index`, `seo_follow`)
+		VALUES (revisionId, pageId, revisionNumber, userId, revisionDate, pageTitle, pageDescription, pageContent, contentFormat, pageUrl,
+			isDraft, seoTitle, seoDescription, seoIndex, seoFollow);
 END ;;
 DELIMITER ;
 /*!50003 SET sql_mode              = @saved_sql_mode */ ;
 /*!50003 SET character_set_client  = @saved_cs_client */ ;
 /*!50003 SET character_set_results = @saved_cs_results */ ;
 /*!50003 SET collation_connection  = @saved_col_connection */ ;
-/*!50003 DROP PROCEDURE IF EXISTS `count_users_by_email` */;
+/*!50003 DROP PROCEDURE IF EXISTS `add_page_term` */;
 /*!50003 SET @saved_cs_client      = @@character_set_client */ ;
 /*!50003 SET @saved_cs_results     = @@character_set_results */ ;
 /*!50003 SET @saved_col_connection = @@collation_connection */ ;
@@ -233,16 +298,16 @@ DELIMITER ;
 /*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
 /*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
 DELIMITER ;;
-CREATE DEFINER=`distro-user`@`%` PROCEDURE `count_users_by_email`(IN normalizedEmail VARCHAR(255), IN excludeId VARCHAR(128))
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `add_page_term`(IN pageId VARCHAR(128), IN termId VARCHAR(128))
 BEGIN
-	SELECT COUNT(*) FROM `users` WHERE `normalized_email` = normalizedEmail AND `id` != excludeId;
+	INSERT INTO `page_terms` (`page_id`, `term_id`) VALUES (pageId, termId);
 END ;;
 DELIMITER ;
 /*!50003 SET sql_mode              = @saved_sql_mode */ ;
 /*!50003 SET character_set_client  = @saved_cs_client */ ;
 /*!50003 SET character_set_results = @saved_cs_results */ ;
 /*!50003 SET collation_connection  = @saved_col_connection */ ;
-/*!50003 DROP PROCEDURE IF EXISTS `create_image` */;
+/*!50003 DROP PROCEDURE IF EXISTS `add_related_post` */;
 /*!50003 SET @saved_cs_client      = @@character_set_client */ ;
 /*!50003 SET @saved_cs_results     = @@character_set_results */ ;
 /*!50003 SET @saved_col_connection = @@collation_connection */ ;
@@ -252,17 +317,17 @@ DELIMITER ;
 /*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
 /*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
 DELIMITER ;;
-CREATE DEFINER=`distro-user`@`%` PROCEDURE `create_image`(IN imageId VARCHAR(128), IN typeId VARCHAR(128))
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `add_related_post`(IN pageId VARCHAR(128), IN relatedPageId VARCHAR(128), IN relatedScore DOUBLE, IN relatedPosition INT)
 BEGIN
-	INSERT INTO `images` (`id`, `type_id`, `alternative_text`) 
-		VALUES (imageId, typeId, NULL);
+	INSERT INTO `related_posts` (`page_id`, `related_page_id`, `score`, `position`)
+		VALUES (pageId, relatedPageId, relatedScore, relatedPosition);
 END ;;
 DELIMITER ;
 /*!50003 SET sql_mode              = @saved_sql_mode */ ;
 /*!50003 SET character_set_client  = @saved_cs_client */ ;
 /*!50003 SET character_set_results = @saved_cs_results */ ;
 /*!50003 SET collation_connection  = @saved_col_connection */ ;
-/*!50003 DROP PROCEDURE IF EXISTS `create_page` */;
+/*!50003 DROP PROCEDURE IF EXISTS `add_series_page` */;
 /*!50003 SET @saved_cs_client      = @@character_set_client */ ;
 /*!50003 SET @saved_cs_results     = @@character_set_results */ ;
 /*!50003 SET @saved_col_connection = @@collation_connection */ ;
@@ -272,18 +337,16 @@ DELIMITER ;
 /*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
 /*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
 DELIMITER ;;
-CREATE DEFINER=`distro-user`@`%` PROCEDURE `create_page`(IN pageId VARCHAR(128), IN pageTitle VARCHAR(255), IN pageDescription VARCHAR(255), IN pageContent TEXT,
-	IN isPageBlog BIT, IN pageUrl VARCHAR(255))
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `add_series_page`(IN seriesId VARCHAR(128), IN pageId VARCHAR(128), IN pagePosition INT)
 BEGIN
-	INSERT INTO `pages` (`id`,`title`,`description`,`content`,`is_blog`,`is_active`, `url`) 
-		VALUES (pageId, pageTitle, pageDescription, pageContent, isPageBlog, FALSE, pageUrl);
+	INSERT INTO `series_pages` (`series_id`, `page_id`, `position`) VALUES (seriesId, pageId, pagePosition);
 END ;;
 DELIMITER ;
 /*!50003 SET sql_mode              = @saved_sql_mode */ ;
 /*!50003 SET character_set_client  = @saved_cs_client */ ;
 /*!50003 SET character_set_results = @saved_cs_results */ ;
 /*!50003 SET collation_connection  = @saved_col_connection */ ;
-/*!50003 DROP PROCEDURE IF EXISTS `create_user` */;
+/*!50003 DROP PROCEDURE IF EXISTS `add_user_audit` */;
 /*!50003 SET @saved_cs_client      = @@character_set_client */ ;
 /*!50003 SET @saved_cs_results     = @@character_set_results */ ;
 /*!50003 SET @saved_col_connection = @@collation_connection */ ;
@@ -291,19 +354,24 @@ DELIMITER ;
 /*!50003 SET character_set_results = utf8mb4 */ ;
 /*!50003 SET collation_connection  = utf8mb4_0900_ai_ci */ ;
 /*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
-/*!50003 SET sql_mode              = 'STRICT_TRANS_TABLES,NO_ENGINE_SUBSTITUTION' */ ;
+/*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
 DELIMITER ;;
-CREATE DEFINER=`root`@`%` PROCEDURE `create_user`(IN id VARCHAR(128), IN firstName VARCHAR(45), IN lastName VARCHAR(45), IN email VARCHAR(255), IN normalizedEmail VARCHAR(255), IN passwordHash TEXT)
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `add_user_audit`(
+	IN message VARCHAR(255), 
+    IN createdDate DATETIME, 
+    IN userId VARCHAR(128), 
+    IN performingUserId VARCHAR(128),
+    IN stateJson TEXT)
 BEGIN
-	INSERT INTO `users` (`id`, `first_name`, `last_name`, `email`, `normalized_email`, `password_hash`)
-		VALUES (id, firstName, lastName, email, normalizedEmail, passwordHash);
+	INSERT INTO `user_audit` (`id`, `user_id`, `performed_by_id`, `date`, `message`,`state`)
+		VALUES (UUID(), userId, performingUserId, createdDate, message, stateJson);
 END ;;
 DELIMITER ;
 /*!50003 SET sql_mode              = @saved_sql_mode */ ;
 /*!50003 SET character_set_client  = @saved_cs_client */ ;
 /*!50003 SET character_set_results = @saved_cs_results */ ;
 /*!50003 SET collation_connection  = @saved_col_connection */ ;
-/*!50003 DROP PROCEDURE IF EXISTS `delete_image` */;
+/*!50003 DROP PROCEDURE IF EXISTS `add_user_mfa_recovery_code` */;
 /*!50003 SET @saved_cs_client      = @@character_set_client */ ;
 /*!50003 SET @saved_cs_results     = @@character_set_results */ ;
 /*!50003 SET @saved_col_connection = @@collation_connection */ ;
@@ -313,16 +381,17 @@ DELIMITER ;
 /*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
 /*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
 DELIMITER ;;
-CREATE DEFINER=`distro-user`@`%` PROCEDURE `delete_image`(IN imageId VARCHAR(128))
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `add_user_mfa_recovery_code`(IN userId VARCHAR(128), IN codeHash CHAR(64), IN usedAt DATETIME)
 BEGIN
-	DELETE FROM images WHERE id = imageId;
+	INSERT INTO user_mfa_recovery_codes (user_id, code_hash, used_at)
+		VALUES (userId, codeHash, usedAt);
 END ;;
 DELIMITER ;
 /*!50003 SET sql_mode              = @saved_sql_mode */ ;
 /*!50003 SET character_set_client  = @saved_cs_client */ ;
 /*!50003 SET character_set_results = @saved_cs_results */ ;
 /*!50003 SET collation_connection  = @saved_col_connection */ ;
-/*!50003 DROP PROCEDURE IF EXISTS `delete_user` */;
+/*!50003 DROP PROCEDURE IF EXISTS `clear_page_authors` */;
 /*!50003 SET @saved_cs_client      = @@character_set_client */ ;
 /*!50003 SET @saved_cs_results     = @@character_set_results */ ;
 /*!50003 SET @saved_col_connection = @@collation_connection */ ;
@@ -332,16 +401,16 @@ DELIMITER ;
 /*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
 /*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
 DELIMITER ;;
-CREATE DEFINER=`root`@`%` PROCEDURE `delete_user`(IN userId VARCHAR(128))
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `clear_page_authors`(IN pageId VARCHAR(128))
 BEGIN
-	DELETE FROM `users` WHERE `id` = userId;
+	DELETE FROM `page_authors` WHERE `page_id` = pageId;
 END ;;
 DELIMITER ;
 /*!50003 SET sql_mode              = @saved_sql_mode */ ;
 /*!50003 SET character_set_client  = @saved_cs_client */ ;
 /*!50003 SET character_set_results = @saved_cs_results */ ;
 /*!50003 SET collation_connection  = @saved_col_connection */ ;
-/*!50003 DROP PROCEDURE IF EXISTS `get_image` */;
+/*!50003 DROP PROCEDURE IF EXISTS `clear_page_terms` */;
 /*!50003 SET @saved_cs_client      = @@character_set_client */ ;
 /*!50003 SET @saved_cs_results     = @@character_set_results */ ;
 /*!50003 SET @saved_col_connection = @@collation_connection */ ;
@@ -351,21 +420,16 @@ DELIMITER ;
 /*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
 /*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
 DELIMITER ;;
-CREATE DEFINER=`distro-user`@`%` PROCEDURE `get_image`(IN imageId VARCHAR(128))
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `clear_page_terms`(IN pageId VARCHAR(128))
 BEGIN
-	SELECT
-		id as `Id`,
-        type_id as `TypeId`,
-        alternative_text `AltText`
-	FROM images
-    WHERE id = imageId;
+	DELETE FROM `page_terms` WHERE `page_id` = pageId;
 END ;;
 DELIMITER ;
 /*!50003 SET sql_mode              = @saved_sql_mode */ ;
 /*!50003 SET character_set_client  = @saved_cs_client */ ;
 /*!50003 SET character_set_results = @saved_cs_results */ ;
 /*!50003 SET collation_connection  = @saved_col_connection */ ;
-/*!50003 DROP PROCEDURE IF EXISTS `get_image_type` */;
+/*!50003 DROP PROCEDURE IF EXISTS `clear_related_posts` */;
 /*!50003 SET @saved_cs_client      = @@character_set_client */ ;
 /*!50003 SET @saved_cs_results     = @@character_set_results */ ;
 /*!50003 SET @saved_col_connection = @@collation_connection */ ;
@@ -375,19 +439,16 @@ DELIMITER ;
 /*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
 /*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
 DELIMITER ;;
-CREATE DEFINER=`distro-user`@`%` PROCEDURE `get_image_type`(IN imageId VARCHAR(128))
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `clear_related_posts`()
 BEGIN
-	SELECT t.`name`
-    FROM images AS i
-		INNER JOIN image_types AS t ON t.id = i.type_id
-	WHERE i.id = imageId;
+	DELETE FROM `related_posts`;
 END ;;
 DELIMITER ;
 /*!50003 SET sql_mode              = @saved_sql_mode */ ;
 /*!50003 SET character_set_client  = @saved_cs_client */ ;
 /*!50003 SET character_set_results = @saved_cs_results */ ;
 /*!50003 SET collation_connection  = @saved_col_connection */ ;
-/*!50003 DROP PROCEDURE IF EXISTS `get_image_type_by_name` */;
+/*!50003 DROP PROCEDURE IF EXISTS `clear_series_pages` */;
 /*!50003 SET @saved_cs_client      = @@character_set_client */ ;
 /*!50003 SET @saved_cs_results     = @@character_set_results */ ;
 /*!50003 SET @saved_col_connection = @@collation_connection */ ;
@@ -397,20 +458,16 @@ DELIMITER ;
 /*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
 /*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
 DELIMITER ;;
-CREATE DEFINER=`distro-user`@`%` PROCEDURE `get_image_type_by_name`(IN normalizedName VARCHAR(255))
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `clear_series_pages`(IN seriesId VARCHAR(128))
 BEGIN
-	SELECT
-		id AS `Id`,
-		`name` AS `Name`
-	FROM `image_types`
-    WHERE `name` = normalizedName;
+	DELETE FROM `series_pages` WHERE `series_id` = seriesId;
 END ;;
 DELIMITER ;
 /*!50003 SET sql_mode              = @saved_sql_mode */ ;
 /*!50003 SET character_set_client  = @saved_cs_client */ ;
 /*!50003 SET character_set_results = @saved_cs_results */ ;
 /*!50003 SET collation_connection  = @saved_col_connection */ ;
-/*!50003 DROP PROCEDURE IF EXISTS `get_page` */;
+/*!50003 DROP PROCEDURE IF EXISTS `count_page_translations_by_path` */;
 /*!50003 SET @saved_cs_client      = @@character_set_client */ ;
 /*!50003 SET @saved_cs_results     = @@character_set_results */ ;
 /*!50003 SET @saved_col_connection = @@collation_connection */ ;
@@ -420,26 +477,19 @@ DELIMITER ;
 /*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
 /*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
 DELIMITER ;;
-CREATE DEFINER=`distro-user`@`%` PROCEDURE `get_page`(IN pageId VARCHAR(128))
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `count_page_translations_by_path`(IN pagePath VARCHAR(255), IN pageId VARCHAR(128), IN pageLocale VARCHAR(20))
 BEGIN
 	SELECT
-		id AS `Id`,
-        title as `Title`,
-        `description` as `Description`,
-        content as `Content`,
-        is_blog = b'1' as `IsBlog`,
-        is_active = b'1' as `IsActive`,
-        image_id as `ImageId`,
-        url as `Url`
-	FROM `pages`
-    WHERE id = pageId;
+		(SELECT COUNT(*) FROM pages AS p WHERE p.`path` = pagePath)
+		+ (SELECT COUNT(*) FROM view_page_translation_paths AS tp
+			WHERE tp.Path = pagePath AND NOT (tp.PageId = pageId AND tp.Locale = pageLocale));
 END ;;
 DELIMITER ;
 /*!50003 SET sql_mode              = @saved_sql_mode */ ;
 /*!50003 SET character_set_client  = @saved_cs_client */ ;
 /*!50003 SET character_set_results = @saved_cs_results */ ;
 /*!50003 SET collation_connection  = @saved_col_connection */ ;
-/*!50003 DROP PROCEDURE IF EXISTS `get_page_audit` */;
+/*!50003 DROP PROCEDURE IF EXISTS `count_pages_by_url` */;
 /*!50003 SET @saved_cs_client      = @@character_set_client */ ;
 /*!50003 SET @saved_cs_results     = @@character_set_results */ ;
 /*!50003 SET @saved_col_connection = @@collation_connection */ ;
@@ -449,27 +499,17 @@ DELIMITER ;
 /*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
 /*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
 DELIMITER ;;
-CREATE DEFINER=`distro-user`@`%` PROCEDURE `get_page_audit`(IN pageId VARCHAR(128))
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `count_pages_by_url`(IN pageUrl VARCHAR(255), IN parentId VARCHAR(128), IN isPageBlog BIT, IN excludePageId VARCHAR(128))
 BEGIN
-SELECT 
-    u.id AS `UserId`,
-    CONCAT(u.first_name, ' ', u.last_name) AS `UserFullname`,
-    a.message AS `Message`,
-    a.`date` AS `Date`
-FROM
-    page_audit AS a
-        INNER JOIN
-    users AS u ON u.id = a.user_id
-WHERE
-    a.page_id = pageId
-Order by a.date desc;
+	SELECT COUNT(*) FROM `pages`
+	WHERE `url` = pageUrl AND `parent_id` <=> parentId AND `is_blog` = isPageBlog AND `id` != excludePageId;
 END ;;
 DELIMITER ;
 /*!50003 SET sql_mode              = @saved_sql_mode */ ;
 /*!50003 SET character_set_client  = @saved_cs_client */ ;
 /*!50003 SET character_set_results = @saved_cs_results */ ;
 /*!50003 SET collation_connection  = @saved_col_connection */ ;
-/*!50003 DROP PROCEDURE IF EXISTS `get_page_seo` */;
+/*!50003 DROP PROCEDURE IF EXISTS `count_terms_by_slug` */;
 /*!50003 SET @saved_cs_client      = @@character_set_client */ ;
 /*!50003 SET @saved_cs_results     = @@character_set_results */ ;
 /*!50003 SET @saved_col_connection = @@collation_connection */ ;
@@ -479,23 +519,17 @@ DELIMITER ;
 /*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
 /*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
 DELIMITER ;;
-CREATE DEFINER=`distro-user`@`%` PROCEDURE `get_page_seo`(IN pageId VARCHAR(255))
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `count_terms_by_slug`(IN termType VARCHAR(16), IN termSlug VARCHAR(255), IN excludeTermId VARCHAR(128))
 BEGIN
-	SELECT
-		s.title as `Title`,
-		s.`description` as `Description`,
-		s.`index` = 1 as `Index`,
-		s.`follow` = 1 as `Follow`
-	FROM pages AS p
-		INNER JOIN seo AS s ON s.id = p.seo_id
-	WHERE p.id = pageId;
+	SELECT COUNT(*) FROM `terms`
+	WHERE `type` = termType AND `slug` = termSlug AND `id` != excludeTermId;
 END ;;
 DELIMITER ;
 /*!50003 SET sql_mode              = @saved_sql_mode */ ;
 /*!50003 SET character_set_client  = @saved_cs_client */ ;
 /*!50003 SET character_set_results = @saved_cs_results */ ;
 /*!50003 SET collation_connection  = @saved_col_connection */ ;
-/*!50003 DROP PROCEDURE IF EXISTS `get_setting` */;
+/*!50003 DROP PROCEDURE IF EXISTS `count_users_by_email` */;
 /*!50003 SET @saved_cs_client      = @@character_set_client */ ;
 /*!50003 SET @saved_cs_results     = @@character_set_results */ ;
 /*!50003 SET @saved_col_connection = @@collation_connection */ ;
@@ -505,16 +539,16 @@ DELIMITER ;
 /*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
 /*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
 DELIMITER ;;
-CREATE DEFINER=`distro-user`@`%` PROCEDURE `get_setting`(IN settingKey VARCHAR(45))
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `count_users_by_email`(IN normalizedEmail VARCHAR(255), IN excludeId VARCHAR(128))
 BEGIN
-	SELECT `key`, `value` FROM `settings` WHERE `key` = settingKey;
+	SELECT COUNT(*) FROM `users` WHERE `normalized_email` = normalizedEmail AND `id` != excludeId;
 END ;;
 DELIMITER ;
 /*!50003 SET sql_mode              = @saved_sql_mode */ ;
 /*!50003 SET character_set_client  = @saved_cs_client */ ;
 /*!50003 SET character_set_results = @saved_cs_results */ ;
 /*!50003 SET collation_connection  = @saved_col_connection */ ;
-/*!50003 DROP PROCEDURE IF EXISTS `get_user` */;
+/*!50003 DROP PROCEDURE IF EXISTS `create_image` */;
 /*!50003 SET @saved_cs_client      = @@character_set_client */ ;
 /*!50003 SET @saved_cs_results     = @@character_set_results */ ;
 /*!50003 SET @saved_col_connection = @@collation_connection */ ;
@@ -524,28 +558,17 @@ DELIMITER ;
 /*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
 /*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
 DELIMITER ;;
-CREATE DEFINER=`distro-user`@`%` PROCEDURE `get_user`(IN userId VARCHAR(128))
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `create_image`(IN imageId VARCHAR(128), IN typeId VARCHAR(128))
 BEGIN
-	SELECT
-		id, first_name, last_name, email, normalized_email, password_hash
-	FROM users
-	WHERE id = userId;
-    
-    SELECT 
-		s.id AS `ScopeId`, s.name AS `ScopeName`
-	FROM
-		user_scopes AS us
-			INNER JOIN
-		scopes AS s ON s.id = us.scope_id
-	WHERE
-		us.user_id = userId;
+	INSERT INTO `images` (`id`, `type_id`, `alternative_text`) 
+		VALUES (imageId, typeId, NULL);
 END ;;
 DELIMITER ;
 /*!50003 SET sql_mode              = @saved_sql_mode */ ;
 /*!50003 SET character_set_client  = @saved_cs_client */ ;
 /*!50003 SET character_set_results = @saved_cs_results */ ;
 /*!50003 SET collation_connection  = @saved_col_connection */ ;
-/*!50003 DROP PROCEDURE IF EXISTS `get_user_audit` */;
+/*!50003 DROP PROCEDURE IF EXISTS `create_page` */;
 /*!50003 SET @saved_cs_client      = @@character_set_client */ ;
 /*!50003 SET @saved_cs_results     = @@character_set_results */ ;
 /*!50003 SET @saved_col_connection = @@collation_connection */ ;
@@ -555,28 +578,21 @@ DELIMITER ;
 /*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
 /*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
 DELIMITER ;;
-CREATE DEFINER=`distro-user`@`%` PROCEDURE `get_user_audit`(IN userId VARCHAR(128))
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `create_page`(IN pageId VARCHAR(128), IN pageTitle VARCHAR(255), IN pageDescription VARCHAR(255), IN pageContent TEXT,
+	IN contentFormat VARCHAR(16), IN contentSource TEXT, IN pageOutline JSON, IN wordCount INT, IN readingTime INT, IN pageExcerpt VARCHAR(255),
+	IN isPageBlog BIT, IN pageUrl VARCHAR(255), IN parentId VARCHAR(128), IN pagePath VARCHAR(255))
 BEGIN
-	SELECT 
-    p.id AS `UserId`,
-    CONCAT(p.first_name, ' ', p.last_name) AS `UserFullname`,
-    a.message AS `Message`,
-    a.`date` AS `Date`,
-    a.state AS `State`
-FROM
-    user_audit AS a
-        INNER JOIN
-    users AS p ON p.id = a.performed_by_id
-WHERE
-    a.user_id = userId
-Order by a.date desc;
+	INSERT INTO `pages` (`id`,`title`,`description`,`content`,`content_format`,`content_source`,`outline`,`word_count`,`reading_time`,`excerpt`,
+		`is_blog`,`is_active`, `url`, `parent_id`, `path`) 
+		VALUES (pageId, pageTitle, pageDescription, pageContent, contentFormat, contentSource, pageOutline, wordCount, readingTime, pageExcerpt,
+			isPageBlog, FALSE, pageUrl, parentId, pagePath);
 END ;;
 DELIMITER ;
 /*!50003 SET sql_mode              = @saved_sql_mode */ ;
 /*!50003 SET character_set_client  = @saved_cs_client */ ;
 /*!50003 SET character_set_results = @saved_cs_results */ ;
 /*!50003 SET collation_connection  = @saved_col_connection */ ;
-/*!50003 DROP PROCEDURE IF EXISTS `get_user_by_email` */;
+/*!50003 DROP PROCEDURE IF EXISTS `create_password_reset_token` */;
 /*!50003 SET @saved_cs_client      = @@character_set_client */ ;
 /*!50003 SET @saved_cs_results     = @@character_set_results */ ;
 /*!50003 SET @saved_col_connection = @@collation_connection */ ;
@@ -586,24 +602,20 @@ DELIMITER ;
 /*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
 /*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
 DELIMITER ;;
-CREATE DEFINER=`distro-user`@`%` PROCEDURE `get_user_by_email`(IN normalizedEmail VARCHAR(255))
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `create_password_reset_token`(IN tokenId VARCHAR(128), IN userId VARCHAR(128), IN tokenHash CHAR(64), IN expiresAt DATETIME)
 BEGIN
-	DECLARE userId VARCHAR(255);
-	SELECT 
-		`id`
-	INTO userId FROM
-		users
-	WHERE
-		normalized_email = normalizedEmail;
-    
-    CALL get_user(userId);
+	-- only the latest token can be used.
+	DELETE FROM password_reset_tokens WHERE user_id = userId AND used_at IS NULL;
+
+	INSERT INTO password_reset_tokens (id, user_id, token_hash, expires_at)
+	VALUES (tokenId, userId, tokenHash, expiresAt);
 END ;;
 DELIMITER ;
 /*!50003 SET sql_mode              = @saved_sql_mode */ ;
 /*!50003 SET character_set_client  = @saved_cs_client */ ;
 /*!50003 SET character_set_results = @saved_cs_results */ ;
 /*!50003 SET collation_connection  = @saved_col_connection */ ;
-/*!50003 DROP PROCEDURE IF EXISTS `update_page` */;
+/*!50003 DROP PROCEDURE IF EXISTS `create_redirect` */;
 /*!50003 SET @saved_cs_client      = @@character_set_client */ ;
 /*!50003 SET @saved_cs_results     = @@character_set_results */ ;
 /*!50003 SET @saved_col_connection = @@collation_connection */ ;
@@ -613,23 +625,27 @@ DELIMITER ;
 /*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
 /*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
 DELIMITER ;;
-CREATE DEFINER=`distro-user`@`%` PROCEDURE `update_page`(IN pageId VARCHAR(128), IN pageTitle VARCHAR(255), IN pageDescription VARCHAR(255), 
-IN pageContent TEXT, IN isPageActive BIT, IN imageId VARCHAR(128), IN pageUrl VARCHAR(255))
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `create_redirect`(IN redirectId VARCHAR(128), IN sourcePath VARCHAR(255), IN targetPath VARCHAR(255), IN statusCode SMALLINT, IN pageId VARCHAR(128))
 BEGIN
-	UPDATE `pages` SET `title` = pageTitle, 
-		`description` = pageDescription, 
-        `content` = pageContent,
-        `is_active` = isPageActive,
-        `image_id` = imageId,
-        `url` = pageUrl
-	WHERE `id` = pageId;
+	-- the target is now live, so it can no longer be redirected.
+	DELETE FROM `redirects` WHERE `source_path` = targetPath;
+
+	-- collapse any chains by pointing redirects to the source straight at the target.
+	UPDATE `redirects` SET `target_path` = targetPath WHERE `target_path` = sourcePath;
+
+	INSERT INTO `redirects` (`id`, `source_path`, `target_path`, `status_code`, `page_id`)
+	VALUES (redirectId, sourcePath, targetPath, statusCode, pageId)
+	ON DUPLICATE KEY UPDATE
+		`target_path` = targetPath,
+		`status_code` = statusCode,
+		`page_id` = pageId;
 END ;;
 DELIMITER ;
 /*!50003 SET sql_mode              = @saved_sql_mode */ ;
 /*!50003 SET character_set_client  = @saved_cs_client */ ;
 /*!50003 SET character_set_results = @saved_cs_results */ ;
 /*!50003 SET collation_connection  = @saved_col_connection */ ;
-/*!50003 DROP PROCEDURE IF EXISTS `update_page_seo` */;
+/*!50003 DROP PROCEDURE IF EXISTS `create_refresh_token` */;
 /*!50003 SET @saved_cs_client      = @@character_set_client */ ;
 /*!50003 SET @saved_cs_results     = @@character_set_results */ ;
 /*!50003 SET @saved_col_connection = @@collation_connection */ ;
@@ -639,31 +655,17 @@ DELIMITER ;
 /*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
 /*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
 DELIMITER ;;
-CREATE DEFINER=`distro-user`@`%` PROCEDURE `update_page_seo`(IN pageId VARCHAR(128), IN seoTitle VARCHAR(255), IN seoDescription VARCHAR(128),
-	IN seoIndex TINYINT(1), IN seoFollow TINYINT(1))
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `create_refresh_token`(IN tokenId VARCHAR(128), IN userId VARCHAR(128), IN familyId VARCHAR(128), IN tokenHash CHAR(64), IN mfaVerified TINYINT(1), IN expiresAt DATETIME)
 BEGIN
-	DECLARE seoId VARCHAR(128);
-    SELECT seo_id INTO seoId FROM pages WHERE id = pageId;
-    
-    IF seoId IS NULL THEN
-		SET seoId = UUID();
-    
-		INSERT INTO `seo` (`id`, `title`, `description`, `index`, `follow`)
-			VALUES (seoId, seoTitle, seoDescription, seoIndex, seoFollow);
-			
-		UPDATE `pages` SET `seo_id` = seoId WHERE `id` = pageId;
-	ELSE
-		UPDATE `seo` SET `title` = seoTitle, `description` = seoDescription, 
-			`index` = seoIndex, `follow` = seoFollow
-		WHERE id = seoId;
-	END IF;
+	INSERT INTO refresh_tokens (id, user_id, family_id, token_hash, mfa_verified, expires_at)
+	VALUES (tokenId, userId, familyId, tokenHash, mfaVerified, expiresAt);
 END ;;
 DELIMITER ;
 /*!50003 SET sql_mode              = @saved_sql_mode */ ;
 /*!50003 SET character_set_client  = @saved_cs_client */ ;
 /*!50003 SET character_set_results = @saved_cs_results */ ;
 /*!50003 SET collation_connection  = @saved_col_connection */ ;
-/*!50003 DROP PROCEDURE IF EXISTS `update_setting` */;
+/*!50003 DROP PROCEDURE IF EXISTS `create_series` */;
 /*!50003 SET @saved_cs_client      = @@character_set_client */ ;
 /*!50003 SET @saved_cs_results     = @@character_set_results */ ;
 /*!50003 SET @saved_col_connection = @@collation_connection */ ;
@@ -673,16 +675,16 @@ DELIMITER ;
 /*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
 /*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
 DELIMITER ;;
-CREATE DEFINER=`distro-user`@`%` PROCEDURE `update_setting`(IN settingKey VARCHAR(45), IN settingValue VARCHAR(255))
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `create_series`(IN seriesId VARCHAR(128), IN seriesTitle VARCHAR(255), IN seriesDescription VARCHAR(1000))
 BEGIN
-	UPDATE `settings` SET `value` = settingValue WHERE `key` = settingKey;
+	INSERT INTO `series` (`id`, `title`, `description`) VALUES (seriesId, seriesTitle, seriesDescription);
 END ;;
 DELIMITER ;
 /*!50003 SET sql_mode              = @saved_sql_mode */ ;
 /*!50003 SET character_set_client  = @saved_cs_client */ ;
 /*!50003 SET character_set_results = @saved_cs_results */ ;
 /*!50003 SET collation_connection  = @saved_col_connection */ ;
-/*!50003 DROP PROCEDURE IF EXISTS `update_user` */;
+/*!50003 DROP PROCEDURE IF EXISTS `create_term` */;
 /*!50003 SET @saved_cs_client      = @@character_set_client */ ;
 /*!50003 SET @saved_cs_results     = @@character_set_results */ ;
 /*!50003 SET @saved_col_connection = @@collation_connection */ ;
@@ -692,16 +694,1926 @@ DELIMITER ;
 /*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
 /*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
 DELIMITER ;;
-CREATE DEFINER=`distro-user`@`%` PROCEDURE `update_user`(IN userId VARCHAR(128), IN firstName VARCHAR(255), IN lastName VARCHAR(255), IN emailAddress VARCHAR(255), IN normalizedEmail VARCHAR(255), IN passwordHash TEXT)
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `create_term`(IN termId VARCHAR(128), IN termType VARCHAR(16), IN termName VARCHAR(255), IN termSlug VARCHAR(255))
 BEGIN
-	UPDATE `users` 
-    SET
-		`first_name` = firstName,
-        `last_name` = lastName,
-        `email` = emailAddress,
-        `normalized_email` = normalizedEmail,
-        `password_hash` = passwordHash
-	WHERE `id` = userId;
+	INSERT INTO `terms` (`id`, `type`, `name`, `slug`)
+		VALUES (termId, termType, termName, termSlug);
+END ;;
+DELIMITER ;
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `create_user` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_0900_ai_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'STRICT_TRANS_TABLES,NO_ENGINE_SUBSTITUTION' */ ;
+DELIMITER ;;
+CREATE DEFINER=`root`@`%` PROCEDURE `create_user`(IN id VARCHAR(128), IN firstName VARCHAR(45), IN lastName VARCHAR(45), IN email VARCHAR(255), IN normalizedEmail VARCHAR(255), IN passwordHash TEXT)
+BEGIN
+	INSERT INTO `users` (`id`, `first_name`, `last_name`, `email`, `normalized_email`, `password_hash`)
+		VALUES (id, firstName, lastName, email, normalizedEmail, passwordHash);
+END ;;
+DELIMITER ;
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `delete_image` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_0900_ai_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
+DELIMITER ;;
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `delete_image`(IN imageId VARCHAR(128))
+BEGIN
+	DELETE FROM images WHERE id = imageId;
+END ;;
+DELIMITER ;
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `delete_page_draft` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_0900_ai_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
+DELIMITER ;;
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `delete_page_draft`(IN pageId VARCHAR(128))
+BEGIN
+	DELETE FROM `page_drafts` WHERE `page_id` = pageId;
+END ;;
+DELIMITER ;
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `delete_user` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_0900_ai_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
+DELIMITER ;;
+CREATE DEFINER=`root`@`%` PROCEDURE `delete_user`(IN userId VARCHAR(128))
+BEGIN
+	DELETE FROM `users` WHERE `id` = userId;
+END ;;
+DELIMITER ;
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `get_author_posts` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_0900_ai_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
+DELIMITER ;;
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `get_author_posts`(IN userId VARCHAR(128), IN pageLimit INT, IN pageOffset INT)
+BEGIN
+	SELECT
+		p.id AS `Id`,
+		p.title AS `Title`,
+		p.`description` AS `Description`,
+		p.url AS `Url`,
+		p.image_id AS `ImageId`,
+		p.excerpt AS `Excerpt`,
+		IFNULL(p.published_at, p.created_at) AS `PublishedAt`,
+		p.updated_at AS `UpdatedAt`,
+		b.Authors AS `Authors`
+	FROM pages AS p
+		LEFT JOIN view_page_bylines AS b ON b.PageId = p.id
+	WHERE p.is_blog = b'1' AND p.is_active = b'1'
+		AND (EXISTS (SELECT 1 FROM page_authors AS pa WHERE pa.page_id = p.id AND pa.user_id = userId)
+			OR (NOT EXISTS (SELECT 1 FROM page_authors AS pa WHERE pa.page_id = p.id)
+				AND EXISTS (SELECT 1 FROM page_audit AS a WHERE a.page_id = p.id AND a.message = 'PAGE_CREATED' AND a.user_id = userId)))
+	ORDER BY `PublishedAt` DESC, p.id
+	LIMIT pageLimit OFFSET pageOffset;
+
+	SELECT COUNT(*) FROM pages AS p
+	WHERE p.is_blog = b'1' AND p.is_active = b'1'
+		AND (EXISTS (SELECT 1 FROM page_authors AS pa WHERE pa.page_id = p.id AND pa.user_id = userId)
+			OR (NOT EXISTS (SELECT 1 FROM page_authors AS pa WHERE pa.page_id = p.id)
+				AND EXISTS (SELECT 1 FROM page_audit AS a WHERE a.page_id = p.id AND a.message = 'PAGE_CREATED' AND a.user_id = userId)));
+END ;;
+DELIMITER ;
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `get_blogs_by_term` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_0900_ai_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
+DELIMITER ;;
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `get_blogs_by_term`(IN termType VARCHAR(16), IN termSlug VARCHAR(255))
+BEGIN
+	SELECT
+		p.id AS `Id`,
+		p.title AS `Title`,
+		p.`description` AS `Description`,
+		p.url AS `Url`,
+		p.image_id AS `ImageId`,
+		p.excerpt AS `Excerpt`,
+		IFNULL(p.published_at, p.created_at) AS `PublishedAt`,
+		p.updated_at AS `UpdatedAt`,
+		b.Authors AS `Authors`
+	FROM pages AS p
+		LEFT JOIN view_page_bylines AS b ON b.PageId = p.id
+		INNER JOIN page_terms AS pt ON pt.page_id = p.id
+		INNER JOIN terms AS t ON t.id = pt.term_id
+	WHERE t.`type` = termType AND t.slug = termSlug
+		AND p.is_blog = b'1' AND p.is_active = b'1'
+	ORDER BY `PublishedAt` DESC, p.id;
+END ;;
+DELIMITER ;
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `get_image` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_0900_ai_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
+DELIMITER ;;
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `get_image`(IN imageId VARCHAR(128))
+BEGIN
+	SELECT
+		id as `Id`,
+        type_id as `TypeId`,
+        alternative_text `AltText`
+	FROM images
+    WHERE id = imageId;
+END ;;
+DELIMITER ;
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `get_image_type` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_0900_ai_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
+DELIMITER ;;
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `get_image_type`(IN imageId VARCHAR(128))
+BEGIN
+	SELECT t.`name`
+    FROM images AS i
+		INNER JOIN image_types AS t ON t.id = i.type_id
+	WHERE i.id = imageId;
+END ;;
+DELIMITER ;
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `get_image_type_by_name` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_0900_ai_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
+DELIMITER ;;
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `get_image_type_by_name`(IN normalizedName VARCHAR(255))
+BEGIN
+	SELECT
+		id AS `Id`,
+		`name` AS `Name`
+	FROM `image_types`
+    WHERE `name` = normalizedName;
+END ;;
+DELIMITER ;
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `get_page` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_0900_ai_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
+DELIMITER ;;
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `get_page`(IN pageId VARCHAR(128))
+BEGIN
+	SELECT
+		id AS `Id`,
+        title as `Title`,
+        `description` as `Description`,
+        content as `Content`,
+        content_format as `ContentFormat`,
+        content_source as `ContentSource`,
+        outline as `Outline`,
+        word_count as `WordCount`,
+        reading_time as `ReadingTime`,
+        excerpt as `Excerpt`,
+        is_blog = b'1' as `IsBlog`,
+        is_active = b'1' as `IsActive`,
+        image_id as `ImageId`,
+        url as `Url`,
+        parent_id as `ParentId`,
+        `path` as `Path`,
+        publish_at as `PublishAt`,
+        unpublish_at as `UnpublishAt`,
+        published_at as `PublishedAt`,
+        deleted_at as `DeletedAt`
+	FROM `pages`
+    WHERE id = pageId;
+END ;;
+DELIMITER ;
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `get_page_audit` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_0900_ai_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
+DELIMITER ;;
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `get_page_audit`(IN pageId VARCHAR(128))
+BEGIN
+SELECT 
+    u.id AS `UserId`,
+    CONCAT(u.first_name, ' ', u.last_name) AS `UserFullname`,
+    a.message AS `Message`,
+    a.`date` AS `Date`
+FROM
+    page_audit AS a
+        INNER JOIN
+    users AS u ON u.id = a.user_id
+WHERE
+    a.page_id = pageId
+Order by a.date desc;
+END ;;
+DELIMITER ;
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `get_page_authors` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_0900_ai_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
+DELIMITER ;;
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `get_page_authors`(IN pageId VARCHAR(128))
+BEGIN
+	SELECT
+		u.id AS `Id`,
+		IFNULL(u.display_name, CONCAT(u.first_name, ' ', u.last_name)) AS `Name`
+	FROM page_authors AS pa
+		INNER JOIN users AS u ON u.id = pa.user_id
+	WHERE pa.page_id = pageId
+	ORDER BY pa.position, u.id;
+END ;;
+DELIMITER ;
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `get_page_data_by_url` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_0900_ai_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
+DELIMITER ;;
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `get_page_data_by_url`(IN pageUrl VARCHAR(255), IN pageLocales VARCHAR(255))
+BEGIN
+	DECLARE matchedPageId VARCHAR(128);
+	DECLARE matchedLocale VARCHAR(20);
+	DECLARE defaultLocale VARCHAR(20);
+
+	SELECT IFNULL((SELECT `value` FROM settings WHERE `key` = 'DEFAULT_LOCALE'), 'en') INTO defaultLocale;
+
+	SELECT p.id INTO matchedPageId FROM pages AS p
+	WHERE p.`path` = pageUrl AND p.is_active = b'1';
+
+	-- a translation's own url always serves that translation.
+	IF matchedPageId IS NULL THEN
+		SELECT tp.PageId, tp.Locale INTO matchedPageId, matchedLocale
+		FROM view_page_translation_paths AS tp
+			INNER JOIN pages AS p ON p.id = tp.PageId
+		WHERE tp.Path = pageUrl AND p.is_active = b'1'
+		LIMIT 1;
+	END IF;
+
+	-- otherwise, use the most preferred translation, unless the default locale is preferred over it.
+	IF matchedLocale IS NULL AND pageLocales IS NOT NULL THEN
+		SELECT t.locale INTO matchedLocale FROM page_translations AS t
+		WHERE t.page_id = matchedPageId AND FIND_IN_SET(t.locale, pageLocales) > 0
+			AND (FIND_IN_SET(defaultLocale, pageLocales) = 0
+				OR FIND_IN_SET(t.locale, pageLocales) < FIND_IN_SET(defaultLocale, pageLocales))
+		ORDER BY FIND_IN_SET(t.locale, pageLocales)
+		LIMIT 1;
+	END IF;
+
+	SELECT
+		p.id AS `Id`,
+		IFNULL(t.title, p.title) AS `Title`,
+		IFNULL(t.`description`, p.`description`) AS `Description`,
+		IF(t.content IS NULL, p.content, t.content) AS `Content`,
+		IF(t.content IS NULL, p.outline, t.outline) AS `Outline`,
+		IF(t.content IS NULL, p.word_count, t.word_count) AS `WordCount`,
+		IF(t.content IS NULL, p.reading_time, t.reading_time) AS `ReadingTime`,
+		IF(t.content IS NULL, p.excerpt, t.excerpt) AS `Excerpt`,
+		b.Authors AS `Authors`,
+		sr.series_id AS `SeriesId`,
+		sr.series_title AS `SeriesTitle`,
+		sr.position AS `SeriesPosition`,
+		sr.page_count AS `SeriesPageCount`,
+		sr.previous_title AS `SeriesPreviousTitle`,
+		sr.previous_path AS `SeriesPreviousPath`,
+		sr.next_title AS `SeriesNextTitle`,
+		sr.next_path AS `SeriesNextPath`,
+		p.is_blog = b'1' AS `IsBlog`,
+		p.image_id AS `ImageId`,
+		IFNULL(t.locale, defaultLocale) AS `Locale`,
+		CONCAT('/', IFNULL(tp.Path, p.`path`)) AS `Path`,
+		defaultLocale AS `DefaultLocale`,
+		CONCAT('/', p.`path`) AS `DefaultPath`,
+		(SELECT CONCAT('[', GROUP_CONCAT(JSON_OBJECT('hreflang', a.Locale, 'path', CONCAT('/', a.Path)) ORDER BY a.Locale), ']')
+			FROM view_page_translation_paths AS a WHERE a.PageId = p.id) AS `Alternates`,
+		REPLACE(REPLACE(IFNULL(tf.`value`, '{TITLE}'), '{TITLE}', COALESCE(t.seo_title, t.title, s.title, p.title)),
+			'{SITE_NAME}', IFNULL(sn.`value`, '')) AS `SeoTitle`,
+		COALESCE(t.seo_description, t.`description`, s.`description`, p.`description`) AS `SeoDescription`,
+		IFNULL(sn.`value`, '') AS `SiteName`,
+		IFNULL(s.`index`, 1) = 1 AS `Index`,
+		IFNULL(s.`follow`, 1) = 1 AS `Follow`
+	FROM pages AS p
+		LEFT JOIN page_translations AS t ON t.page_id = p.id AND t.locale = matchedLocale
+		LEFT JOIN view_page_translation_paths AS tp ON tp.PageId = t.page_id AND tp.Locale = t.locale
+		LEFT JOIN view_page_bylines AS b ON b.PageId = p.id
+		LEFT JOIN (
+			SELECT
+				sp.series_id,
+				sp.page_id,
+				se.title AS series_title,
+				ROW_NUMBER() OVER w AS position,
+				COUNT(*) OVER (PARTITION BY sp.series_id) AS page_count,
+				LAG(sa.title) OVER w AS previous_title,
+				LAG(CONCAT('/', sa.`path`)) OVER w AS previous_path,
+				LEAD(sa.title) OVER w AS next_title,
+				LEAD(CONCAT('/', sa.`path`)) OVER w AS next_path
+			FROM series_pages AS sp
+				INNER JOIN series AS se ON se.id = sp.series_id
+				INNER JOIN pages AS sa ON sa.id = sp.page_id AND sa.is_active = b'1'
+			WINDOW w AS (PARTITION BY sp.series_id ORDER BY sp.position)
+		) AS sr ON sr.page_id = p.id
+		LEFT JOIN seo AS s ON s.id = p.seo_id
+		LEFT JOIN settings AS sn ON sn.`key` = 'SITE_NAME'
+		LEFT JOIN settings AS tf ON tf.`key` = 'TITLE_FORMAT'
+	WHERE p.id = matchedPageId AND p.is_active = b'1';
+END ;;
+DELIMITER ;
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `get_page_draft` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_0900_ai_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
+DELIMITER ;;
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `get_page_draft`(IN pageId VARCHAR(128))
+BEGIN
+	SELECT
+		d.page_id AS `PageId`,
+		d.user_id AS `UserId`,
+		CONCAT(u.first_name, ' ', u.last_name) AS `UserFullname`,
+		d.`date` AS `Date`,
+		d.title AS `Title`,
+		d.`description` AS `Description`,
+		d.content AS `Content`,
+		d.content_format AS `ContentFormat`,
+		d.url AS `Url`,
+		d.seo_title AS `SeoTitle`,
+		d.seo_description AS `SeoDescription`,
+		d.seo_index = 1 AS `SeoIndex`,
+		d.seo_follow = 1 AS `SeoFollow`
+	FROM page_drafts AS d
+		LEFT JOIN users AS u ON u.id = d.user_id
+	WHERE d.page_id = pageId;
+END ;;
+DELIMITER ;
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `get_page_list` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_0900_ai_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
+DELIMITER ;;
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `get_page_list`(IN isPageBlog BIT, IN isPageActive BIT, IN searchTerm VARCHAR(255), IN dateFrom DATETIME, IN dateTo DATETIME,
+	IN sortBy VARCHAR(16), IN sortDesc BIT, IN pageLimit INT, IN pageOffset INT)
+BEGIN
+	SELECT
+		id AS `Id`,
+		title AS `Title`,
+		`description` AS `Description`,
+		url AS `Url`,
+		is_active = b'1' AS `IsActive`,
+		created_at AS `CreatedAt`,
+		updated_at AS `UpdatedAt`
+	FROM `pages`
+	WHERE is_blog = isPageBlog AND deleted_at IS NULL
+		AND (isPageActive IS NULL OR is_active = isPageActive)
+		AND (searchTerm IS NULL OR title LIKE CONCAT('%', searchTerm, '%') OR `description` LIKE CONCAT('%', searchTerm, '%'))
+		AND (dateFrom IS NULL OR created_at >= dateFrom)
+		AND (dateTo IS NULL OR created_at <= dateTo)
+	ORDER BY
+		CASE WHEN sortBy = 'title' AND sortDesc = b'0' THEN title END ASC,
+		CASE WHEN sortBy = 'title' AND sortDesc = b'1' THEN title END DESC,
+		CASE WHEN sortBy = 'created' AND sortDesc = b'0' THEN created_at END ASC,
+		CASE WHEN sortBy = 'created' AND sortDesc = b'1' THEN created_at END DESC,
+		CASE WHEN sortBy = 'updated' AND sortDesc = b'0' THEN updated_at END ASC,
+		CASE WHEN sortBy = 'updated' AND sortDesc = b'1' THEN updated_at END DESC,
+		id
+	LIMIT pageLimit OFFSET pageOffset;
+
+	SELECT COUNT(*)
+	FROM `pages`
+	WHERE is_blog = isPageBlog AND deleted_at IS NULL
+		AND (isPageActive IS NULL OR is_active = isPageActive)
+		AND (searchTerm IS NULL OR title LIKE CONCAT('%', searchTerm, '%') OR `description` LIKE CONCAT('%', searchTerm, '%'))
+		AND (dateFrom IS NULL OR created_at >= dateFrom)
+		AND (dateTo IS NULL OR created_at <= dateTo);
+END ;;
+DELIMITER ;
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `get_page_revision` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_0900_ai_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
+DELIMITER ;;
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `get_page_revision`(IN revisionId VARCHAR(128))
+BEGIN
+	SELECT
+		r.id AS `Id`,
+		r.page_id AS `PageId`,
+		r.`number` AS `Number`,
+		r.user_id AS `UserId`,
+		CONCAT(u.first_name, ' ', u.last_name) AS `UserFullname`,
+		r.`date` AS `Date`,
+		r.title AS `Title`,
+		r.`description` AS `Description`,
+		r.content AS `Content`,
+		r.content_format AS `ContentFormat`,
+		r.url AS `Url`,
+		r.is_draft = 1 AS `IsDraft`,
+		r.seo_title AS `SeoTitle`,
+		r.seo_description AS `SeoDescription`,
+		r.seo_index = 1 AS `SeoIndex`,
+		r.seo_follow = 1 AS `SeoFollow`
+	FROM page_revisions AS r
+		LEFT JOIN users AS u ON u.id = r.user_id
+	WHERE r.id = revisionId;
+END ;;
+DELIMITER ;
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `get_page_revisions` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_0900_ai_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
+DELIMITER ;;
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `get_page_revisions`(IN pageId VARCHAR(128))
+BEGIN
+	SELECT
+		r.id AS `Id`,
+		r.page_id AS `PageId`,
+		r.`number` AS `Number`,
+		r.user_id AS `UserId`,
+		CONCAT(u.first_name, ' ', u.last_name) AS `UserFullname`,
+		r.`date` AS `Date`,
+		r.title AS `Title`,
+		r.`description` AS `Description`,
+		r.url AS `Url`,
+		r.is_draft = 1 AS `IsDraft`
+	FROM page_revisions AS r
+		LEFT JOIN users AS u ON u.id = r.user_id
+	WHERE r.page_id = pageId
+	ORDER BY r.`number` DESC;
+END ;;
+DELIMITER ;
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `get_page_seo` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_0900_ai_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
+DELIMITER ;;
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `get_page_seo`(IN pageId VARCHAR(255))
+BEGIN
+	SELECT
+		s.title as `Title`,
+		s.`description` as `Description`,
+		s.`index` = 1 as `Index`,
+		s.`follow` = 1 as `Follow`
+	FROM pages AS p
+		INNER JOIN seo AS s ON s.id = p.seo_id
+	WHERE p.id = pageId;
+END ;;
+DELIMITER ;
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `get_page_series_id` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_0900_ai_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
+DELIMITER ;;
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `get_page_series_id`(IN pageId VARCHAR(128))
+BEGIN
+	SELECT `series_id` FROM `series_pages` WHERE `page_id` = pageId;
+END ;;
+DELIMITER ;
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `get_page_terms` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_0900_ai_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
+DELIMITER ;;
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `get_page_terms`(IN pageId VARCHAR(128))
+BEGIN
+	SELECT
+		t.id AS `Id`,
+		t.`type` AS `Type`,
+		t.`name` AS `Name`,
+		t.slug AS `Slug`,
+		(SELECT COUNT(*) FROM page_terms AS c WHERE c.term_id = t.id) AS `PageCount`
+	FROM page_terms AS pt
+		INNER JOIN terms AS t ON t.id = pt.term_id
+	WHERE pt.page_id = pageId
+	ORDER BY t.`type`, t.`name`;
+END ;;
+DELIMITER ;
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `get_page_translations` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_0900_ai_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
+DELIMITER ;;
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `get_page_translations`(IN pageId VARCHAR(128))
+BEGIN
+	SELECT
+		t.page_id,
+		t.locale,
+		t.title,
+		t.`description`,
+		t.content,
+		t.content_format,
+		t.content_source,
+		t.outline,
+		t.word_count,
+		t.reading_time,
+		t.excerpt,
+		t.url,
+		tp.Path,
+		t.seo_title,
+		t.seo_description
+	FROM page_translations AS t
+		INNER JOIN view_page_translation_paths AS tp ON tp.PageId = t.page_id AND tp.Locale = t.locale
+	WHERE t.page_id = pageId
+	ORDER BY t.locale;
+END ;;
+DELIMITER ;
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `get_password_reset_token` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_0900_ai_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
+DELIMITER ;;
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `get_password_reset_token`(IN tokenHash CHAR(64))
+BEGIN
+	SELECT t.id, t.user_id, t.token_hash, t.expires_at, t.used_at
+	FROM password_reset_tokens AS t
+	WHERE t.token_hash = tokenHash;
+END ;;
+DELIMITER ;
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `get_published_blogs` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_0900_ai_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
+DELIMITER ;;
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `get_published_blogs`(IN pageLimit INT, IN pageOffset INT)
+BEGIN
+	SELECT
+		p.id AS `Id`,
+		p.title AS `Title`,
+		p.`description` AS `Description`,
+		p.url AS `Url`,
+		p.image_id AS `ImageId`,
+		p.excerpt AS `Excerpt`,
+		IFNULL(p.published_at, p.created_at) AS `PublishedAt`,
+		p.updated_at AS `UpdatedAt`,
+		b.Authors AS `Authors`
+	FROM pages AS p
+		LEFT JOIN view_page_bylines AS b ON b.PageId = p.id
+	WHERE p.is_blog = b'1' AND p.is_active = b'1'
+	ORDER BY `PublishedAt` DESC, p.id
+	LIMIT pageLimit OFFSET pageOffset;
+
+	SELECT COUNT(*) FROM pages
+	WHERE is_blog = b'1' AND is_active = b'1';
+END ;;
+DELIMITER ;
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `get_redirect` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_0900_ai_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
+DELIMITER ;;
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `get_redirect`(IN redirectId VARCHAR(128))
+BEGIN
+	SELECT `id`, `source_path`, `target_path`, `status_code`, `page_id`, `created_at`
+	FROM `redirects`
+	WHERE `id` = redirectId;
+END ;;
+DELIMITER ;
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `get_redirect_by_source` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_0900_ai_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
+DELIMITER ;;
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `get_redirect_by_source`(IN sourcePath VARCHAR(255))
+BEGIN
+	SELECT `id`, `source_path`, `target_path`, `status_code`, `page_id`, `created_at`
+	FROM `redirects`
+	WHERE `source_path` = sourcePath;
+END ;;
+DELIMITER ;
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `get_redirects` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_0900_ai_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
+DELIMITER ;;
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `get_redirects`()
+BEGIN
+	SELECT `id`, `source_path`, `target_path`, `status_code`, `page_id`, `created_at`
+	FROM `redirects`
+	ORDER BY `source_path`;
+END ;;
+DELIMITER ;
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `get_refresh_token` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_0900_ai_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
+DELIMITER ;;
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `get_refresh_token`(IN tokenHash CHAR(64))
+BEGIN
+	SELECT
+		t.id,
+		t.user_id,
+		t.family_id,
+		t.token_hash,
+		t.mfa_verified,
+		t.expires_at,
+		t.used_at,
+		t.revoked_at
+	FROM refresh_tokens AS t
+	WHERE t.token_hash = tokenHash;
+END ;;
+DELIMITER ;
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `get_related_documents` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_0900_ai_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
+DELIMITER ;;
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `get_related_documents`()
+BEGIN
+	SELECT
+		p.id AS `Id`,
+		p.title AS `Title`,
+		p.`description` AS `Description`,
+		p.content AS `Content`
+	FROM pages AS p
+	WHERE p.is_blog = b'1' AND p.is_active = b'1';
+END ;;
+DELIMITER ;
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `get_related_posts` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_0900_ai_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
+DELIMITER ;;
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `get_related_posts`(IN pageId VARCHAR(128), IN pageLimit INT)
+BEGIN
+	SELECT
+		p.id AS `Id`,
+		p.title AS `Title`,
+		p.`description` AS `Description`,
+		p.url AS `Url`,
+		p.image_id AS `ImageId`,
+		p.excerpt AS `Excerpt`,
+		IFNULL(p.published_at, p.created_at) AS `PublishedAt`,
+		p.updated_at AS `UpdatedAt`,
+		b.Authors AS `Authors`
+	FROM related_posts AS r
+		INNER JOIN pages AS p ON p.id = r.related_page_id
+		LEFT JOIN view_page_bylines AS b ON b.PageId = p.id
+	WHERE r.page_id = pageId AND p.is_blog = b'1' AND p.is_active = b'1'
+	ORDER BY r.position
+	LIMIT pageLimit;
+END ;;
+DELIMITER ;
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `get_related_posts_refresh_needed` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_0900_ai_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
+DELIMITER ;;
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `get_related_posts_refresh_needed`()
+BEGIN
+	-- a refresh requested in the same second one started may not have been
+	-- included in it, so it's refreshed again, to be safe.
+	SELECT COUNT(*) FROM related_posts_refresh AS r
+	WHERE r.requested_at IS NOT NULL AND (r.refreshed_at IS NULL OR r.requested_at >= r.refreshed_at);
+END ;;
+DELIMITER ;
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `get_scheduled_page_ids` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_0900_ai_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
+DELIMITER ;;
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `get_scheduled_page_ids`(IN scheduleDate DATETIME)
+BEGIN
+	SELECT id AS `Id`
+	FROM `pages`
+    WHERE deleted_at IS NULL AND ((publish_at IS NOT NULL AND publish_at <= scheduleDate)
+		OR (unpublish_at IS NOT NULL AND unpublish_at <= scheduleDate))
+	ORDER BY LEAST(IFNULL(publish_at, unpublish_at), IFNULL(unpublish_at, publish_at));
+END ;;
+DELIMITER ;
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `get_series` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_0900_ai_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
+DELIMITER ;;
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `get_series`(IN seriesId VARCHAR(128))
+BEGIN
+	SELECT
+		s.id AS `Id`,
+		s.title AS `Title`,
+		s.`description` AS `Description`
+	FROM series AS s
+	WHERE s.id = seriesId;
+
+	SELECT
+		p.id AS `PageId`,
+		p.title AS `Title`,
+		CONCAT('/', p.`path`) AS `Path`,
+		p.is_active = b'1' AS `IsActive`
+	FROM series_pages AS sp
+		INNER JOIN pages AS p ON p.id = sp.page_id
+	WHERE sp.series_id = seriesId
+	ORDER BY sp.position;
+END ;;
+DELIMITER ;
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `get_series_list` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_0900_ai_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
+DELIMITER ;;
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `get_series_list`()
+BEGIN
+	SELECT
+		s.id AS `Id`,
+		s.title AS `Title`,
+		s.`description` AS `Description`,
+		COUNT(sp.page_id) AS `PageCount`
+	FROM series AS s
+		LEFT JOIN series_pages AS sp ON sp.series_id = s.id
+	GROUP BY s.id, s.title, s.`description`
+	ORDER BY s.title;
+END ;;
+DELIMITER ;
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `get_setting` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_0900_ai_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
+DELIMITER ;;
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `get_setting`(IN settingKey VARCHAR(45))
+BEGIN
+	SELECT `key`, `value` FROM `settings` WHERE `key` = settingKey;
+END ;;
+DELIMITER ;
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `get_sitemap_entries` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_0900_ai_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
+DELIMITER ;;
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `get_sitemap_entries`()
+BEGIN
+	SELECT
+		p.path AS `Path`,
+		p.updated_at AS `UpdatedAt`
+	FROM pages AS p
+		LEFT JOIN seo AS s ON s.id = p.seo_id
+	WHERE p.is_active = b'1' AND (s.id IS NULL OR s.`index` = 1)
+	ORDER BY p.path;
+END ;;
+DELIMITER ;
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `get_term` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_0900_ai_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
+DELIMITER ;;
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `get_term`(IN termId VARCHAR(128))
+BEGIN
+	SELECT
+		id AS `Id`,
+		`type` AS `Type`,
+		`name` AS `Name`,
+		slug AS `Slug`
+	FROM terms
+	WHERE id = termId;
+END ;;
+DELIMITER ;
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `get_terms` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_0900_ai_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
+DELIMITER ;;
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `get_terms`(IN termType VARCHAR(16))
+BEGIN
+	SELECT
+		t.id AS `Id`,
+		t.`type` AS `Type`,
+		t.`name` AS `Name`,
+		t.slug AS `Slug`,
+		COUNT(pt.page_id) AS `PageCount`
+	FROM terms AS t
+		LEFT JOIN page_terms AS pt ON pt.term_id = t.id
+	WHERE termType IS NULL OR t.`type` = termType
+	GROUP BY t.id, t.`type`, t.`name`, t.slug
+	ORDER BY t.`type`, t.`name`;
+END ;;
+DELIMITER ;
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `get_trashed_page_ids` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_0900_ai_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
+DELIMITER ;;
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `get_trashed_page_ids`(IN deletedBefore DATETIME)
+BEGIN
+	SELECT id AS `Id`
+	FROM `pages`
+    WHERE deleted_at IS NOT NULL AND deleted_at <= deletedBefore
+    ORDER BY deleted_at;
+END ;;
+DELIMITER ;
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `get_trashed_pages` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_0900_ai_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
+DELIMITER ;;
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `get_trashed_pages`()
+BEGIN
+	SELECT
+		id AS `Id`,
+        title AS `Title`,
+        `path` AS `Path`,
+        is_blog = b'1' AS `IsBlog`,
+        deleted_at AS `DeletedAt`
+	FROM `pages`
+    WHERE deleted_at IS NOT NULL
+    ORDER BY deleted_at DESC;
+END ;;
+DELIMITER ;
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `get_user` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_0900_ai_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
+DELIMITER ;;
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `get_user`(IN userId VARCHAR(128))
+BEGIN
+	SELECT
+		id, first_name, last_name, email, normalized_email, password_hash,
+		display_name, bio, avatar_image_id, social_links
+	FROM users
+	WHERE id = userId;
+    
+    SELECT 
+		s.id AS `ScopeId`, s.name AS `ScopeName`
+	FROM
+		user_scopes AS us
+			INNER JOIN
+		scopes AS s ON s.id = us.scope_id
+	WHERE
+		us.user_id = userId;
+END ;;
+DELIMITER ;
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `get_user_audit` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_0900_ai_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
+DELIMITER ;;
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `get_user_audit`(IN userId VARCHAR(128))
+BEGIN
+	SELECT 
+    p.id AS `UserId`,
+    CONCAT(p.first_name, ' ', p.last_name) AS `UserFullname`,
+    a.message AS `Message`,
+    a.`date` AS `Date`,
+    a.state AS `State`
+FROM
+    user_audit AS a
+        INNER JOIN
+    users AS p ON p.id = a.performed_by_id
+WHERE
+    a.user_id = userId
+Order by a.date desc;
+END ;;
+DELIMITER ;
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `get_user_by_email` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_0900_ai_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
+DELIMITER ;;
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `get_user_by_email`(IN normalizedEmail VARCHAR(255))
+BEGIN
+	DECLARE userId VARCHAR(255);
+	SELECT 
+		`id`
+	INTO userId FROM
+		users
+	WHERE
+		normalized_email = normalizedEmail;
+    
+    CALL get_user(userId);
+END ;;
+DELIMITER ;
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `get_user_mfa` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_0900_ai_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
+DELIMITER ;;
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `get_user_mfa`(IN userId VARCHAR(128))
+BEGIN
+	SELECT
+		m.user_id, m.secret, m.confirmed_at, m.last_used_step
+	FROM user_mfa AS m
+	WHERE m.user_id = userId;
+
+	SELECT
+		c.code_hash, c.used_at
+	FROM user_mfa_recovery_codes AS c
+	WHERE c.user_id = userId;
+END ;;
+DELIMITER ;
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `is_token_revoked` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_0900_ai_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
+DELIMITER ;;
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `is_token_revoked`(IN tokenId VARCHAR(128), IN userId VARCHAR(128), IN issuedAt DATETIME)
+BEGIN
+	SELECT
+		EXISTS (SELECT 1 FROM revoked_tokens AS r WHERE r.id = tokenId)
+		OR NOT EXISTS (SELECT 1 FROM users AS u WHERE u.id = userId)
+		OR EXISTS (SELECT 1 FROM users AS u WHERE u.id = userId AND u.tokens_revoked_at > issuedAt);
+END ;;
+DELIMITER ;
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `merge_terms` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_0900_ai_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
+DELIMITER ;;
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `merge_terms`(IN sourceTermId VARCHAR(128), IN targetTermId VARCHAR(128))
+BEGIN
+	INSERT IGNORE INTO `page_terms` (`page_id`, `term_id`)
+		SELECT `page_id`, targetTermId FROM `page_terms` WHERE `term_id` = sourceTermId;
+
+	DELETE FROM `terms` WHERE `id` = sourceTermId;
+END ;;
+DELIMITER ;
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `purge_page` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_0900_ai_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
+DELIMITER ;;
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `purge_page`(IN pageId VARCHAR(128), IN userId VARCHAR(128), IN purgeDate DATETIME)
+BEGIN
+	DECLARE seoId VARCHAR(128);
+	DECLARE pageTitle VARCHAR(255);
+
+	DECLARE EXIT HANDLER FOR SQLEXCEPTION
+	BEGIN
+		ROLLBACK;
+		RESIGNAL;
+	END;
+
+	SELECT seo_id, title INTO seoId, pageTitle FROM `pages` WHERE id = pageId AND deleted_at IS NOT NULL;
+
+	START TRANSACTION;
+
+	-- the audit history is kept, so the page's title is recorded against it
+	-- before the page_id is set to NULL by the delete.
+	IF pageTitle IS NOT NULL THEN
+		UPDATE `page_audit` AS a SET a.page_title = pageTitle WHERE a.page_id = pageId;
+		INSERT INTO `page_audit` (`id`, `page_id`, `page_title`, `user_id`, `date`, `message`)
+			VALUES (UUID(), pageId, pageTitle, userId, purgeDate, 'PAGE_PURGED');
+	END IF;
+
+	DELETE FROM `pages` WHERE id = pageId AND deleted_at IS NOT NULL;
+	DELETE FROM `seo` WHERE id = seoId;
+
+	COMMIT;
+END ;;
+DELIMITER ;
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `request_related_posts_refresh` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_0900_ai_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
+DELIMITER ;;
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `request_related_posts_refresh`(IN requestedAt DATETIME)
+BEGIN
+	INSERT INTO related_posts_refresh (id, requested_at) VALUES (1, requestedAt)
+	ON DUPLICATE KEY UPDATE requested_at = GREATEST(IFNULL(requested_at, requestedAt), requestedAt);
+END ;;
+DELIMITER ;
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `revoke_refresh_token_family` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_0900_ai_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
+DELIMITER ;;
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `revoke_refresh_token_family`(IN familyId VARCHAR(128), IN revokedAt DATETIME)
+BEGIN
+	UPDATE refresh_tokens AS t SET t.revoked_at = revokedAt
+	WHERE t.family_id = familyId AND t.revoked_at IS NULL;
+END ;;
+DELIMITER ;
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `revoke_token` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_0900_ai_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
+DELIMITER ;;
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `revoke_token`(IN tokenId VARCHAR(128), IN expiresAt DATETIME)
+BEGIN
+	-- tokens can't be used after they expire, so don't need to be kept.
+	DELETE FROM revoked_tokens WHERE expires_at < UTC_TIMESTAMP();
+
+	INSERT IGNORE INTO revoked_tokens (id, expires_at) VALUES (tokenId, expiresAt);
+END ;;
+DELIMITER ;
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `revoke_user_tokens` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_0900_ai_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
+DELIMITER ;;
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `revoke_user_tokens`(IN userId VARCHAR(128), IN revokedAt DATETIME)
+BEGIN
+	UPDATE users AS u SET u.tokens_revoked_at = revokedAt WHERE u.id = userId;
+
+	UPDATE refresh_tokens AS t SET t.revoked_at = revokedAt
+	WHERE t.user_id = userId AND t.revoked_at IS NULL;
+END ;;
+DELIMITER ;
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `rotate_refresh_token` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_0900_ai_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
+DELIMITER ;;
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `rotate_refresh_token`(IN usedId VARCHAR(128), IN usedAt DATETIME, IN tokenId VARCHAR(128), IN userId VARCHAR(128), IN familyId VARCHAR(128), IN tokenHash CHAR(64), IN mfaVerified TINYINT(1), IN expiresAt DATETIME)
+BEGIN
+	DECLARE rotated INT DEFAULT 0;
+
+	DECLARE EXIT HANDLER FOR SQLEXCEPTION
+	BEGIN
+		ROLLBACK;
+		RESIGNAL;
+	END;
+
+	START TRANSACTION;
+
+	-- the token is only replaced if it hasn't been used, or revoked, since it was read.
+	UPDATE refresh_tokens AS t SET t.used_at = usedAt
+	WHERE t.id = usedId AND t.used_at IS NULL AND t.revoked_at IS NULL;
+	SET rotated = ROW_COUNT();
+
+	IF rotated = 1 THEN
+		INSERT INTO refresh_tokens (id, user_id, family_id, token_hash, mfa_verified, expires_at)
+		VALUES (tokenId, userId, familyId, tokenHash, mfaVerified, expiresAt);
+	END IF;
+
+	COMMIT;
+
+	SELECT rotated;
+END ;;
+DELIMITER ;
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `save_page_draft` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_0900_ai_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
+DELIMITER ;;
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `save_page_draft`(IN pageId VARCHAR(128), IN userId VARCHAR(128), IN draftDate DATETIME,
+	IN pageTitle VARCHAR(255), IN pageDescription VARCHAR(255), IN pageContent TEXT, IN contentFormat VARCHAR(16), IN pageUrl VARCHAR(255),
+	IN seoTitle VARCHAR(255), IN seoDescription VARCHAR(255), IN seoIndex TINYINT(1), IN seoFollow TINYINT(1))
+BEGIN
+	REPLACE INTO `page_drafts` (`page_id`, `user_id`, `date`, `title`, `description`, `content`, `content_format`, `url`,
+		`seo_title`, `seo_description`, `seo_index`, `seo_follow`)
+		VALUES (pageId, userId, draftDate, pageTitle, pageDescription, pageContent, contentFormat, pageUrl,
+			seoTitle, seoDescription, seoIndex, seoFollow);
+END ;;
+DELIMITER ;
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `save_page_translation` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_0900_ai_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
+DELIMITER ;;
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `save_page_translation`(IN pageId VARCHAR(128), IN pageLocale VARCHAR(20), IN translationTitle VARCHAR(255), IN translationDescription VARCHAR(255), IN translationContent TEXT, IN translationContentFormat VARCHAR(16), IN translationContentSource TEXT, IN translationOutline JSON, IN translationWordCount INT, IN translationReadingTime INT, IN translationExcerpt VARCHAR(255), IN translationUrl VARCHAR(255), IN seoTitle VARCHAR(255), IN seoDescription VARCHAR(255))
+BEGIN
+	INSERT INTO page_translations (page_id, locale, title, `description`, content, content_format, content_source,
+		outline, word_count, reading_time, excerpt, url, seo_title, seo_description)
+	VALUES (pageId, pageLocale, translationTitle, translationDescription, translationContent, translationContentFormat, translationContentSource,
+		translationOutline, translationWordCount, translationReadingTime, translationExcerpt, translationUrl, seoTitle, seoDescription)
+	ON DUPLICATE KEY UPDATE
+		title = translationTitle,
+		`description` = translationDescription,
+		content = translationContent,
+		content_format = translationContentFormat,
+		content_source = translationContentSource,
+		outline = translationOutline,
+		word_count = translationWordCount,
+		reading_time = translationReadingTime,
+		excerpt = translationExcerpt,
+		url = translationUrl,
+		seo_title = seoTitle,
+		seo_description = seoDescription;
+END ;;
+DELIMITER ;
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `save_user_mfa` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_0900_ai_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
+DELIMITER ;;
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `save_user_mfa`(IN userId VARCHAR(128), IN mfaSecret VARCHAR(64), IN confirmedAt DATETIME, IN lastUsedStep BIGINT)
+BEGIN
+	INSERT INTO user_mfa (user_id, secret, confirmed_at, last_used_step)
+		VALUES (userId, mfaSecret, confirmedAt, lastUsedStep)
+	ON DUPLICATE KEY UPDATE
+		secret = mfaSecret,
+		confirmed_at = confirmedAt,
+		last_used_step = lastUsedStep,
+		failed_attempts = 0,
+		locked_until = NULL;
+
+	DELETE c FROM user_mfa_recovery_codes AS c WHERE c.user_id = userId;
+END ;;
+DELIMITER ;
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `search_pages` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_0900_ai_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
+DELIMITER ;;
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `search_pages`(IN searchQuery VARCHAR(255), IN includeInactive BIT, IN pageLimit INT, IN pageOffset INT)
+BEGIN
+	-- drafts are only searched when inactive pages are included, as they aren't live.
+	SELECT
+		p.id AS `Id`,
+		p.title AS `Title`,
+		p.`description` AS `Description`,
+		CONCAT('/', p.`path`) AS `Path`,
+		p.content AS `Content`,
+		p.is_blog = b'1' AS `IsBlog`,
+		p.is_active = b'1' AS `IsActive`,
+		MATCH(p.title) AGAINST(searchQuery IN NATURAL LANGUAGE MODE) * 2
+			+ MATCH(p.title, p.`description`, p.content) AGAINST(searchQuery IN NATURAL LANGUAGE MODE)
+			+ IFNULL(MATCH(d.title, d.`description`, d.content) AGAINST(searchQuery IN NATURAL LANGUAGE MODE), 0) AS `Score`
+	FROM pages AS p
+		LEFT JOIN page_drafts AS d ON d.page_id = p.id AND includeInactive = b'1'
+	WHERE p.deleted_at IS NULL AND (includeInactive = b'1' OR p.is_active = b'1')
+		AND (MATCH(p.title, p.`description`, p.content) AGAINST(searchQuery IN NATURAL LANGUAGE MODE)
+			OR MATCH(d.title, d.`description`, d.content) AGAINST(searchQuery IN NATURAL LANGUAGE MODE))
+	ORDER BY `Score` DESC, p.id
+	LIMIT pageLimit OFFSET pageOffset;
+
+	SELECT COUNT(*) FROM pages AS p
+		LEFT JOIN page_drafts AS d ON d.page_id = p.id AND includeInactive = b'1'
+	WHERE p.deleted_at IS NULL AND (includeInactive = b'1' OR p.is_active = b'1')
+		AND (MATCH(p.title, p.`description`, p.content) AGAINST(searchQuery IN NATURAL LANGUAGE MODE)
+			OR MATCH(d.title, d.`description`, d.content) AGAINST(searchQuery IN NATURAL LANGUAGE MODE));
+END ;;
+DELIMITER ;
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `set_related_posts_refreshed` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_0900_ai_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
+DELIMITER ;;
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `set_related_posts_refreshed`(IN refreshedAt DATETIME)
+BEGIN
+	INSERT INTO related_posts_refresh (id, refreshed_at) VALUES (1, refreshedAt)
+	ON DUPLICATE KEY UPDATE refreshed_at = refreshedAt;
+END ;;
+DELIMITER ;
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `update_page` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_0900_ai_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
+DELIMITER ;;
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `update_page`(IN pageId VARCHAR(128), IN pageTitle VARCHAR(255), IN pageDescription VARCHAR(255), 
+IN pageContent TEXT, IN contentFormat VARCHAR(16), IN contentSource TEXT,
+IN pageOutline JSON, IN wordCount INT, IN readingTime INT, IN pageExcerpt VARCHAR(255), IN isPageActive BIT, IN imageId VARCHAR(128), IN pageUrl VARCHAR(255),
+IN parentId VARCHAR(128), IN pagePath VARCHAR(255),
+IN publishAt DATETIME, IN unpublishAt DATETIME, IN publishedAt DATETIME, IN deletedAt DATETIME)
+BEGIN
+	UPDATE `pages` SET `title` = pageTitle, 
+		`description` = pageDescription, 
+        `content` = pageContent,
+        `content_format` = contentFormat,
+        `content_source` = contentSource,
+        `outline` = pageOutline,
+        `word_count` = wordCount,
+        `reading_time` = readingTime,
+        `excerpt` = pageExcerpt,
+        `is_active` = isPageActive,
+        `image_id` = imageId,
+        `url` = pageUrl,
+        `parent_id` = parentId,
+        `path` = pagePath,
+        `publish_at` = publishAt,
+        `unpublish_at` = unpublishAt,
+        `published_at` = publishedAt,
+        `deleted_at` = deletedAt
+	WHERE `id` = pageId;
+END ;;
+DELIMITER ;
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `update_page_paths` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_0900_ai_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
+DELIMITER ;;
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `update_page_paths`(IN pageId VARCHAR(128))
+BEGIN
+	DROP TEMPORARY TABLE IF EXISTS `tmp_page_paths`;
+
+	-- rebuild the path of each descendant from the page's, already updated, path.
+	CREATE TEMPORARY TABLE `tmp_page_paths` AS
+	WITH RECURSIVE tree (id, new_path) AS (
+		SELECT id, `path` FROM `pages` WHERE id = pageId
+		UNION ALL
+		SELECT c.id, CONCAT(t.new_path, '/', c.url)
+		FROM `pages` AS c
+			INNER JOIN tree AS t ON c.parent_id = t.id
+	)
+	SELECT t.id, p.`path` AS old_path, t.new_path, p.is_active = b'1' OR p.published_at IS NOT NULL AS is_published
+	FROM tree AS t
+		INNER JOIN `pages` AS p ON p.id = t.id
+	WHERE t.id != pageId AND p.`path` != t.new_path;
+
+	-- redirect the old paths of published descendants, collapsing any existing chains.
+	UPDATE `redirects` AS r
+		INNER JOIN `tmp_page_paths` AS t ON r.target_path = CONCAT('/', t.old_path)
+	SET r.target_path = CONCAT('/', t.new_path);
+
+	DELETE r FROM `redirects` AS r
+		INNER JOIN `tmp_page_paths` AS t ON r.source_path = CONCAT('/', t.new_path);
+
+	INSERT INTO `redirects` (`id`, `source_path`, `target_path`, `status_code`, `page_id`)
+	SELECT UUID(), CONCAT('/', t.old_path), CONCAT('/', t.new_path), 301, t.id
+	FROM `tmp_page_paths` AS t
+	WHERE t.is_published
+	ON DUPLICATE KEY UPDATE `target_path` = VALUES(`target_path`), `page_id` = VALUES(`page_id`);
+
+	UPDATE `pages` AS p
+		INNER JOIN `tmp_page_paths` AS t ON t.id = p.id
+	SET p.`path` = t.new_path;
+
+	DROP TEMPORARY TABLE `tmp_page_paths`;
+END ;;
+DELIMITER ;
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `update_page_seo` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_0900_ai_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
+DELIMITER ;;
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `update_page_seo`(IN pageId VARCHAR(128), IN seoTitle VARCHAR(255), IN seoDescription VARCHAR(128),
+	IN seoIndex TINYINT(1), IN seoFollow TINYINT(1))
+BEGIN
+	DECLARE seoId VARCHAR(128);
+    SELECT seo_id INTO seoId FROM pages WHERE id = pageId;
+    
+    IF seoId IS NULL THEN
+		SET seoId = UUID();
+    
+		INSERT INTO `seo` (`id`, `title`, `description`, `index`, `follow`)
+			VALUES (seoId, seoTitle, seoDescription, seoIndex, seoFollow);
+			
+		UPDATE `pages` SET `seo_id` = seoId WHERE `id` = pageId;
+	ELSE
+		UPDATE `seo` SET `title` = seoTitle, `description` = seoDescription, 
+			`index` = seoIndex, `follow` = seoFollow
+		WHERE id = seoId;
+	END IF;
+END ;;
+DELIMITER ;
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `update_redirect` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_0900_ai_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
+DELIMITER ;;
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `update_redirect`(IN redirectId VARCHAR(128), IN targetPath VARCHAR(255), IN statusCode SMALLINT)
+BEGIN
+	UPDATE `redirects` SET `target_path` = targetPath, `status_code` = statusCode
+	WHERE `id` = redirectId;
+END ;;
+DELIMITER ;
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `update_series` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_0900_ai_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
+DELIMITER ;;
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `update_series`(IN seriesId VARCHAR(128), IN seriesTitle VARCHAR(255), IN seriesDescription VARCHAR(1000))
+BEGIN
+	UPDATE `series`
+	SET
+		`title` = seriesTitle,
+		`description` = seriesDescription
+	WHERE `id` = seriesId;
+END ;;
+DELIMITER ;
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `update_setting` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_0900_ai_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
+DELIMITER ;;
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `update_setting`(IN settingKey VARCHAR(45), IN settingValue VARCHAR(255))
+BEGIN
+	UPDATE `settings` SET `value` = settingValue WHERE `key` = settingKey;
+END ;;
+DELIMITER ;
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `update_term` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_0900_ai_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
+DELIMITER ;;
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `update_term`(IN termId VARCHAR(128), IN termName VARCHAR(255), IN termSlug VARCHAR(255))
+BEGIN
+	UPDATE `terms` SET `name` = termName, `slug` = termSlug
+	WHERE `id` = termId;
+END ;;
+DELIMITER ;
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `update_user` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_0900_ai_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
+DELIMITER ;;
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `update_user`(IN userId VARCHAR(128), IN firstName VARCHAR(255), IN lastName VARCHAR(255), IN emailAddress VARCHAR(255), IN normalizedEmail VARCHAR(255), IN passwordHash TEXT, IN displayName VARCHAR(100), IN userBio TEXT, IN avatarImageId VARCHAR(128), IN socialLinks JSON)
+BEGIN
+	UPDATE `users` 
+    SET
+		`first_name` = firstName,
+        `last_name` = lastName,
+        `email` = emailAddress,
+        `normalized_email` = normalizedEmail,
+        `password_hash` = passwordHash,
+        `display_name` = displayName,
+        `bio` = userBio,
+        `avatar_image_id` = avatarImageId,
+        `social_links` = socialLinks
+	WHERE `id` = userId;
+END ;;
+DELIMITER ;
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `use_password_reset_token` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_0900_ai_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
+DELIMITER ;;
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `use_password_reset_token`(IN tokenId VARCHAR(128), IN usedAt DATETIME)
+BEGIN
+	UPDATE password_reset_tokens AS t SET t.used_at = usedAt
+	WHERE t.id = tokenId AND t.used_at IS NULL;
+
+	SELECT ROW_COUNT();
+END ;;
+DELIMITER ;
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `use_user_mfa_attempt` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_0900_ai_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'NO_ENGINE_SUBSTITUTION' */ ;
+DELIMITER ;;
+CREATE DEFINER=`distro-user`@`%` PROCEDURE `use_user_mfa_attempt`(IN userId VARCHAR(128), IN attemptAt DATETIME, IN maxAttempts INT, IN lockedUntil DATETIME)
+BEGIN
+	-- attempts are counted before the code is checked, so they can't be made in parallel
+	-- to get around the limit. Once a lockout has expired, the count starts again.
+	UPDATE user_mfa AS m SET
+		m.failed_attempts = IF(m.locked_until IS NULL, m.failed_attempts, 0) + 1,
+		m.locked_until = IF(m.failed_attempts >= maxAttempts, lockedUntil, NULL)
+	WHERE m.user_id = userId AND (m.locked_until IS NULL OR m.locked_until <= attemptAt);
+
+	SELECT ROW_COUNT();
 END ;;
 DELIMITER ;
 /*!50003 SET sql_mode              = @saved_sql_mode */ ;
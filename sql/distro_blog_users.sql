@@ -39,9 +39,16 @@ CREATE TABLE `users` (
   `email` varchar(255) NOT NULL,
   `normalized_email` varchar(255) NOT NULL,
   `password_hash` text NOT NULL,
+  `display_name` varchar(100) DEFAULT NULL,
+  `bio` text,
+  `avatar_image_id` varchar(128) DEFAULT NULL,
+  `social_links` json DEFAULT NULL,
+  `tokens_revoked_at` datetime DEFAULT NULL,
   PRIMARY KEY (`id`),
   UNIQUE KEY `email_UNIQUE` (`email`),
-  UNIQUE KEY `normalized_email_UNIQUE` (`normalized_email`)
+  UNIQUE KEY `normalized_email_UNIQUE` (`normalized_email`),
+  KEY `fk_user_avatar_image_idx` (`avatar_image_id`),
+  CONSTRAINT `fk_user_avatar_image` FOREIGN KEY (`avatar_image_id`) REFERENCES `images` (`id`) ON DELETE SET NULL
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
 /*!40101 SET character_set_client = @saved_cs_client */;
 SET @@SESSION.SQL_LOG_BIN = @MYSQLDUMP_TEMP_LOG_BIN;
@@ -55,4 +62,4 @@ SET @@SESSION.SQL_LOG_BIN = @MYSQLDUMP_TEMP_LOG_BIN;
 /*!40101 SET COLLATION_CONNECTION=@OLD_COLLATION_CONNECTION */;
 /*!40111 SET SQL_NOTES=@OLD_SQL_NOTES */;
 
--- Dump completed on 2020-07-19 20:32:58
+-- Dump completed on 2026-10-18 13:21:09
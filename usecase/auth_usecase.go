@@ -10,37 +10,82 @@ import (
 	"github.com/reecerussell/distro-blog/domain/dto"
 	"github.com/reecerussell/distro-blog/domain/model"
 	"github.com/reecerussell/distro-blog/domain/repository"
+	"github.com/reecerussell/distro-blog/libraries/contextkey"
 	"github.com/reecerussell/distro-blog/libraries/logging"
 	"github.com/reecerussell/distro-blog/libraries/result"
 	"github.com/reecerussell/distro-blog/password"
 )
 
+// AccessTokenLifetime is how long an access token can be used for, before
+// a new one must be requested, using a refresh token.
+const AccessTokenLifetime = time.Hour
+
+// MFAChallengeLifetime is how long a user has to enter a code from their
+// authenticator app, after signing in with their password.
+const MFAChallengeLifetime = time.Minute * 5
+
 // AuthUsecase is a high-level interface for handling the generation
 // and verification of access tokens.
 type AuthUsecase interface {
 	Token(ctx context.Context, cred *dto.UserCredential) result.Result
+	VerifyMFA(ctx context.Context, d *dto.VerifyMFA) result.Result
+	Refresh(ctx context.Context, d *dto.RefreshToken) result.Result
+	Revoke(ctx context.Context, d *dto.RevokeToken) result.Result
+	SignOutEverywhere(ctx context.Context, userID string) result.Result
 	Verify(ctx context.Context, tokenData []byte) result.Result
 	VerifyWithScopes(ctx context.Context, tokenData []byte, scopes ...string) result.Result
+	KeySet(ctx context.Context) result.Result
 }
 
 type authUsecase struct {
 	repo repository.UserRepository
+	tokens repository.TokenRepository
+	mfa repository.MFARepository
+	settings repository.SettingRepository
 	pwd password.Service
 	auth *auth.Service
 }
 
-// NewAuthUsecase returns a new instance of AuthUsecase with the given repo.
-func NewAuthUsecase(repo repository.UserRepository) AuthUsecase {
+// NewAuthUsecase returns a new instance of AuthUsecase with the given repos, which
+// signs tokens using the signer. Tokens are checked against the token repository,
+// to see if they've been revoked. The settings are used to determine which scopes
+// require two-factor authentication.
+func NewAuthUsecase(repo repository.UserRepository, tokens repository.TokenRepository, mfa repository.MFARepository, settings repository.SettingRepository, signer auth.Signer) AuthUsecase {
+	var revocations auth.RevocationStore
+	if tokens != nil {
+		revocations = &revocationStore{tokens: tokens}
+	}
+
 	return &authUsecase{
 		repo: repo,
+		tokens: tokens,
+		mfa: mfa,
+		settings: settings,
 		pwd: password.New(),
-		auth: auth.New(),
+		auth: auth.New(signer, revocations),
+	}
+}
+
+// revocationStore implements auth.RevocationStore using a TokenRepository.
+type revocationStore struct {
+	tokens repository.TokenRepository
+}
+
+func (s *revocationStore) IsRevoked(ctx context.Context, tokenID, userID string, issuedAt time.Time) (bool, error) {
+	success, _, value, err := s.tokens.IsRevoked(ctx, tokenID, userID, issuedAt).Deconstruct()
+	if !success {
+		return false, err
 	}
+
+	return value.(bool), nil
 }
 
 // Token generates a new access token for the user with the
 // given credentials. If the credentials are invalid a failed result
 // will be returned, with a bad request status code.
+//
+// If the user has two-factor authentication enabled, a *dto.MFAChallenge
+// is returned instead, which must be completed using VerifyMFA.
 func (u *authUsecase) Token(ctx context.Context, cred *dto.UserCredential) result.Result {
 	defaultErr := "Email and/or password is incorrect."
 
@@ -59,23 +104,213 @@ func (u *authUsecase) Token(ctx context.Context, cred *dto.UserCredential) resul
 		return result.Failure(defaultErr).WithStatusCode(http.StatusBadRequest)
 	}
 
+	mfaEnabled, res := u.mfaEnabled(ctx, user.ID())
+	if !res.IsOk() {
+		return res
+	}
+
+	if mfaEnabled {
+		return u.challenge(ctx, user)
+	}
+
+	return u.signIn(ctx, user, false)
+}
+
+// mfaEnabled determines whether the user has two-factor authentication enabled.
+func (u *authUsecase) mfaEnabled(ctx context.Context, userID string) (bool, result.Result) {
+	success, status, value, err := u.mfa.Get(ctx, userID).Deconstruct()
+	if !success {
+		if status == http.StatusNotFound {
+			return false, result.Ok()
+		}
+
+		return false, result.Failure(err).WithStatusCode(status)
+	}
+
+	return value.(*model.MFA).IsEnabled(), result.Ok()
+}
+
+// mfaAudience returns the audience of MFA challenge tokens, which is different to
+// that of access tokens, so they can't be used in place of one.
+func (u *authUsecase) mfaAudience() string {
+	return u.auth.Audience() + "/mfa"
+}
+
+// challenge returns a short-lived MFA token for the user, which can be exchanged
+// for an access token, along with a code from their authenticator app.
+func (u *authUsecase) challenge(ctx context.Context, user *model.User) result.Result {
+	now := time.Now().UTC()
+	exp := now.Add(MFAChallengeLifetime)
+
+	t := u.auth.NewToken(ctx).
+		SetSubject(user.ID()).
+		SetAudience(u.mfaAudience()).
+		SetNotBefore(now).
+		SetIssuedAt(now).
+		SetExpiry(exp).
+		AddClaim(auth.ClaimTypeUserId, user.ID()).
+		Build()
+	if t == nil {
+		errMsg := "Oops, something went wrong when signing you in :/"
+		return result.Failure(errMsg)
+	}
+
+	return result.Ok().WithValue(&dto.MFAChallenge{
+		MFARequired: true,
+		MFAToken: t.String(),
+		Expires: exp.Unix(),
+	})
+}
+
+// VerifyMFA completes signing in, for users with two-factor authentication enabled,
+// exchanging an MFA token and a code from their authenticator app, or a recovery
+// code, for an access token. Each MFA token can only be used once, and once too many
+// invalid codes have been entered, the user's authenticator is locked for a while.
+func (u *authUsecase) VerifyMFA(ctx context.Context, d *dto.VerifyMFA) result.Result {
+	invalidErr := result.Failure("The code is invalid, or signing in has expired.").WithStatusCode(http.StatusUnauthorized)
+
+	// MFA tokens are JWTs, which have three parts.
+	t := auth.Token(d.MFAToken)
+	if strings.Count(d.MFAToken, ".") != 2 || !u.auth.VerifyTokenForAudience(ctx, t, u.mfaAudience()) {
+		return invalidErr
+	}
+
+	id, exp := t.StringValue(auth.ClaimTypeTokenID), t.Number(auth.ClaimTypeExpiry)
+	if id == "" || exp == nil {
+		return invalidErr
+	}
+
+	// revoke the MFA token before checking the code, so that each password
+	// sign in only allows one guess.
+	success, status, _, err := u.tokens.RevokeToken(ctx, id, time.Unix(int64(*exp), 0).UTC()).Deconstruct()
+	if !success {
+		return result.Failure(err).WithStatusCode(status)
+	}
+
+	userID := t.StringValue(auth.ClaimTypeSubject)
+	success, status, value, err := u.mfa.Get(ctx, userID).Deconstruct()
+	if !success {
+		if status == http.StatusNotFound {
+			return invalidErr
+		}
+
+		return result.Failure(err).WithStatusCode(status)
+	}
+
+	now := time.Now().UTC()
+	res := attemptMFA(ctx, u.mfa, userID, now)
+	if !res.IsOk() {
+		return res
+	}
+
+	mfa := value.(*model.MFA)
+	err = mfa.Verify(d.Code, now)
+	if err != nil {
+		logging.Warningf("Failed two-factor authentication attempt for user '%s': %v\n", userID, err)
+		return invalidErr
+	}
+
+	// the used code is saved, so it can't be used again.
+	success, status, _, err = u.mfa.Save(ctx, mfa).Deconstruct()
+	if !success {
+		return result.Failure(err).WithStatusCode(status)
+	}
+
+	success, status, value, err = u.repo.Get(ctx, userID).Deconstruct()
+	if !success {
+		if status == http.StatusNotFound {
+			return invalidErr
+		}
+
+		return result.Failure(err).WithStatusCode(status)
+	}
+
+	return u.signIn(ctx, value.(*model.User), true)
+}
+
+// signIn creates a new refresh token for the user, and issues an access token. The
+// refresh token records whether the user signed in using two-factor authentication.
+func (u *authUsecase) signIn(ctx context.Context, user *model.User, mfaVerified bool) result.Result {
+	now := time.Now().UTC()
+	rt, refreshToken, err := model.NewRefreshToken(user.ID(), mfaVerified, now)
+	if err != nil {
+		logging.Error(err)
+		return result.Failure(err)
+	}
+
+	success, status, _, err := u.tokens.CreateRefreshToken(ctx, rt).Deconstruct()
+	if !success {
+		return result.Failure(err).WithStatusCode(status)
+	}
+
+	return u.issue(ctx, user, now, refreshToken, rt)
+}
+
+// mfaRequiredScopes returns the scopes which users must have two-factor
+// authentication enabled to be given, if any.
+func (u *authUsecase) mfaRequiredScopes(ctx context.Context) (map[string]bool, result.Result) {
+	success, status, value, err := u.settings.Get(ctx, model.SettingMFARequiredScopes).Deconstruct()
+	if !success {
+		if status == http.StatusNotFound {
+			return nil, result.Ok()
+		}
+
+		return nil, result.Failure(err).WithStatusCode(status)
+	}
+
+	required := make(map[string]bool)
+	for _, s := range value.(*model.Setting).MFARequiredScopes() {
+		required[s] = true
+	}
+
+	return required, result.Ok()
+}
+
+// issue generates a new access token for the user, returning it
+// as an *auth.AccessToken, along with the given refresh token.
+//
+// If the refresh token's family wasn't started using two-factor authentication, any
+// of the user's scopes which require it are replaced with the "mfa:enroll" scope.
+func (u *authUsecase) issue(ctx context.Context, user *model.User, now time.Time, refreshToken string, rt *model.RefreshToken) result.Result {
+	mfaVerified := rt.MFAVerified()
+	required, res := u.mfaRequiredScopes(ctx)
+	if !res.IsOk() {
+		return res
+	}
+
 	scopes := user.Scopes()
-	scopeNames := make([]string, len(scopes))
+	scopeNames := make([]string, 0, len(scopes))
+	enrollmentRequired := false
+
+	for _, s := range scopes {
+		if !mfaVerified && required[s.Name()] {
+			enrollmentRequired = true
+			continue
+		}
+
+		scopeNames = append(scopeNames, s.Name())
+	}
 
-	for i, s := range scopes {
-		scopeNames[i] = s.Name()
+	if enrollmentRequired {
+		scopeNames = append(scopeNames, auth.ScopeMFAEnroll)
+	}
+
+	authMethods := []string{"pwd"}
+	if mfaVerified {
+		authMethods = append(authMethods, "otp")
 	}
 
 	claims := map[string]interface{}{
 		auth.ClaimTypeEmail: user.NormalizedEmail(),
 		auth.ClaimTypeUserId: user.ID(),
 		auth.ClaimTypeScopes: scopeNames,
+		auth.ClaimTypeAuthMethods: authMethods,
 	}
 
-	now := time.Now().UTC()
-	exp := now.Add(time.Second * 3600)
+	exp := now.Add(AccessTokenLifetime)
 
 	t := u.auth.NewToken(ctx).
+		SetSubject(user.ID()).
 		SetNotBefore(now).
 		SetIssuedAt(now).
 		SetExpiry(exp).
@@ -87,9 +322,131 @@ func (u *authUsecase) Token(ctx context.Context, cred *dto.UserCredential) resul
 	}
 
 	ac := auth.NewAccessToken(t, exp)
+	ac.RefreshToken = refreshToken
+	ac.RefreshTokenExpires = rt.ExpiresAt().Unix()
+	ac.MFAEnrollmentRequired = enrollmentRequired
+
 	return result.Ok().WithValue(ac)
 }
 
+// Refresh exchanges a refresh token for a new access token and refresh token. Each refresh
+// token can only be used once; if one is used again, it's likely to have been stolen, so
+// all of the tokens which have replaced it since the user signed in are revoked.
+func (u *authUsecase) Refresh(ctx context.Context, d *dto.RefreshToken) result.Result {
+	invalidErr := result.Failure("The refresh token is invalid or has expired.").WithStatusCode(http.StatusUnauthorized)
+
+	success, status, value, err := u.tokens.GetRefreshToken(ctx, model.HashRefreshToken(d.RefreshToken)).Deconstruct()
+	if !success {
+		if status == http.StatusNotFound {
+			return invalidErr
+		}
+
+		return result.Failure(err).WithStatusCode(status)
+	}
+
+	rt := value.(*model.RefreshToken)
+	now := time.Now().UTC()
+	next, refreshToken, err := rt.Rotate(now)
+	switch err {
+	case nil:
+		break
+	case model.ErrRefreshTokenReused:
+		return u.revokeFamily(ctx, rt, invalidErr)
+	case model.ErrRefreshTokenExpired:
+		return invalidErr
+	default:
+		logging.Error(err)
+		return result.Failure(err)
+	}
+
+	success, status, _, err = u.tokens.RotateRefreshToken(ctx, rt, next).Deconstruct()
+	if !success {
+		// the token was used by another request, since it was read.
+		if status == http.StatusConflict {
+			return u.revokeFamily(ctx, rt, invalidErr)
+		}
+
+		return result.Failure(err).WithStatusCode(status)
+	}
+
+	success, status, value, err = u.repo.Get(ctx, rt.UserID()).Deconstruct()
+	if !success {
+		if status == http.StatusNotFound {
+			return invalidErr
+		}
+
+		return result.Failure(err).WithStatusCode(status)
+	}
+
+	return u.issue(ctx, value.(*model.User), now, refreshToken, next)
+}
+
+// revokeFamily revokes all of the refresh tokens in the same family as
+// the reused token, returning res if they were revoked successfully.
+func (u *authUsecase) revokeFamily(ctx context.Context, rt *model.RefreshToken, res result.Result) result.Result {
+	logging.Warningf("Refresh token reused for user '%s', revoking family '%s'.\n", rt.UserID(), rt.FamilyID())
+
+	success, status, _, err := u.tokens.RevokeRefreshTokenFamily(ctx, rt.FamilyID()).Deconstruct()
+	if !success {
+		return result.Failure(err).WithStatusCode(status)
+	}
+
+	return res
+}
+
+// Revoke revokes an access token, until it expires, or a refresh token, along with the
+// tokens which have replaced it. Tokens which are invalid, or have already been revoked,
+// are ignored, so the result is successful unless the tokens couldn't be revoked.
+func (u *authUsecase) Revoke(ctx context.Context, d *dto.RevokeToken) result.Result {
+	// access tokens are JWTs, which have three parts.
+	if strings.Count(d.Token, ".") == 2 {
+		t := auth.Token(d.Token)
+		if !u.auth.VerifyToken(ctx, t) {
+			return result.Ok()
+		}
+
+		id, exp := t.StringValue(auth.ClaimTypeTokenID), t.Number(auth.ClaimTypeExpiry)
+		if id == "" || exp == nil {
+			return result.Ok()
+		}
+
+		return u.tokens.RevokeToken(ctx, id, time.Unix(int64(*exp), 0).UTC())
+	}
+
+	success, status, value, err := u.tokens.GetRefreshToken(ctx, model.HashRefreshToken(d.Token)).Deconstruct()
+	if !success {
+		if status == http.StatusNotFound {
+			return result.Ok()
+		}
+
+		return result.Failure(err).WithStatusCode(status)
+	}
+
+	return u.tokens.RevokeRefreshTokenFamily(ctx, value.(*model.RefreshToken).FamilyID())
+}
+
+// SignOutEverywhere revokes all of the user's refresh tokens and access tokens, so
+// they must sign in again on every device. If the id is empty, the current user is
+// signed out everywhere.
+func (u *authUsecase) SignOutEverywhere(ctx context.Context, userID string) result.Result {
+	if userID == "" {
+		uid := ctx.Value(contextkey.ContextKey("user_id"))
+		if uid == nil {
+			return result.Failure("User must be logged in to sign out.").
+				WithStatusCode(http.StatusUnauthorized)
+		}
+
+		userID = uid.(string)
+	}
+
+	success, status, _, err := u.repo.Get(ctx, userID).Deconstruct()
+	if !success {
+		return result.Failure(err).WithStatusCode(status)
+	}
+
+	return u.tokens.RevokeUserTokens(ctx, userID)
+}
+
 // Verify verifies the given access token.
 func (u *authUsecase) Verify(ctx context.Context, tokenData []byte) result.Result {
 	ok := u.auth.VerifyToken(ctx, tokenData)
@@ -130,4 +487,16 @@ func (u *authUsecase) VerifyWithScopes(ctx context.Context, tokenData []byte, sc
 
 	return result.Failure("You're not allowed to access this resource :(").
 		WithStatusCode(http.StatusForbidden)
-}
\ No newline at end of file
+}
+
+// KeySet returns a result with an *auth.JSONWebKeySet value, containing
+// the public keys which can be used to verify access tokens.
+func (u *authUsecase) KeySet(ctx context.Context) result.Result {
+	set, err := u.auth.KeySet(ctx)
+	if err != nil {
+		logging.Errorf("failed to read the key set: %v\n", err)
+		return result.Failure("An error occurred while reading the signing keys.")
+	}
+
+	return result.Ok().WithValue(set)
+}
@@ -18,12 +18,19 @@ import (
 var(
 	testConnStringEmptySchema = os.Getenv("CONN_STRING_EMPTY_SCHEMA")
 	testAuthUsecase AuthUsecase
+	testSigner auth.Signer
 )
 
 func init() {
 	db := database.NewMySQL(testConnString)
 	repo := persistence.NewUserRepository(db)
-	testAuthUsecase = NewAuthUsecase(repo)
+	signer, err := auth.NewSignerFromEnv()
+	if err != nil {
+		panic(err)
+	}
+
+	testSigner = signer
+	testAuthUsecase = NewAuthUsecase(repo, persistence.NewTokenRepository(db), persistence.NewMFARepository(db), persistence.NewSettingRepository(db), signer)
 }
 
 func TestAuthUsecase_Token(t *testing.T) {
@@ -56,7 +63,7 @@ func TestAuthUsecase_Token(t *testing.T) {
 	t.Run("Repository Failure", func(t *testing.T) {
 		db := database.NewMySQL(testConnStringEmptySchema)
 		repo := persistence.NewUserRepository(db)
-		auth := NewAuthUsecase(repo)
+		auth := NewAuthUsecase(repo, persistence.NewTokenRepository(db), persistence.NewMFARepository(db), persistence.NewSettingRepository(db), testSigner)
 		res := auth.Token(ctx, d)
 		if res.IsOk() {
 			t.Errorf("expected to fail")
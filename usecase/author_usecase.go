@@ -0,0 +1,149 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/reecerussell/distro-blog/domain/dto"
+	"github.com/reecerussell/distro-blog/domain/model"
+	"github.com/reecerussell/distro-blog/domain/repository"
+	"github.com/reecerussell/distro-blog/libraries/result"
+)
+
+// MaxPageAuthors is the maximum number of authors a page can have.
+const MaxPageAuthors = 10
+
+// AuthorUsecase is used to manage the public profiles of users, the
+// authors of pages, and to list the posts written by an author.
+type AuthorUsecase interface {
+	Get(ctx context.Context, id string) result.Result
+	ListPosts(ctx context.Context, id string, q *dto.PagingQuery) result.Result
+	UpdateProfile(ctx context.Context, d *dto.UpdateUserProfile) result.Result
+	GetPageAuthors(ctx context.Context, pageID string) result.Result
+	SetPageAuthors(ctx context.Context, pageID string, d *dto.PageAuthors) result.Result
+}
+
+type authorUsecase struct {
+	repo repository.AuthorRepository
+	users repository.UserRepository
+	pages repository.PageRepository
+	images repository.ImageRepository
+}
+
+func NewAuthorUsecase(repo repository.AuthorRepository, users repository.UserRepository, pages repository.PageRepository, images repository.ImageRepository) AuthorUsecase {
+	return &authorUsecase{
+		repo: repo,
+		users: users,
+		pages: pages,
+		images: images,
+	}
+}
+
+// Get returns the public profile of the user with the given id, as a *dto.Author.
+func (u *authorUsecase) Get(ctx context.Context, id string) result.Result {
+	user, res := u.get(ctx, id)
+	if !res.IsOk() {
+		return res
+	}
+
+	return result.Ok().WithValue(user.Author())
+}
+
+// ListPosts returns a page of the active blogs written by the user, with the newest first.
+func (u *authorUsecase) ListPosts(ctx context.Context, id string, q *dto.PagingQuery) result.Result {
+	limit, offset, err := normalizePaging(q.Limit, q.Offset, q.Cursor)
+	if err != nil {
+		return result.Failure(err).WithStatusCode(http.StatusBadRequest)
+	}
+
+	_, res := u.get(ctx, id)
+	if !res.IsOk() {
+		return res
+	}
+
+	return withNextCursor(u.repo.ListPosts(ctx, id, limit, offset))
+}
+
+// UpdateProfile updates the public profile of a user. The avatar must
+// be the id of an image which has already been uploaded.
+func (u *authorUsecase) UpdateProfile(ctx context.Context, d *dto.UpdateUserProfile) result.Result {
+	user, res := u.get(ctx, d.ID)
+	if !res.IsOk() {
+		return res
+	}
+
+	if d.AvatarImageID != nil && *d.AvatarImageID != "" {
+		success, status, _, err := u.images.Get(ctx, *d.AvatarImageID).Deconstruct()
+		if !success {
+			if status == http.StatusNotFound {
+				msg := fmt.Sprintf("The image '%s' does not exist.", *d.AvatarImageID)
+				return result.Failure(msg).WithStatusCode(http.StatusBadRequest)
+			}
+
+			return result.Failure(err).WithStatusCode(status)
+		}
+	}
+
+	err := user.UpdateProfile(ctx, d)
+	if err != nil {
+		return result.Failure(err).WithStatusCode(http.StatusBadRequest)
+	}
+
+	return u.users.Update(ctx, user)
+}
+
+func (u *authorUsecase) GetPageAuthors(ctx context.Context, pageID string) result.Result {
+	return u.repo.GetPageAuthors(ctx, pageID)
+}
+
+// SetPageAuthors replaces the authors of the page. A page must have at least
+// one author, and its authors are shown in the order they're given in.
+func (u *authorUsecase) SetPageAuthors(ctx context.Context, pageID string, d *dto.PageAuthors) result.Result {
+	success, status, _, err := u.pages.Get(ctx, pageID).Deconstruct()
+	if !success {
+		return result.Failure(err).WithStatusCode(status)
+	}
+
+	ids := make([]string, 0, len(d.UserIDs))
+	seen := make(map[string]bool)
+
+	for _, id := range d.UserIDs {
+		if seen[id] {
+			continue
+		}
+
+		success, status, _, err := u.users.Get(ctx, id).Deconstruct()
+		if !success {
+			if status == http.StatusNotFound {
+				msg := fmt.Sprintf("The user '%s' does not exist.", id)
+				return result.Failure(msg).WithStatusCode(http.StatusBadRequest)
+			}
+
+			return result.Failure(err).WithStatusCode(status)
+		}
+
+		seen[id] = true
+		ids = append(ids, id)
+	}
+
+	switch true {
+	case len(ids) < 1:
+		return result.Failure("A page must have at least one author.").WithStatusCode(http.StatusBadRequest)
+	case len(ids) > MaxPageAuthors:
+		msg := fmt.Sprintf("A page cannot have more than %d authors.", MaxPageAuthors)
+		return result.Failure(msg).WithStatusCode(http.StatusBadRequest)
+	}
+
+	return u.repo.SetPageAuthors(ctx, pageID, ids)
+}
+
+// get gets a user from the repository, returning it as a *model.User.
+func (u *authorUsecase) get(ctx context.Context, id string) (*model.User, result.Result) {
+	success, status, value, err := u.users.Get(ctx, id).Deconstruct()
+	if !success {
+		return nil, result.Failure(err).WithStatusCode(status)
+	}
+
+	return value.(*model.User), result.Ok()
+}
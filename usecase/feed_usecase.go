@@ -0,0 +1,125 @@
+package usecase
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/reecerussell/distro-blog/domain/dto"
+	"github.com/reecerussell/distro-blog/domain/model"
+	"github.com/reecerussell/distro-blog/domain/repository"
+	"github.com/reecerussell/distro-blog/libraries/feed"
+	"github.com/reecerussell/distro-blog/libraries/logging"
+	"github.com/reecerussell/distro-blog/libraries/result"
+)
+
+// feedItemLimit is the number of blogs included in a feed.
+const feedItemLimit = 50
+
+type FeedUsecase interface {
+	Generate(ctx context.Context, q *dto.FeedQuery) result.Result
+}
+
+type feedUsecase struct {
+	pages repository.PageRepository
+	settings repository.SettingRepository
+	siteURL string
+	mediaURL string
+}
+
+// NewFeedUsecase returns a new FeedUsecase. The siteURL is the absolute url of the
+// public site, used to build item links, and mediaURL is the base url images are served
+// from, which can be empty if images should not be included.
+func NewFeedUsecase(pages repository.PageRepository, settings repository.SettingRepository, siteURL, mediaURL string) FeedUsecase {
+	return &feedUsecase{
+		pages: pages,
+		settings: settings,
+		siteURL: strings.TrimRight(siteURL, "/"),
+		mediaURL: strings.TrimRight(mediaURL, "/"),
+	}
+}
+
+// Generate renders a feed of the latest published blogs, in the requested format,
+// returning a *dto.Document. RSS is used if no format is given.
+func (u *feedUsecase) Generate(ctx context.Context, q *dto.FeedQuery) result.Result {
+	format := feed.FormatRSS
+	if q.Format != "" {
+		f, err := feed.ParseFormat(q.Format)
+		if err != nil {
+			return result.Failure(err).WithStatusCode(http.StatusBadRequest)
+		}
+
+		format = f
+	}
+
+	success, status, value, err := u.pages.ListPublishedBlogs(ctx, feedItemLimit, 0).Deconstruct()
+	if !success {
+		return result.Failure(err).WithStatusCode(status)
+	}
+
+	f := &feed.Feed{
+		ID: u.siteURL + "/",
+		Title: u.siteName(ctx),
+		Link: u.siteURL + "/",
+	}
+
+	blogs := value.(*dto.PagedList).Items.([]*dto.BlogListItem)
+	f.Items = make([]*feed.Item, len(blogs))
+
+	for i, b := range blogs {
+		item := &feed.Item{
+			ID: "urn:uuid:" + b.ID,
+			Title: b.Title,
+			Link: u.siteURL + model.PublicPath(b.URL, true),
+			Summary: b.Excerpt,
+			Published: b.PublishedAt,
+			Updated: b.UpdatedAt,
+		}
+
+		if b.Author != nil {
+			item.Author = b.Author.Name
+		}
+
+		if b.ImageID != nil && u.mediaURL != "" {
+			item.ImageURL = u.mediaURL + "/" + *b.ImageID
+		}
+
+		if b.UpdatedAt.After(f.Updated) {
+			f.Updated = b.UpdatedAt
+		}
+
+		if b.PublishedAt.After(f.Updated) {
+			f.Updated = b.PublishedAt
+		}
+
+		f.Items[i] = item
+	}
+
+	body, err := feed.Render(f, format)
+	if err != nil {
+		logging.Errorf("failed to render %s feed: %v\n", format, err)
+		return result.Failure(err)
+	}
+
+	return result.Ok().WithValue(&dto.Document{
+		ContentType: format.ContentType(),
+		Body: body,
+		LastModified: f.Updated,
+	})
+}
+
+// siteName returns the value of the site name setting, falling back
+// to the site's url if the setting could not be read.
+func (u *feedUsecase) siteName(ctx context.Context) string {
+	success, _, value, err := u.settings.Get(ctx, model.SettingSiteName).Deconstruct()
+	if !success {
+		logging.Errorf("failed to read the site name: %v\n", err)
+		return u.siteURL
+	}
+
+	if v := value.(*model.Setting).DTO().Value; v != nil && *v != "" {
+		return *v
+	}
+
+	return u.siteURL
+}
@@ -0,0 +1,253 @@
+package usecase
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/reecerussell/distro-blog/auth"
+	"github.com/reecerussell/distro-blog/domain/dto"
+	"github.com/reecerussell/distro-blog/domain/model"
+	"github.com/reecerussell/distro-blog/domain/repository"
+	"github.com/reecerussell/distro-blog/libraries/contextkey"
+	"github.com/reecerussell/distro-blog/libraries/logging"
+	"github.com/reecerussell/distro-blog/libraries/result"
+)
+
+// MFAUsecase is a high-level interface used to manage the current user's
+// two-factor authentication.
+type MFAUsecase interface {
+	Enroll(ctx context.Context) result.Result
+	Confirm(ctx context.Context, d *dto.MFACode) result.Result
+	Disable(ctx context.Context, d *dto.MFACode) result.Result
+	RegenerateRecoveryCodes(ctx context.Context, d *dto.MFACode) result.Result
+	Reset(ctx context.Context, userID string) result.Result
+}
+
+type mfaUsecase struct {
+	repo repository.MFARepository
+	users repository.UserRepository
+	tokens repository.TokenRepository
+	settings repository.SettingRepository
+}
+
+// NewMFAUsecase returns a new instance of MFAUsecase with the given repos. The
+// site name setting is used as the issuer shown in users' authenticator apps.
+func NewMFAUsecase(repo repository.MFARepository, users repository.UserRepository, tokens repository.TokenRepository, settings repository.SettingRepository) MFAUsecase {
+	return &mfaUsecase{
+		repo: repo,
+		users: users,
+		tokens: tokens,
+		settings: settings,
+	}
+}
+
+// currentUser returns the current user, or a failed result if the user isn't logged in.
+func (u *mfaUsecase) currentUser(ctx context.Context) (*model.User, result.Result) {
+	uid := ctx.Value(contextkey.ContextKey("user_id"))
+	if uid == nil {
+		return nil, result.Failure("User must be logged in to manage two-factor authentication.").
+			WithStatusCode(http.StatusUnauthorized)
+	}
+
+	success, status, value, err := u.users.Get(ctx, uid.(string)).Deconstruct()
+	if !success {
+		return nil, result.Failure(err).WithStatusCode(status)
+	}
+
+	return value.(*model.User), result.Ok()
+}
+
+// get returns the current user's authenticator.
+func (u *mfaUsecase) get(ctx context.Context) (*model.MFA, result.Result) {
+	user, res := u.currentUser(ctx)
+	if !res.IsOk() {
+		return nil, res
+	}
+
+	success, status, value, err := u.repo.Get(ctx, user.ID()).Deconstruct()
+	if !success {
+		if status == http.StatusNotFound {
+			return nil, result.Failure(model.ErrMFANotEnabled).WithStatusCode(http.StatusBadRequest)
+		}
+
+		return nil, result.Failure(err).WithStatusCode(status)
+	}
+
+	return value.(*model.MFA), result.Ok()
+}
+
+// Enroll creates a new authenticator for the current user, returning a *dto.MFAEnrollment,
+// which must be confirmed before it's required to sign in. Enrolling again, before the
+// authenticator has been confirmed, replaces it.
+func (u *mfaUsecase) Enroll(ctx context.Context) result.Result {
+	user, res := u.currentUser(ctx)
+	if !res.IsOk() {
+		return res
+	}
+
+	success, status, value, err := u.repo.Get(ctx, user.ID()).Deconstruct()
+	if success && value.(*model.MFA).IsEnabled() {
+		return result.Failure(model.ErrMFAAlreadyEnabled).WithStatusCode(http.StatusBadRequest)
+	} else if !success && status != http.StatusNotFound {
+		return result.Failure(err).WithStatusCode(status)
+	}
+
+	mfa, err := model.NewMFA(user.ID())
+	if err != nil {
+		logging.Error(err)
+		return result.Failure(err)
+	}
+
+	success, status, _, err = u.repo.Save(ctx, mfa).Deconstruct()
+	if !success {
+		return result.Failure(err).WithStatusCode(status)
+	}
+
+	return result.Ok().WithValue(&dto.MFAEnrollment{
+		Secret: mfa.Secret(),
+		URI: mfa.URI(u.issuer(ctx), user.Email()),
+	})
+}
+
+// issuer returns the value of the site name setting, falling back
+// to the default token issuer if the setting could not be read.
+func (u *mfaUsecase) issuer(ctx context.Context) string {
+	success, _, value, err := u.settings.Get(ctx, model.SettingSiteName).Deconstruct()
+	if !success {
+		logging.Errorf("failed to read the site name: %v\n", err)
+		return auth.DefaultIssuer
+	}
+
+	if v := value.(*model.Setting).DTO().Value; v != nil && *v != "" {
+		return *v
+	}
+
+	return auth.DefaultIssuer
+}
+
+// Confirm enables the current user's authenticator, using a code from it, returning
+// their recovery codes. The user's existing tokens are revoked, so they must sign in
+// again, using two-factor authentication.
+func (u *mfaUsecase) Confirm(ctx context.Context, d *dto.MFACode) result.Result {
+	mfa, res := u.get(ctx)
+	if !res.IsOk() {
+		return res
+	}
+
+	codes, err := mfa.Confirm(d.Code, time.Now().UTC())
+	switch err {
+	case nil:
+		break
+	case model.ErrMFAAlreadyEnabled, model.ErrInvalidMFACode:
+		return result.Failure(err).WithStatusCode(http.StatusBadRequest)
+	default:
+		logging.Error(err)
+		return result.Failure(err)
+	}
+
+	success, status, _, err := u.repo.Save(ctx, mfa).Deconstruct()
+	if !success {
+		return result.Failure(err).WithStatusCode(status)
+	}
+
+	success, status, _, err = u.tokens.RevokeUserTokens(ctx, mfa.UserID()).Deconstruct()
+	if !success {
+		return result.Failure(err).WithStatusCode(status)
+	}
+
+	return result.Ok().WithValue(&dto.MFARecoveryCodes{RecoveryCodes: codes})
+}
+
+// verify verifies a code from the current user's authenticator, or one of their recovery codes.
+// Only a limited number of codes can be tried, before the authenticator is locked for a while.
+func (u *mfaUsecase) verify(ctx context.Context, code string) (*model.MFA, result.Result) {
+	mfa, res := u.get(ctx)
+	if !res.IsOk() {
+		return nil, res
+	}
+
+	now := time.Now().UTC()
+	res = attemptMFA(ctx, u.repo, mfa.UserID(), now)
+	if !res.IsOk() {
+		return nil, res
+	}
+
+	err := mfa.Verify(code, now)
+	if err != nil {
+		return nil, result.Failure(err).WithStatusCode(http.StatusBadRequest)
+	}
+
+	return mfa, result.Ok()
+}
+
+// attemptMFA records an attempt to verify a code for the user, returning a failed result
+// if too many invalid codes have been entered.
+func attemptMFA(ctx context.Context, repo repository.MFARepository, userID string, now time.Time) result.Result {
+	success, status, _, err := repo.Attempt(ctx, userID, now).Deconstruct()
+	if !success {
+		if status == http.StatusTooManyRequests {
+			logging.Warningf("Two-factor authentication for user '%s' is locked, after too many invalid codes.\n", userID)
+			return result.Failure(model.ErrMFALocked).WithStatusCode(status)
+		}
+
+		return result.Failure(err).WithStatusCode(status)
+	}
+
+	return result.Ok()
+}
+
+// Disable removes the current user's authenticator, and their recovery codes, once
+// a code from it has been verified. The user's existing tokens are revoked.
+func (u *mfaUsecase) Disable(ctx context.Context, d *dto.MFACode) result.Result {
+	mfa, res := u.verify(ctx, d.Code)
+	if !res.IsOk() {
+		return res
+	}
+
+	success, status, _, err := u.repo.Delete(ctx, mfa.UserID()).Deconstruct()
+	if !success {
+		return result.Failure(err).WithStatusCode(status)
+	}
+
+	return u.tokens.RevokeUserTokens(ctx, mfa.UserID())
+}
+
+// RegenerateRecoveryCodes replaces the current user's recovery codes, once a code
+// from their authenticator has been verified, returning a *dto.MFARecoveryCodes.
+func (u *mfaUsecase) RegenerateRecoveryCodes(ctx context.Context, d *dto.MFACode) result.Result {
+	mfa, res := u.verify(ctx, d.Code)
+	if !res.IsOk() {
+		return res
+	}
+
+	codes, err := mfa.RegenerateRecoveryCodes()
+	if err != nil {
+		logging.Error(err)
+		return result.Failure(err)
+	}
+
+	success, status, _, err := u.repo.Save(ctx, mfa).Deconstruct()
+	if !success {
+		return result.Failure(err).WithStatusCode(status)
+	}
+
+	return result.Ok().WithValue(&dto.MFARecoveryCodes{RecoveryCodes: codes})
+}
+
+// Reset removes a user's authenticator, for users who have lost both it and their
+// recovery codes. The user's existing tokens are revoked, and if any of their scopes
+// require two-factor authentication, they'll have to enroll again to use them.
+func (u *mfaUsecase) Reset(ctx context.Context, userID string) result.Result {
+	success, status, _, err := u.users.Get(ctx, userID).Deconstruct()
+	if !success {
+		return result.Failure(err).WithStatusCode(status)
+	}
+
+	success, status, _, err = u.repo.Delete(ctx, userID).Deconstruct()
+	if !success {
+		return result.Failure(err).WithStatusCode(status)
+	}
+
+	return u.tokens.RevokeUserTokens(ctx, userID)
+}
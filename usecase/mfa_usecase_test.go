@@ -0,0 +1,40 @@
+package usecase
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/reecerussell/distro-blog/domain/dto"
+	"github.com/reecerussell/distro-blog/domain/model"
+	"github.com/reecerussell/distro-blog/libraries/contextkey"
+	"github.com/reecerussell/distro-blog/libraries/database"
+	"github.com/reecerussell/distro-blog/libraries/totp"
+	"github.com/reecerussell/distro-blog/persistence"
+)
+
+func TestMFAUsecase_DisableIsLockedOut(t *testing.T) {
+	userID := seedUser("lockout@mfaUsecase.test", "MyTestPassword1")
+	secret, _ := totp.GenerateSecret()
+	executeHelper("INSERT INTO `user_mfa` (`user_id`,`secret`,`confirmed_at`) VALUES (?,?,?);", userID, secret, time.Now().UTC())
+
+	db := database.NewMySQL(testConnString)
+	u := NewMFAUsecase(persistence.NewMFARepository(db), persistence.NewUserRepository(db),
+		persistence.NewTokenRepository(db), persistence.NewSettingRepository(db))
+	ctx := context.WithValue(context.Background(), contextkey.ContextKey("user_id"), userID)
+
+	for i := 0; i < model.MFAMaxAttempts; i++ {
+		_, status, _, _ := u.Disable(ctx, &dto.MFACode{Code: "abcd-efgh"}).Deconstruct()
+		if status != http.StatusBadRequest {
+			t.Fatalf("attempt %d: expected status %d but got %d", i+1, http.StatusBadRequest, status)
+		}
+	}
+
+	// the authenticator is locked, so even a valid code is refused.
+	code, _ := totp.Code(secret, time.Now())
+	_, status, _, _ := u.Disable(ctx, &dto.MFACode{Code: code}).Deconstruct()
+	if status != http.StatusTooManyRequests {
+		t.Errorf("expected status %d but got %d", http.StatusTooManyRequests, status)
+	}
+}
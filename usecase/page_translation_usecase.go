@@ -0,0 +1,105 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/reecerussell/distro-blog/domain/dto"
+	"github.com/reecerussell/distro-blog/domain/model"
+	"github.com/reecerussell/distro-blog/domain/repository"
+	"github.com/reecerussell/distro-blog/libraries/locale"
+	"github.com/reecerussell/distro-blog/libraries/logging"
+	"github.com/reecerussell/distro-blog/libraries/result"
+)
+
+// PageTranslationUsecase is used to manage the translations of pages into other locales.
+type PageTranslationUsecase interface {
+	List(ctx context.Context, pageID string) result.Result
+	Set(ctx context.Context, pageID, locale string, d *dto.SetPageTranslation) result.Result
+	Delete(ctx context.Context, pageID, locale string) result.Result
+}
+
+type pageTranslationUsecase struct {
+	repo repository.PageTranslationRepository
+	pages repository.PageRepository
+	settings repository.SettingRepository
+}
+
+func NewPageTranslationUsecase(repo repository.PageTranslationRepository, pages repository.PageRepository, settings repository.SettingRepository) PageTranslationUsecase {
+	return &pageTranslationUsecase{
+		repo: repo,
+		pages: pages,
+		settings: settings,
+	}
+}
+
+func (u *pageTranslationUsecase) List(ctx context.Context, pageID string) result.Result {
+	return u.repo.List(ctx, pageID)
+}
+
+// Set creates or replaces the page's translation in the given locale, returning it as a
+// *dto.PageTranslation. Pages can't be translated into the default locale, as that's
+// the locale they're written in, and translations can't share a path with another page.
+func (u *pageTranslationUsecase) Set(ctx context.Context, pageID, l string, d *dto.SetPageTranslation) result.Result {
+	success, status, value, err := u.pages.Get(ctx, pageID).Deconstruct()
+	if !success {
+		return result.Failure(err).WithStatusCode(status)
+	}
+
+	t, err := model.NewPageTranslation(value.(*model.Page), l, d)
+	if err != nil {
+		return result.Failure(err).WithStatusCode(http.StatusBadRequest)
+	}
+
+	defaultLocale, res := u.defaultLocale(ctx)
+	if !res.IsOk() {
+		return res
+	}
+
+	if t.Locale() == defaultLocale {
+		msg := fmt.Sprintf("Pages are written in the default locale, '%s', so cannot be translated into it.", defaultLocale)
+		return result.Failure(msg).WithStatusCode(http.StatusBadRequest)
+	}
+
+	success, status, value, err = u.repo.CountByPath(ctx, t).Deconstruct()
+	if !success {
+		return result.Failure(err).WithStatusCode(status)
+	}
+
+	if value.(int64) > 0 {
+		msg := fmt.Sprintf("The url '%s' is already being used.", t.Path())
+		return result.Failure(msg).WithStatusCode(http.StatusBadRequest)
+	}
+
+	success, status, _, err = u.repo.Save(ctx, t).Deconstruct()
+	if !success {
+		return result.Failure(err).WithStatusCode(status)
+	}
+
+	return result.Ok().WithValue(t.DTO())
+}
+
+func (u *pageTranslationUsecase) Delete(ctx context.Context, pageID, l string) result.Result {
+	l, err := locale.Normalize(l)
+	if err != nil {
+		return result.Failure(err).WithStatusCode(http.StatusBadRequest)
+	}
+
+	return u.repo.Delete(ctx, pageID, l)
+}
+
+// defaultLocale returns the locale pages are written in, using DefaultLocale if it hasn't been set.
+func (u *pageTranslationUsecase) defaultLocale(ctx context.Context) (string, result.Result) {
+	success, status, value, err := u.settings.Get(ctx, model.SettingDefaultLocale).Deconstruct()
+	if !success {
+		if status == http.StatusNotFound {
+			return model.DefaultLocale, result.Ok()
+		}
+
+		logging.Errorf("failed to read the default locale: %v\n", err)
+		return "", result.Failure(err).WithStatusCode(status)
+	}
+
+	return value.(*model.Setting).DefaultLocale(), result.Ok()
+}
@@ -2,46 +2,77 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"github.com/reecerussell/distro-blog/domain/service"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/reecerussell/distro-blog/domain/dto"
 	"github.com/reecerussell/distro-blog/domain/model"
 	"github.com/reecerussell/distro-blog/domain/repository"
+	"github.com/reecerussell/distro-blog/libraries/contextkey"
 	"github.com/reecerussell/distro-blog/libraries/logging"
+	"github.com/reecerussell/distro-blog/libraries/paging"
 	"github.com/reecerussell/distro-blog/libraries/result"
 )
 
 type PageUsecase interface {
 	CreatePage(ctx context.Context, d *dto.CreatePage) result.Result
 	CreateBlog(ctx context.Context, d *dto.CreatePage) result.Result
-	ListPages(ctx context.Context) result.Result
-	ListBlogs(ctx context.Context) result.Result
+	ListPages(ctx context.Context, q *dto.PageListQuery) result.Result
+	ListBlogs(ctx context.Context, q *dto.PageListQuery) result.Result
+	ListPublishedBlogs(ctx context.Context, q *dto.PagingQuery) result.Result
 	Get(ctx context.Context, id string, expand ...string) result.Result
 	Update(ctx context.Context, d *dto.UpdatePage, imageData []byte) result.Result
 	Activate(ctx context.Context, id string) result.Result
 	Deactivate(ctx context.Context, id string) result.Result
 	Delete(ctx context.Context, id string) result.Result
 	GetDropdownOptions(ctx context.Context) result.Result
+	ListRevisions(ctx context.Context, pageID string) result.Result
+	DiffRevisions(ctx context.Context, pageID, fromID, toID string, asHTML bool) result.Result
+	RestoreRevision(ctx context.Context, pageID, revisionID string) result.Result
+	PublishScheduled(ctx context.Context) result.Result
+	PublishDraft(ctx context.Context, id string) result.Result
+	Move(ctx context.Context, id string, d *dto.MovePage) result.Result
+	Schedule(ctx context.Context, id string, d *dto.SchedulePage) result.Result
+	ListTrash(ctx context.Context) result.Result
+	RestoreFromTrash(ctx context.Context, id string) result.Result
+	PurgeTrash(ctx context.Context, retention time.Duration) result.Result
 }
 
 type pageUsecase struct {
 	repo repository.PageRepository
 	svc *service.PageService
 	media MediaUsecase
+	related RelatedUsecase
 }
 
-func NewPageUsecase(repo repository.PageRepository, media MediaUsecase) PageUsecase {
+func NewPageUsecase(repo repository.PageRepository, media MediaUsecase, related RelatedUsecase) PageUsecase {
 	return &pageUsecase{
 		repo: repo,
 		svc: service.NewPageService(repo),
 		media: media,
+		related: related,
 	}
 }
 
 func (u *pageUsecase) CreatePage(ctx context.Context, d *dto.CreatePage) result.Result {
-	p, err := model.NewPage(ctx, d)
+	var p *model.Page
+	var err error
+
+	if d.ParentID != nil {
+		parent, res := u.getParent(ctx, *d.ParentID)
+		if !res.IsOk() {
+			return res
+		}
+
+		p, err = model.NewChildPage(ctx, d, parent)
+	} else {
+		p, err = model.NewPage(ctx, d)
+	}
+
 	if err != nil {
 		return result.Failure(err).WithStatusCode(http.StatusBadRequest)
 	}
@@ -69,15 +100,96 @@ func (u *pageUsecase) createPage(ctx context.Context, p *model.Page) result.Resu
 		return result.Failure(err).WithStatusCode(status)
 	}
 
-	return result.Ok().WithValue(p.GetID())
+	return result.Ok().WithValue(&dto.SavedPage{
+		ID: p.GetID(),
+		StrippedContent: p.StrippedContent(),
+	})
+}
+
+// ListPages returns a page of the pages matching the query, wrapped in a *dto.PagedList.
+func (u *pageUsecase) ListPages(ctx context.Context, q *dto.PageListQuery) result.Result {
+	res := normalizeListQuery(q)
+	if !res.IsOk() {
+		return res
+	}
+
+	return withNextCursor(u.repo.ListPages(ctx, q))
+}
+
+// ListBlogs returns a page of the blogs matching the query, wrapped in a *dto.PagedList.
+func (u *pageUsecase) ListBlogs(ctx context.Context, q *dto.PageListQuery) result.Result {
+	res := normalizeListQuery(q)
+	if !res.IsOk() {
+		return res
+	}
+
+	return withNextCursor(u.repo.ListBlogs(ctx, q))
 }
 
-func (u *pageUsecase) ListPages(ctx context.Context) result.Result {
-	return u.repo.ListPages(ctx)
+// ListPublishedBlogs returns a page of active blogs, with the newest first, for the public site.
+func (u *pageUsecase) ListPublishedBlogs(ctx context.Context, q *dto.PagingQuery) result.Result {
+	limit, offset, err := normalizePaging(q.Limit, q.Offset, q.Cursor)
+	if err != nil {
+		return result.Failure(err).WithStatusCode(http.StatusBadRequest)
+	}
+
+	return withNextCursor(u.repo.ListPublishedBlogs(ctx, limit, offset))
+}
+
+// normalizePaging applies the paging limits to the limit and offset. If
+// a cursor is given, it takes precedence over the offset.
+func normalizePaging(limit, offset int, cursor string) (int, int, error) {
+	if cursor != "" {
+		var err error
+		offset, err = paging.DecodeCursor(cursor)
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+
+	limit, offset = paging.Normalize(limit, offset)
+
+	return limit, offset, nil
+}
+
+// normalizeListQuery validates the list query, and applies the paging limits.
+func normalizeListQuery(q *dto.PageListQuery) result.Result {
+	var err error
+	q.Limit, q.Offset, err = normalizePaging(q.Limit, q.Offset, q.Cursor)
+	if err != nil {
+		return result.Failure(err).WithStatusCode(http.StatusBadRequest)
+	}
+
+	switch strings.ToLower(q.SortBy) {
+	case "":
+		q.SortBy = dto.PageSortTitle
+	case dto.PageSortTitle, dto.PageSortCreated, dto.PageSortUpdated:
+		q.SortBy = strings.ToLower(q.SortBy)
+	default:
+		msg := fmt.Sprintf("Cannot sort by '%s'.", q.SortBy)
+		return result.Failure(msg).WithStatusCode(http.StatusBadRequest)
+	}
+
+	if q.From != nil && q.To != nil && q.To.Before(*q.From) {
+		return result.Failure("The 'to' date cannot be before the 'from' date.").WithStatusCode(http.StatusBadRequest)
+	}
+
+	q.Search = strings.TrimSpace(q.Search)
+
+	return result.Ok()
 }
 
-func (u *pageUsecase) ListBlogs(ctx context.Context) result.Result {
-	return u.repo.ListBlogs(ctx)
+// withNextCursor sets the next cursor of the paged list, in a successful result.
+func withNextCursor(res result.Result) result.Result {
+	success, status, value, err := res.Deconstruct()
+	if !success {
+		return result.Failure(err).WithStatusCode(status)
+	}
+
+	l := value.(*dto.PagedList)
+	l.NextCursor = paging.NextCursor(l.Limit, l.Offset, l.Total)
+
+	return result.Ok().WithValue(l)
 }
 
 func (u *pageUsecase) Get(ctx context.Context, id string, expand ...string) result.Result {
@@ -150,7 +262,15 @@ func (u *pageUsecase) Update(ctx context.Context, d *dto.UpdatePage, imageData [
 		return result.Failure(err).WithStatusCode(status)
 	}
 
-	return result.Ok()
+	// changes saved to the draft don't change the live blog.
+	if !p.HasDraft() {
+		u.requestRelatedRefresh(ctx, p)
+	}
+
+	return result.Ok().WithValue(&dto.SavedPage{
+		ID: p.GetID(),
+		StrippedContent: p.StrippedContent(),
+	})
 }
 
 func (u *pageUsecase) Activate(ctx context.Context, id string) result.Result {
@@ -175,6 +295,8 @@ func (u *pageUsecase) Activate(ctx context.Context, id string) result.Result {
 		return res
 	}
 
+	u.requestRelatedRefresh(ctx, p)
+
 	return result.Ok()
 }
 
@@ -200,9 +322,14 @@ func (u *pageUsecase) Deactivate(ctx context.Context, id string) result.Result {
 		return res
 	}
 
+	u.requestRelatedRefresh(ctx, p)
+
 	return result.Ok()
 }
 
+// Delete moves the page with the given id to the trash. Pages which still
+// have child pages, which aren't in the trash, cannot be deleted. As children
+// are always trashed before their parent, they're purged before it too.
 func (u *pageUsecase) Delete(ctx context.Context, id string) result.Result {
 	logging.Debugf("Attempting to delete page...\n")
 	logging.Debugf("Getting page...\n")
@@ -214,25 +341,391 @@ func (u *pageUsecase) Delete(ctx context.Context, id string) result.Result {
 	}
 
 	p := value.(*model.Page)
-	if imgID := p.GetImageID(); imgID != nil {
-		logging.Debugf("Deleting page image...\n")
-		success, status, _, err = u.media.Delete(ctx, *imgID).Deconstruct()
+	success, status, value, err = u.repo.CountChildren(ctx, id).Deconstruct()
+	if !success {
+		logging.Errorf("Failed to count child pages: %v\n", err)
+		return result.Failure(err).WithStatusCode(status)
+	}
+
+	if value.(int64) > 0 {
+		return result.Failure("page cannot be deleted while it has child pages").WithStatusCode(http.StatusBadRequest)
+	}
+
+	err = p.Trash(ctx)
+	if err != nil {
+		return result.Failure(err).WithStatusCode(http.StatusBadRequest)
+	}
+
+	logging.Debugf("Moving page to the trash...\n")
+	success, status, _, err = u.repo.Update(ctx, p).Deconstruct()
+	if !success {
+		logging.Errorf("Failed to trash page: %v\n", err)
+		return result.Failure(err).WithStatusCode(status)
+	}
+
+	u.requestRelatedRefresh(ctx, p)
+
+	return result.Ok()
+}
+
+func (u *pageUsecase) GetDropdownOptions(ctx context.Context) result.Result {
+	return u.repo.GetDropdownOptions(ctx)
+}
+
+// ListRevisions returns a list of revisions for the page with the given id.
+func (u *pageUsecase) ListRevisions(ctx context.Context, pageID string) result.Result {
+	return u.repo.ListRevisions(ctx, pageID)
+}
+
+// DiffRevisions compares two revisions of a page, returning a field-by-field diff.
+// If asHTML is true, each text field will also contain a HTML diff.
+func (u *pageUsecase) DiffRevisions(ctx context.Context, pageID, fromID, toID string, asHTML bool) result.Result {
+	from, res := u.getRevision(ctx, pageID, fromID)
+	if !res.IsOk() {
+		return res
+	}
+
+	to, res := u.getRevision(ctx, pageID, toID)
+	if !res.IsOk() {
+		return res
+	}
+
+	return result.Ok().WithValue(from.Diff(to, asHTML))
+}
+
+// RestoreRevision restores the content of a page to an older revision. The
+// restore is saved as a new update, creating another revision.
+func (u *pageUsecase) RestoreRevision(ctx context.Context, pageID, revisionID string) result.Result {
+	logging.Debugf("Attempting to restore page revision...\n")
+	rev, res := u.getRevision(ctx, pageID, revisionID)
+	if !res.IsOk() {
+		return res
+	}
+
+	success, status, value, err := u.repo.Get(ctx, pageID).Deconstruct()
+	if !success {
+		logging.Errorf("Failed to fetch page: %v\n", err)
+		return result.Failure(err).WithStatusCode(status)
+	}
+
+	p := value.(*model.Page)
+	err = p.Restore(ctx, rev)
+	if err != nil {
+		logging.Errorf("Failed to restore page model: %v\n", err)
+		return result.Failure(err).WithStatusCode(http.StatusBadRequest)
+	}
+
+	res = u.svc.EnsureURLIsUnique(ctx, p)
+	if !res.IsOk() {
+		return res
+	}
+
+	logging.Debugf("Saving changes...\n")
+	return u.save(ctx, p)
+}
+
+// getRevision gets a revision from the repository, ensuring it belongs to the given page.
+func (u *pageUsecase) getRevision(ctx context.Context, pageID, id string) (*model.PageRevision, result.Result) {
+	success, status, value, err := u.repo.GetRevision(ctx, id).Deconstruct()
+	if !success {
+		return nil, result.Failure(err).WithStatusCode(status)
+	}
+
+	rev := value.(*model.PageRevision)
+	if rev.PageID() != pageID {
+		msg := fmt.Sprintf("The revision '%s' does not belong to this page.", id)
+		return nil, result.Failure(msg).WithStatusCode(http.StatusNotFound)
+	}
+
+	return rev, result.Ok()
+}
+
+// PublishScheduled activates and deactivates any pages which have a publish or unpublish
+// date which is due. Each page is updated through the domain model, so audit events
+// are still raised. The number of pages updated is returned as the result's value.
+func (u *pageUsecase) PublishScheduled(ctx context.Context) result.Result {
+	now := time.Now().UTC()
+	logging.Debugf("Fetching pages scheduled on or before %s...\n", now.Format(time.RFC3339))
+	success, status, value, err := u.repo.GetScheduledIDs(ctx, now).Deconstruct()
+	if !success {
+		logging.Errorf("Failed to fetch scheduled pages: %v\n", err)
+		return result.Failure(err).WithStatusCode(status)
+	}
+
+	ids := value.([]string)
+	logging.Debugf("Found %d scheduled pages.\n", len(ids))
+
+	updated, failed, blogs := 0, 0, 0
+	for _, id := range ids {
+		success, _, value, err := u.repo.Get(ctx, id).Deconstruct()
+		if !success {
+			logging.Errorf("Failed to fetch page '%s': %v\n", id, err)
+			failed++
+			continue
+		}
+
+		p := value.(*model.Page)
+		if !p.ApplySchedule(ctx, now) {
+			continue
+		}
+
+		success, _, _, err = u.repo.Update(ctx, p).Deconstruct()
+		if !success {
+			logging.Errorf("Failed to save page '%s': %v\n", id, err)
+			failed++
+			continue
+		}
+
+		updated++
+		if p.IsBlog() {
+			blogs++
+		}
+	}
+
+	if blogs > 0 && u.related != nil {
+		success, _, _, err := u.related.RequestRefresh(ctx).Deconstruct()
+		if !success {
+			logging.Errorf("Failed to request a refresh of the related posts: %v\n", err)
+		}
+	}
+
+	if failed > 0 {
+		msg := fmt.Sprintf("%d of %d scheduled pages failed to update", failed, len(ids))
+		return result.Failure(msg)
+	}
+
+	return result.Ok().WithValue(updated)
+}
+
+// PublishDraft applies the draft of the page with the given id to the live page.
+// The page and its draft are saved in a single transaction.
+func (u *pageUsecase) PublishDraft(ctx context.Context, id string) result.Result {
+	logging.Debugf("Attempting to publish page draft...\n")
+	success, status, value, err := u.repo.Get(ctx, id).Deconstruct()
+	if !success {
+		logging.Errorf("Failed to fetch page: %v\n", err)
+		return result.Failure(err).WithStatusCode(status)
+	}
+
+	p := value.(*model.Page)
+	err = p.PublishDraft(ctx)
+	if err != nil {
+		logging.Errorf("Failed to publish page draft: %v\n", err)
+		return result.Failure(err).WithStatusCode(http.StatusBadRequest)
+	}
+
+	res := u.svc.EnsureURLIsUnique(ctx, p)
+	if !res.IsOk() {
+		return res
+	}
+
+	logging.Debugf("Saving changes...\n")
+	return u.save(ctx, p)
+}
+
+// save saves the page's content, then requests a refresh of the related posts if it's a blog.
+// Changes which were saved to the page's draft don't change the live blog, so don't need one.
+func (u *pageUsecase) save(ctx context.Context, p *model.Page) result.Result {
+	res := u.repo.Update(ctx, p)
+	if !res.IsOk() {
+		return res
+	}
+
+	if !p.HasDraft() {
+		u.requestRelatedRefresh(ctx, p)
+	}
+
+	return res
+}
+
+// requestRelatedRefresh requests a refresh of the related posts, after a blog has been
+// changed, which is done in the background, as it compares every blog. Failures are
+// only logged, as the blog itself has already been saved successfully.
+func (u *pageUsecase) requestRelatedRefresh(ctx context.Context, p *model.Page) {
+	if u.related == nil || !p.IsBlog() {
+		return
+	}
+
+	logging.Debugf("Requesting a refresh of the related posts...\n")
+	success, _, _, err := u.related.RequestRefresh(ctx).Deconstruct()
+	if !success {
+		logging.Errorf("Failed to request a refresh of the related posts: %v\n", err)
+	}
+}
+
+// Move moves a page beneath another page, or to the top-level, which
+// changes the path of the page and all of the pages beneath it.
+func (u *pageUsecase) Move(ctx context.Context, id string, d *dto.MovePage) result.Result {
+	success, status, value, err := u.repo.Get(ctx, id).Deconstruct()
+	if !success {
+		return result.Failure(err).WithStatusCode(status)
+	}
+
+	var parent *model.Page
+	if d.ParentID != nil {
+		var res result.Result
+		parent, res = u.getParent(ctx, *d.ParentID)
+		if !res.IsOk() {
+			return res
+		}
+	}
+
+	p := value.(*model.Page)
+	err = p.Move(ctx, parent)
+	if err != nil {
+		return result.Failure(err).WithStatusCode(http.StatusBadRequest)
+	}
+
+	res := u.svc.EnsureURLIsUnique(ctx, p)
+	if !res.IsOk() {
+		return res
+	}
+
+	success, status, _, err = u.repo.Update(ctx, p).Deconstruct()
+	if !success {
+		return result.Failure(err).WithStatusCode(status)
+	}
+
+	return result.Ok()
+}
+
+// Schedule sets, or clears, the dates the page will be automatically activated
+// and deactivated at. Unlike Update, which leaves the dates it isn't given as
+// they are, a nil date clears it.
+func (u *pageUsecase) Schedule(ctx context.Context, id string, d *dto.SchedulePage) result.Result {
+	success, status, value, err := u.repo.Get(ctx, id).Deconstruct()
+	if !success {
+		return result.Failure(err).WithStatusCode(status)
+	}
+
+	p := value.(*model.Page)
+	err = p.Schedule(ctx, d.PublishAt, d.UnpublishAt)
+	if err != nil {
+		return result.Failure(err).WithStatusCode(http.StatusBadRequest)
+	}
+
+	success, status, _, err = u.repo.Update(ctx, p).Deconstruct()
+	if !success {
+		return result.Failure(err).WithStatusCode(status)
+	}
+
+	return result.Ok()
+}
+
+// getParent returns the page with the given id, to be used as a parent page. A
+// missing parent is treated as a bad request, rather than the page not being found.
+func (u *pageUsecase) getParent(ctx context.Context, id string) (*model.Page, result.Result) {
+	success, status, value, err := u.repo.Get(ctx, id).Deconstruct()
+	if !success {
+		if status == http.StatusNotFound {
+			msg := fmt.Sprintf("parent page '%s' does not exist", id)
+			return nil, result.Failure(msg).WithStatusCode(http.StatusBadRequest)
+		}
+
+		return nil, result.Failure(err).WithStatusCode(status)
+	}
+
+	return value.(*model.Page), result.Ok()
+}
+
+// ListTrash returns a list of pages which are in the trash.
+func (u *pageUsecase) ListTrash(ctx context.Context) result.Result {
+	return u.repo.ListTrash(ctx)
+}
+
+// RestoreFromTrash takes the page with the given id out of the trash. If the
+// page's parent is in the trash, the parent must be restored first.
+func (u *pageUsecase) RestoreFromTrash(ctx context.Context, id string) result.Result {
+	success, status, value, err := u.repo.Get(ctx, id).Deconstruct()
+	if !success {
+		return result.Failure(err).WithStatusCode(status)
+	}
+
+	p := value.(*model.Page)
+	if parentID := p.ParentID(); parentID != nil {
+		success, status, value, err = u.repo.Get(ctx, *parentID).Deconstruct()
 		if !success {
-			logging.Errorf("Failed to delete image: %v\n", err)
 			return result.Failure(err).WithStatusCode(status)
 		}
+
+		if value.(*model.Page).IsTrashed() {
+			return result.Failure("the parent page must be restored from the trash first").
+				WithStatusCode(http.StatusBadRequest)
+		}
+	}
+
+	err = p.RestoreFromTrash(ctx)
+	if err != nil {
+		return result.Failure(err).WithStatusCode(http.StatusBadRequest)
 	}
 
-	logging.Debugf("Deleting page...\n")
-	success, status, _, err = u.repo.Delete(ctx, id).Deconstruct()
+	res := u.repo.Update(ctx, p)
+	if !res.IsOk() {
+		return res
+	}
+
+	return result.Ok()
+}
+
+// PurgeTrash permanently deletes pages which have been in the trash for longer
+// than the given retention period, along with their images. A failure to purge one
+// page does not stop the others from being purged, but will result in a failure.
+func (u *pageUsecase) PurgeTrash(ctx context.Context, retention time.Duration) result.Result {
+	before := time.Now().UTC().Add(-retention)
+	logging.Debugf("Fetching pages trashed on or before %s...\n", before.Format(time.RFC3339))
+	success, status, value, err := u.repo.GetTrashedIDs(ctx, before).Deconstruct()
 	if !success {
-		logging.Errorf("Failed to delete page: %v\n", err)
+		logging.Errorf("Failed to fetch trashed pages: %v\n", err)
 		return result.Failure(err).WithStatusCode(status)
 	}
-	
+
+	ids := value.([]string)
+	logging.Debugf("Found %d trashed pages.\n", len(ids))
+
+	failed := 0
+	for _, id := range ids {
+		err = u.purge(ctx, id)
+		if err != nil {
+			logging.Errorf("Failed to purge page '%s': %v\n", id, err)
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		msg := fmt.Sprintf("%d of %d trashed pages failed to purge", failed, len(ids))
+		return result.Failure(msg)
+	}
+
+	logging.Debugf("Purged %d pages.\n", len(ids))
+
 	return result.Ok()
 }
 
-func (u *pageUsecase) GetDropdownOptions(ctx context.Context) result.Result {
-	return u.repo.GetDropdownOptions(ctx)
-}
\ No newline at end of file
+func (u *pageUsecase) purge(ctx context.Context, id string) error {
+	success, _, value, err := u.repo.Get(ctx, id).Deconstruct()
+	if !success {
+		return err
+	}
+
+	uid := ctx.Value(contextkey.ContextKey("user_id"))
+	if uid == nil {
+		return errors.New("no user id is present in the context to audit the purge")
+	}
+
+	// the page is purged before its image is deleted, so a failed
+	// purge doesn't leave the page without its image.
+	success, _, _, err = u.repo.Purge(ctx, id, uid.(string), time.Now().UTC()).Deconstruct()
+	if !success {
+		return err
+	}
+
+	p := value.(*model.Page)
+	if imgID := p.GetImageID(); imgID != nil {
+		success, _, _, err = u.media.Delete(ctx, *imgID).Deconstruct()
+		if !success {
+			return fmt.Errorf("page was purged, but its image '%s' could not be deleted: %v", *imgID, err)
+		}
+	}
+
+	return nil
+}
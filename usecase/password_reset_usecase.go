@@ -0,0 +1,207 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/reecerussell/distro-blog/domain/dto"
+	"github.com/reecerussell/distro-blog/domain/model"
+	"github.com/reecerussell/distro-blog/domain/repository"
+	"github.com/reecerussell/distro-blog/libraries/logging"
+	"github.com/reecerussell/distro-blog/libraries/mail"
+	"github.com/reecerussell/distro-blog/libraries/result"
+	"github.com/reecerussell/distro-blog/password"
+)
+
+// passwordResetEmail is the body of password reset emails, formatted
+// with the user's name, the site name and the password reset link.
+const passwordResetEmail = `Hi %s,
+
+A password reset has been requested for your %s account. To choose a new password, follow the link below, which expires in %d minutes:
+
+%s
+
+If you didn't request a password reset, you can ignore this email, and your password won't be changed.
+`
+
+// PasswordResetUsecase is a high-level interface used to reset the
+// passwords of users who have forgotten them.
+type PasswordResetUsecase interface {
+	Request(ctx context.Context, d *dto.RequestPasswordReset) result.Result
+	Send(ctx context.Context, userID string) result.Result
+	Reset(ctx context.Context, d *dto.ResetPassword) result.Result
+}
+
+type passwordResetUsecase struct {
+	repo repository.PasswordResetRepository
+	users repository.UserRepository
+	tokens repository.TokenRepository
+	settings repository.SettingRepository
+	sender mail.Sender
+	resetURL string
+	pwd password.Service
+}
+
+// NewPasswordResetUsecase returns a new instance of PasswordResetUsecase with the given
+// repos, which emails password reset links using the sender. Links are made by adding
+// a "token" query parameter to the reset url, which should be the page used to set a
+// new password.
+func NewPasswordResetUsecase(repo repository.PasswordResetRepository, users repository.UserRepository, tokens repository.TokenRepository,
+	settings repository.SettingRepository, sender mail.Sender, resetURL string) PasswordResetUsecase {
+	return &passwordResetUsecase{
+		repo: repo,
+		users: users,
+		tokens: tokens,
+		settings: settings,
+		sender: sender,
+		resetURL: resetURL,
+		pwd: password.New(),
+	}
+}
+
+// Request emails a password reset link to the user with the given email address.
+// The result is successful if there isn't a user with the email address, so the
+// endpoint can't be used to find out which email addresses have accounts.
+func (u *passwordResetUsecase) Request(ctx context.Context, d *dto.RequestPasswordReset) result.Result {
+	success, status, value, err := u.users.GetByEmail(ctx, d.Email).Deconstruct()
+	if !success {
+		if status == http.StatusNotFound {
+			logging.Debugf("Password reset requested for unknown email address '%s'.\n", d.Email)
+			return result.Ok()
+		}
+
+		return result.Failure(err).WithStatusCode(status)
+	}
+
+	return u.send(ctx, value.(*model.User))
+}
+
+// Send emails a password reset link to the user with the given id. This is
+// used by admins, so they never have to handle users' passwords.
+func (u *passwordResetUsecase) Send(ctx context.Context, userID string) result.Result {
+	success, status, value, err := u.users.Get(ctx, userID).Deconstruct()
+	if !success {
+		return result.Failure(err).WithStatusCode(status)
+	}
+
+	return u.send(ctx, value.(*model.User))
+}
+
+// send creates a new password reset token for the user, and emails it to them.
+// Only the latest token emailed to the user can be used.
+func (u *passwordResetUsecase) send(ctx context.Context, user *model.User) result.Result {
+	t, value, err := model.NewPasswordResetToken(user.ID(), time.Now().UTC())
+	if err != nil {
+		logging.Error(err)
+		return result.Failure(err)
+	}
+
+	link, err := url.Parse(u.resetURL)
+	if err != nil {
+		logging.Errorf("invalid password reset url: %v\n", err)
+		return result.Failure("The password reset url is invalid.")
+	}
+
+	q := link.Query()
+	q.Set("token", value)
+	link.RawQuery = q.Encode()
+
+	success, status, _, err := u.repo.Create(ctx, t).Deconstruct()
+	if !success {
+		return result.Failure(err).WithStatusCode(status)
+	}
+
+	siteName := u.siteName(ctx, link)
+	msg := &mail.Message{
+		To: user.Email(),
+		Subject: fmt.Sprintf("Reset your %s password", siteName),
+		Body: fmt.Sprintf(passwordResetEmail, user.DisplayName(), siteName,
+			int(model.PasswordResetTokenLifetime.Minutes()), link.String()),
+	}
+
+	err = u.sender.Send(ctx, msg)
+	if err != nil {
+		logging.Errorf("failed to send password reset email: %v\n", err)
+		return result.Failure("An error occurred while sending the password reset email.")
+	}
+
+	return result.Ok()
+}
+
+// siteName returns the value of the site name setting, falling back
+// to the host of the reset link if the setting could not be read.
+func (u *passwordResetUsecase) siteName(ctx context.Context, link *url.URL) string {
+	success, _, value, err := u.settings.Get(ctx, model.SettingSiteName).Deconstruct()
+	if !success {
+		logging.Errorf("failed to read the site name: %v\n", err)
+		return link.Host
+	}
+
+	if v := value.(*model.Setting).DTO().Value; v != nil && *v != "" {
+		return *v
+	}
+
+	return link.Host
+}
+
+// Reset sets a new password for a user, using the token from a password reset email.
+// Each token can only be used once. Once the password has been reset, all of the
+// user's existing tokens are revoked, so they're signed out on every device.
+func (u *passwordResetUsecase) Reset(ctx context.Context, d *dto.ResetPassword) result.Result {
+	invalidErr := result.Failure("The password reset link is invalid or has expired.").WithStatusCode(http.StatusBadRequest)
+
+	success, status, value, err := u.repo.Get(ctx, model.HashPasswordResetToken(d.Token)).Deconstruct()
+	if !success {
+		if status == http.StatusNotFound {
+			return invalidErr
+		}
+
+		return result.Failure(err).WithStatusCode(status)
+	}
+
+	t := value.(*model.PasswordResetToken)
+	success, status, value, err = u.users.Get(ctx, t.UserID()).Deconstruct()
+	if !success {
+		if status == http.StatusNotFound {
+			return invalidErr
+		}
+
+		return result.Failure(err).WithStatusCode(status)
+	}
+
+	// the password is validated before the token is used, so
+	// the user can try again if their password isn't valid.
+	user := value.(*model.User)
+	err = user.ResetPassword(ctx, d.NewPassword, u.pwd)
+	if err != nil {
+		return result.Failure(err).WithStatusCode(http.StatusBadRequest)
+	}
+
+	err = t.Use(time.Now().UTC())
+	if err != nil {
+		return invalidErr
+	}
+
+	// the token is claimed before the password is saved, so if the same
+	// token is used by two requests at once, only one changes the password.
+	success, status, _, err = u.repo.Use(ctx, t).Deconstruct()
+	if !success {
+		if status == http.StatusConflict {
+			logging.Warningf("Password reset token for user '%s' was used more than once.\n", user.ID())
+			return invalidErr
+		}
+
+		return result.Failure(err).WithStatusCode(status)
+	}
+
+	success, status, _, err = u.users.Update(ctx, user).Deconstruct()
+	if !success {
+		logging.Errorf("Password reset token for user '%s' was used, but the password could not be saved: %v\n", user.ID(), err)
+		return result.Failure(err).WithStatusCode(status)
+	}
+
+	return u.tokens.RevokeUserTokens(ctx, user.ID())
+}
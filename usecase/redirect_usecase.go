@@ -0,0 +1,136 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/reecerussell/distro-blog/domain/dto"
+	"github.com/reecerussell/distro-blog/domain/model"
+	"github.com/reecerussell/distro-blog/domain/repository"
+	"github.com/reecerussell/distro-blog/libraries/result"
+)
+
+// RedirectUsecase is used to manage, and resolve, redirects on the public site.
+type RedirectUsecase interface {
+	List(ctx context.Context) result.Result
+	Get(ctx context.Context, id string) result.Result
+	Create(ctx context.Context, d *dto.CreateRedirect) result.Result
+	Update(ctx context.Context, d *dto.UpdateRedirect) result.Result
+	Delete(ctx context.Context, id string) result.Result
+	Resolve(ctx context.Context, path string) result.Result
+}
+
+type redirectUsecase struct {
+	repo repository.RedirectRepository
+}
+
+func NewRedirectUsecase(repo repository.RedirectRepository) RedirectUsecase {
+	return &redirectUsecase{
+		repo: repo,
+	}
+}
+
+func (u *redirectUsecase) List(ctx context.Context) result.Result {
+	return u.repo.List(ctx)
+}
+
+func (u *redirectUsecase) Get(ctx context.Context, id string) result.Result {
+	success, status, value, err := u.repo.Get(ctx, id).Deconstruct()
+	if !success {
+		return result.Failure(err).WithStatusCode(status)
+	}
+
+	return result.Ok().WithValue(value.(*model.Redirect).DTO())
+}
+
+func (u *redirectUsecase) Create(ctx context.Context, d *dto.CreateRedirect) result.Result {
+	r, err := model.NewRedirect(d)
+	if err != nil {
+		return result.Failure(err).WithStatusCode(http.StatusBadRequest)
+	}
+
+	success, status, _, err := u.repo.GetBySource(ctx, r.Source()).Deconstruct()
+	if success {
+		msg := fmt.Sprintf("a redirect from '%s' already exists", r.Source())
+		return result.Failure(msg).WithStatusCode(http.StatusConflict)
+	} else if status != http.StatusNotFound {
+		return result.Failure(err).WithStatusCode(status)
+	}
+
+	res := u.follow(ctx, r)
+	if !res.IsOk() {
+		return res
+	}
+
+	success, status, _, err = u.repo.Create(ctx, r).Deconstruct()
+	if !success {
+		return result.Failure(err).WithStatusCode(status)
+	}
+
+	return result.Ok().WithValue(r.GetID())
+}
+
+func (u *redirectUsecase) Update(ctx context.Context, d *dto.UpdateRedirect) result.Result {
+	success, status, value, err := u.repo.Get(ctx, d.ID).Deconstruct()
+	if !success {
+		return result.Failure(err).WithStatusCode(status)
+	}
+
+	r := value.(*model.Redirect)
+	err = r.Update(d)
+	if err != nil {
+		return result.Failure(err).WithStatusCode(http.StatusBadRequest)
+	}
+
+	res := u.follow(ctx, r)
+	if !res.IsOk() {
+		return res
+	}
+
+	return u.repo.Update(ctx, r)
+}
+
+// follow points r straight at the final target, if r's target is itself redirected.
+// Existing redirects are never chained, so only a single redirect needs to be followed.
+func (u *redirectUsecase) follow(ctx context.Context, r *model.Redirect) result.Result {
+	if !strings.HasPrefix(r.Target(), "/") {
+		return result.Ok()
+	}
+
+	success, status, value, err := u.repo.GetBySource(ctx, strings.ToLower(r.Target())).Deconstruct()
+	if !success {
+		if status == http.StatusNotFound {
+			return result.Ok()
+		}
+
+		return result.Failure(err).WithStatusCode(status)
+	}
+
+	err = r.Follow(value.(*model.Redirect))
+	if err != nil {
+		return result.Failure(err).WithStatusCode(http.StatusBadRequest)
+	}
+
+	return result.Ok()
+}
+
+func (u *redirectUsecase) Delete(ctx context.Context, id string) result.Result {
+	return u.repo.Delete(ctx, id)
+}
+
+// Resolve returns a *dto.Redirect for the redirect from the given path
+// on the public site, or a 404 result if the path isn't redirected.
+func (u *redirectUsecase) Resolve(ctx context.Context, path string) result.Result {
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+
+	success, status, value, err := u.repo.GetBySource(ctx, strings.ToLower(path)).Deconstruct()
+	if !success {
+		return result.Failure(err).WithStatusCode(status)
+	}
+
+	return result.Ok().WithValue(value.(*model.Redirect).DTO())
+}
@@ -0,0 +1,121 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/reecerussell/distro-blog/domain/dto"
+	"github.com/reecerussell/distro-blog/domain/repository"
+	"github.com/reecerussell/distro-blog/libraries/content"
+	"github.com/reecerussell/distro-blog/libraries/logging"
+	"github.com/reecerussell/distro-blog/libraries/result"
+)
+
+// Limits applied to the number of related posts.
+const (
+	DefaultRelatedPosts = 3
+	MaxRelatedPosts = 10
+)
+
+// RelatedUsecase is used to find the posts most related to each blog. Related
+// posts are computed ahead of time and stored, so they're cheap to read.
+type RelatedUsecase interface {
+	Refresh(ctx context.Context) result.Result
+	RequestRefresh(ctx context.Context) result.Result
+	RefreshIfRequested(ctx context.Context) result.Result
+	List(ctx context.Context, pageID string, q *dto.RelatedQuery) result.Result
+}
+
+type relatedUsecase struct {
+	repo repository.RelatedPostRepository
+}
+
+func NewRelatedUsecase(repo repository.RelatedPostRepository) RelatedUsecase {
+	return &relatedUsecase{
+		repo: repo,
+	}
+}
+
+// Refresh compares each active blog with every other, by the words in their title,
+// description and content, then replaces the stored related posts. As the weight of
+// each word depends on every blog, all of the blogs are refreshed at once.
+func (u *relatedUsecase) Refresh(ctx context.Context) result.Result {
+	now := time.Now().UTC()
+	success, status, value, err := u.repo.ListDocuments(ctx).Deconstruct()
+	if !success {
+		return result.Failure(err).WithStatusCode(status)
+	}
+
+	items := value.([]*dto.RelatedDocument)
+	docs := make([]*content.Document, len(items))
+
+	for i, item := range items {
+		text := item.Description
+		if item.Content != nil {
+			text += " " + content.PlainText(*item.Content)
+		}
+
+		docs[i] = &content.Document{
+			ID: item.ID,
+			Title: item.Title,
+			Text: text,
+		}
+	}
+
+	related := content.Related(docs, MaxRelatedPosts)
+
+	var posts []*dto.RelatedPost
+	for _, d := range docs {
+		for i, m := range related[d.ID] {
+			posts = append(posts, &dto.RelatedPost{
+				PageID: d.ID,
+				RelatedPageID: m.ID,
+				Score: m.Score,
+				Position: i,
+			})
+		}
+	}
+
+	logging.Debugf("Storing %d related posts for %d blogs...\n", len(posts), len(docs))
+
+	return u.repo.Replace(ctx, posts, now)
+}
+
+// RequestRefresh marks the related posts as out of date, after a blog has changed, so
+// they're refreshed by RefreshIfRequested. Refreshing compares every blog, so is done
+// in the background, rather than while saving a blog.
+func (u *relatedUsecase) RequestRefresh(ctx context.Context) result.Result {
+	return u.repo.RequestRefresh(ctx, time.Now().UTC())
+}
+
+// RefreshIfRequested refreshes the related posts, if a refresh has been requested
+// since they were last refreshed. The result's value is true if they were refreshed.
+func (u *relatedUsecase) RefreshIfRequested(ctx context.Context) result.Result {
+	success, status, value, err := u.repo.NeedsRefresh(ctx).Deconstruct()
+	if !success {
+		return result.Failure(err).WithStatusCode(status)
+	}
+
+	if !value.(bool) {
+		return result.Ok().WithValue(false)
+	}
+
+	success, status, _, err = u.Refresh(ctx).Deconstruct()
+	if !success {
+		return result.Failure(err).WithStatusCode(status)
+	}
+
+	return result.Ok().WithValue(true)
+}
+
+// List returns a []*dto.BlogListItem of the active blogs most related to the page.
+func (u *relatedUsecase) List(ctx context.Context, pageID string, q *dto.RelatedQuery) result.Result {
+	switch true {
+	case q.Limit < 1:
+		q.Limit = DefaultRelatedPosts
+	case q.Limit > MaxRelatedPosts:
+		q.Limit = MaxRelatedPosts
+	}
+
+	return u.repo.List(ctx, pageID, q.Limit)
+}
@@ -0,0 +1,127 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/reecerussell/distro-blog/domain/dto"
+	"github.com/reecerussell/distro-blog/libraries/result"
+)
+
+// memoryRelatedPosts is an in-memory RelatedPostRepository,
+// used to test the related posts usecase.
+type memoryRelatedPosts struct {
+	docs []*dto.RelatedDocument
+	posts []*dto.RelatedPost
+	requestedAt *time.Time
+	refreshedAt *time.Time
+}
+
+func (r *memoryRelatedPosts) ListDocuments(ctx context.Context) result.Result {
+	return result.Ok().WithValue(r.docs)
+}
+
+func (r *memoryRelatedPosts) Replace(ctx context.Context, posts []*dto.RelatedPost, refreshedAt time.Time) result.Result {
+	r.posts = posts
+	r.refreshedAt = &refreshedAt
+	return result.Ok()
+}
+
+func (r *memoryRelatedPosts) RequestRefresh(ctx context.Context, date time.Time) result.Result {
+	r.requestedAt = &date
+	return result.Ok()
+}
+
+func (r *memoryRelatedPosts) NeedsRefresh(ctx context.Context) result.Result {
+	needed := r.requestedAt != nil && (r.refreshedAt == nil || !r.requestedAt.Before(*r.refreshedAt))
+	return result.Ok().WithValue(needed)
+}
+
+func (r *memoryRelatedPosts) List(ctx context.Context, pageID string, limit int) result.Result {
+	var items []*dto.BlogListItem
+	for _, p := range r.posts {
+		if p.PageID == pageID && len(items) < limit {
+			items = append(items, &dto.BlogListItem{ID: p.RelatedPageID})
+		}
+	}
+
+	return result.Ok().WithValue(items)
+}
+
+func TestRelatedUsecase_Refresh(t *testing.T) {
+	body := "<p>Deploy <strong>Lambda</strong> functions written in Go.</p>"
+	repo := &memoryRelatedPosts{
+		docs: []*dto.RelatedDocument{
+			{ID: "1", Title: "Go on Lambda", Description: "Serverless Go", Content: &body},
+			{ID: "2", Title: "Lambda layers", Description: "Sharing code between functions"},
+			{ID: "3", Title: "Sourdough", Description: "Baking bread"},
+		},
+	}
+
+	u := NewRelatedUsecase(repo)
+	if success, _, _, err := u.Refresh(context.Background()).Deconstruct(); !success {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	success, _, value, err := u.List(context.Background(), "2", &dto.RelatedQuery{}).Deconstruct()
+	if !success {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	items := value.([]*dto.BlogListItem)
+	if len(items) != 1 || items[0].ID != "1" {
+		t.Errorf("expected blog '1' to be related to blog '2' but got: %v", items)
+	}
+
+	for _, p := range repo.posts {
+		if p.PageID == "3" || p.RelatedPageID == "3" {
+			t.Errorf("expected the unrelated blog not to be matched but got: %v", p)
+		}
+	}
+}
+
+func TestRelatedUsecase_RefreshIfRequested(t *testing.T) {
+	repo := &memoryRelatedPosts{
+		docs: []*dto.RelatedDocument{
+			{ID: "1", Title: "Go on Lambda", Description: "Serverless Go"},
+			{ID: "2", Title: "Lambda layers", Description: "Serverless Go functions"},
+		},
+	}
+
+	u := NewRelatedUsecase(repo)
+	ctx := context.Background()
+	if _, _, refreshed, _ := u.RefreshIfRequested(ctx).Deconstruct(); refreshed.(bool) {
+		t.Errorf("expected the related posts not to be refreshed, before a refresh was requested")
+	}
+
+	_ = u.RequestRefresh(ctx)
+	if _, _, refreshed, _ := u.RefreshIfRequested(ctx).Deconstruct(); !refreshed.(bool) {
+		t.Errorf("expected the related posts to be refreshed, once a refresh was requested")
+	}
+
+	if len(repo.posts) == 0 {
+		t.Errorf("expected related posts to be stored")
+	}
+}
+
+func TestRelatedUsecase_ListLimit(t *testing.T) {
+	repo := &memoryRelatedPosts{}
+	for i := 0; i < MaxRelatedPosts+5; i++ {
+		repo.posts = append(repo.posts, &dto.RelatedPost{PageID: "1", RelatedPageID: "x", Position: i})
+	}
+
+	u := NewRelatedUsecase(repo)
+	tests := map[int]int{
+		0: DefaultRelatedPosts,
+		5: 5,
+		MaxRelatedPosts + 5: MaxRelatedPosts,
+	}
+
+	for limit, expected := range tests {
+		_, _, value, _ := u.List(context.Background(), "1", &dto.RelatedQuery{Limit: limit}).Deconstruct()
+		if n := len(value.([]*dto.BlogListItem)); n != expected {
+			t.Errorf("limit %d: expected %d posts but got %d", limit, expected, n)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package usecase
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/reecerussell/distro-blog/domain/dto"
+	"github.com/reecerussell/distro-blog/domain/repository"
+	"github.com/reecerussell/distro-blog/libraries/content"
+	"github.com/reecerussell/distro-blog/libraries/paging"
+	"github.com/reecerussell/distro-blog/libraries/result"
+)
+
+// snippetLength is the maximum number of characters in a search result's snippet.
+const snippetLength = 200
+
+// maxSearchQueryLength is the maximum number of characters in a search query.
+const maxSearchQueryLength = 255
+
+// SearchUsecase is used to search the content of pages and blogs.
+type SearchUsecase interface {
+	Search(ctx context.Context, q *dto.SearchQuery) result.Result
+	SearchAll(ctx context.Context, q *dto.SearchQuery) result.Result
+}
+
+type searchUsecase struct {
+	index repository.SearchIndex
+}
+
+// NewSearchUsecase returns a new instance of SearchUsecase, using the given index.
+func NewSearchUsecase(index repository.SearchIndex) SearchUsecase {
+	return &searchUsecase{
+		index: index,
+	}
+}
+
+// Search returns a *dto.PagedList of *dto.SearchResult for the active pages and
+// blogs matching the query, with the most relevant first. This is used by the public site.
+func (u *searchUsecase) Search(ctx context.Context, q *dto.SearchQuery) result.Result {
+	return u.search(ctx, q, false)
+}
+
+// SearchAll is the same as Search, but also returns inactive pages and blogs,
+// and matches the unpublished drafts of pages.
+func (u *searchUsecase) SearchAll(ctx context.Context, q *dto.SearchQuery) result.Result {
+	return u.search(ctx, q, true)
+}
+
+func (u *searchUsecase) search(ctx context.Context, q *dto.SearchQuery, includeInactive bool) result.Result {
+	query := strings.TrimSpace(q.Query)
+	terms := content.Terms(query)
+
+	switch true {
+	case len(terms) < 1:
+		return result.Failure("A search query is required.").WithStatusCode(http.StatusBadRequest)
+	case len([]rune(query)) > maxSearchQueryLength:
+		return result.Failure("The search query cannot be greater than 255 characters long.").WithStatusCode(http.StatusBadRequest)
+	}
+
+	limit, offset, err := normalizePaging(q.Limit, q.Offset, q.Cursor)
+	if err != nil {
+		return result.Failure(err).WithStatusCode(http.StatusBadRequest)
+	}
+
+	success, status, value, err := u.index.Search(ctx, query, includeInactive, limit, offset).Deconstruct()
+	if !success {
+		return result.Failure(err).WithStatusCode(status)
+	}
+
+	l := value.(*dto.PagedList)
+	hits := l.Items.([]*dto.SearchHit)
+	items := make([]*dto.SearchResult, len(hits))
+
+	for i, h := range hits {
+		items[i] = searchResult(h, terms)
+	}
+
+	l.Items = items
+	l.NextCursor = paging.NextCursor(l.Limit, l.Offset, l.Total)
+
+	return result.Ok().WithValue(l)
+}
+
+// searchResult returns a *dto.SearchResult for the hit, highlighting the terms in its
+// title and in a snippet of its content. Pages which don't have any content containing
+// the terms use their description as the snippet.
+func searchResult(h *dto.SearchHit, terms []string) *dto.SearchResult {
+	r := &dto.SearchResult{
+		ID: h.ID,
+		Title: h.Title,
+		TitleHighlight: content.Highlight(h.Title, terms, len([]rune(h.Title))),
+		Description: h.Description,
+		Path: h.Path,
+		IsBlog: h.IsBlog,
+		IsActive: h.IsActive,
+		Score: h.Score,
+	}
+
+	text := h.Description
+	if h.Content != nil {
+		if c := content.PlainText(*h.Content); containsAny(c, terms) {
+			text = c
+		}
+	}
+
+	r.Snippet = content.Highlight(text, terms, snippetLength)
+
+	return r
+}
+
+func containsAny(s string, terms []string) bool {
+	s = strings.ToLower(s)
+	for _, t := range terms {
+		if strings.Contains(s, t) {
+			return true
+		}
+	}
+
+	return false
+}
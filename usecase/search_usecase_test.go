@@ -0,0 +1,156 @@
+package usecase
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/reecerussell/distro-blog/domain/dto"
+	"github.com/reecerussell/distro-blog/libraries/content"
+	"github.com/reecerussell/distro-blog/libraries/result"
+)
+
+// memoryIndex is an in-memory inverted index, used in place
+// of the MySQL search index, to test the search usecase.
+type memoryIndex struct {
+	docs map[string]*dto.SearchHit
+	postings map[string]map[string]float64
+}
+
+func newMemoryIndex(hits ...*dto.SearchHit) *memoryIndex {
+	i := &memoryIndex{
+		docs: make(map[string]*dto.SearchHit),
+		postings: make(map[string]map[string]float64),
+	}
+
+	for _, h := range hits {
+		i.docs[h.ID] = h
+
+		text := h.Description
+		if h.Content != nil {
+			text += " " + content.PlainText(*h.Content)
+		}
+
+		// matches in the title are weighted above the rest.
+		i.index(h.ID, h.Title, 3)
+		i.index(h.ID, text, 1)
+	}
+
+	return i
+}
+
+func (i *memoryIndex) index(id, text string, weight float64) {
+	for _, t := range strings.Fields(strings.ToLower(text)) {
+		t = strings.Trim(t, ".,!?")
+		if i.postings[t] == nil {
+			i.postings[t] = make(map[string]float64)
+		}
+
+		i.postings[t][id] += weight
+	}
+}
+
+func (i *memoryIndex) Search(ctx context.Context, query string, includeInactive bool, limit, offset int) result.Result {
+	scores := make(map[string]float64)
+	for _, t := range content.Terms(query) {
+		for id, s := range i.postings[t] {
+			if includeInactive || i.docs[id].IsActive {
+				scores[id] += s
+			}
+		}
+	}
+
+	var hits []*dto.SearchHit
+	for id, s := range scores {
+		h := *i.docs[id]
+		h.Score = s
+		hits = append(hits, &h)
+	}
+
+	sort.Slice(hits, func(a, b int) bool {
+		if hits[a].Score == hits[b].Score {
+			return hits[a].ID < hits[b].ID
+		}
+
+		return hits[a].Score > hits[b].Score
+	})
+
+	total := int64(len(hits))
+	if offset > len(hits) {
+		offset = len(hits)
+	}
+
+	hits = hits[offset:]
+	if len(hits) > limit {
+		hits = hits[:limit]
+	}
+
+	return result.Ok().WithValue(&dto.PagedList{
+		Items: hits,
+		Total: total,
+		Limit: limit,
+		Offset: offset,
+	})
+}
+
+func testSearchUsecase() SearchUsecase {
+	body := "<p>A guide to deploying <strong>Go</strong> functions to AWS Lambda.</p>"
+	draft := "<p>Notes about Lambda layers.</p>"
+
+	return NewSearchUsecase(newMemoryIndex(
+		&dto.SearchHit{ID: "1", Title: "Deploying Go", Description: "A guide", Path: "/blog/deploying-go", Content: &body, IsBlog: true, IsActive: true},
+		&dto.SearchHit{ID: "2", Title: "Lambda", Description: "All about Lambda", Path: "/lambda", IsActive: true},
+		&dto.SearchHit{ID: "3", Title: "Layers", Description: "Draft", Path: "/layers", Content: &draft},
+	))
+}
+
+func TestSearchUsecase_Search(t *testing.T) {
+	success, _, value, err := testSearchUsecase().Search(context.Background(), &dto.SearchQuery{Query: "lambda"}).Deconstruct()
+	if !success {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	l := value.(*dto.PagedList)
+	items := l.Items.([]*dto.SearchResult)
+	if l.Total != 2 || len(items) != 2 {
+		t.Fatalf("expected 2 results but got %d", len(items))
+	}
+
+	if items[0].ID != "2" || items[0].TitleHighlight != "<mark>Lambda</mark>" {
+		t.Errorf("expected the page titled 'Lambda' to be first but got: %v", items[0])
+	}
+
+	expected := "A guide to deploying Go functions to AWS <mark>Lambda</mark>."
+	if items[1].Snippet != expected {
+		t.Errorf("expected the snippet to be '%s' but got '%s'", expected, items[1].Snippet)
+	}
+}
+
+func TestSearchUsecase_SearchAll(t *testing.T) {
+	success, _, value, err := testSearchUsecase().SearchAll(context.Background(), &dto.SearchQuery{Query: "lambda", Limit: 1}).Deconstruct()
+	if !success {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	l := value.(*dto.PagedList)
+	if l.Total != 3 || len(l.Items.([]*dto.SearchResult)) != 1 {
+		t.Errorf("expected a page of 1 of 3 results but got %d", l.Total)
+	}
+
+	if l.NextCursor == nil {
+		t.Errorf("expected a cursor to the next page")
+	}
+}
+
+func TestSearchUsecase_SearchWithoutQuery(t *testing.T) {
+	res := testSearchUsecase().Search(context.Background(), &dto.SearchQuery{Query: "  \"\" "})
+	if res.IsOk() {
+		t.Fatalf("expected an error but got nil")
+	}
+
+	if _, status, _, _ := res.Deconstruct(); status != http.StatusBadRequest {
+		t.Errorf("expected a status code of %d but got %d", http.StatusBadRequest, status)
+	}
+}
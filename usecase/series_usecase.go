@@ -0,0 +1,150 @@
+package usecase
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/reecerussell/distro-blog/domain/dto"
+	"github.com/reecerussell/distro-blog/domain/model"
+	"github.com/reecerussell/distro-blog/domain/repository"
+	"github.com/reecerussell/distro-blog/libraries/result"
+)
+
+// SeriesUsecase is used to manage series of blogs, and the order of the blogs in them.
+type SeriesUsecase interface {
+	List(ctx context.Context) result.Result
+	Get(ctx context.Context, id string) result.Result
+	Create(ctx context.Context, d *dto.CreateSeries) result.Result
+	Update(ctx context.Context, d *dto.UpdateSeries) result.Result
+	Delete(ctx context.Context, id string) result.Result
+	AddPage(ctx context.Context, seriesID string, d *dto.AddSeriesPage) result.Result
+	RemovePage(ctx context.Context, seriesID, pageID string) result.Result
+	Reorder(ctx context.Context, seriesID string, d *dto.ReorderSeries) result.Result
+}
+
+type seriesUsecase struct {
+	repo repository.SeriesRepository
+	pages repository.PageRepository
+}
+
+func NewSeriesUsecase(repo repository.SeriesRepository, pages repository.PageRepository) SeriesUsecase {
+	return &seriesUsecase{
+		repo: repo,
+		pages: pages,
+	}
+}
+
+func (u *seriesUsecase) List(ctx context.Context) result.Result {
+	return u.repo.List(ctx)
+}
+
+// Get returns the series with the given id, as a *dto.Series, including its blogs.
+func (u *seriesUsecase) Get(ctx context.Context, id string) result.Result {
+	s, res := u.get(ctx, id)
+	if !res.IsOk() {
+		return res
+	}
+
+	return result.Ok().WithValue(s.DTO())
+}
+
+func (u *seriesUsecase) Create(ctx context.Context, d *dto.CreateSeries) result.Result {
+	s, err := model.NewSeries(d)
+	if err != nil {
+		return result.Failure(err).WithStatusCode(http.StatusBadRequest)
+	}
+
+	success, status, _, err := u.repo.Create(ctx, s).Deconstruct()
+	if !success {
+		return result.Failure(err).WithStatusCode(status)
+	}
+
+	return result.Ok().WithValue(s.GetID())
+}
+
+func (u *seriesUsecase) Update(ctx context.Context, d *dto.UpdateSeries) result.Result {
+	s, res := u.get(ctx, d.ID)
+	if !res.IsOk() {
+		return res
+	}
+
+	err := s.Update(d)
+	if err != nil {
+		return result.Failure(err).WithStatusCode(http.StatusBadRequest)
+	}
+
+	return u.repo.Update(ctx, s)
+}
+
+func (u *seriesUsecase) Delete(ctx context.Context, id string) result.Result {
+	return u.repo.Delete(ctx, id)
+}
+
+// AddPage adds a blog to the end of the series. A blog can
+// only be part of one series at a time.
+func (u *seriesUsecase) AddPage(ctx context.Context, seriesID string, d *dto.AddSeriesPage) result.Result {
+	s, res := u.get(ctx, seriesID)
+	if !res.IsOk() {
+		return res
+	}
+
+	success, status, value, err := u.pages.Get(ctx, d.PageID).Deconstruct()
+	if !success {
+		return result.Failure(err).WithStatusCode(status)
+	}
+
+	success, status, id, err := u.repo.GetSeriesIDByPage(ctx, d.PageID).Deconstruct()
+	switch true {
+	case success && id.(string) != seriesID:
+		return result.Failure("The blog is already part of another series.").WithStatusCode(http.StatusBadRequest)
+	case !success && status != http.StatusNotFound:
+		return result.Failure(err).WithStatusCode(status)
+	}
+
+	err = s.AddPage(value.(*model.Page))
+	if err != nil {
+		return result.Failure(err).WithStatusCode(http.StatusBadRequest)
+	}
+
+	return u.repo.Update(ctx, s)
+}
+
+// RemovePage removes a blog from the series, moving the blogs after it up a place.
+func (u *seriesUsecase) RemovePage(ctx context.Context, seriesID, pageID string) result.Result {
+	s, res := u.get(ctx, seriesID)
+	if !res.IsOk() {
+		return res
+	}
+
+	err := s.RemovePage(pageID)
+	if err != nil {
+		return result.Failure(err).WithStatusCode(http.StatusBadRequest)
+	}
+
+	return u.repo.Update(ctx, s)
+}
+
+// Reorder changes the order of the blogs in the series.
+func (u *seriesUsecase) Reorder(ctx context.Context, seriesID string, d *dto.ReorderSeries) result.Result {
+	s, res := u.get(ctx, seriesID)
+	if !res.IsOk() {
+		return res
+	}
+
+	err := s.Reorder(d.PageIDs)
+	if err != nil {
+		return result.Failure(err).WithStatusCode(http.StatusBadRequest)
+	}
+
+	return u.repo.Update(ctx, s)
+}
+
+// get gets a series from the repository, returning it as a *model.Series.
+func (u *seriesUsecase) get(ctx context.Context, id string) (*model.Series, result.Result) {
+	success, status, value, err := u.repo.Get(ctx, id).Deconstruct()
+	if !success {
+		return nil, result.Failure(err).WithStatusCode(status)
+	}
+
+	return value.(*model.Series), result.Ok()
+}
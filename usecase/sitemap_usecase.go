@@ -0,0 +1,129 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/reecerussell/distro-blog/domain/dto"
+	"github.com/reecerussell/distro-blog/domain/model"
+	"github.com/reecerussell/distro-blog/domain/repository"
+	"github.com/reecerussell/distro-blog/libraries/logging"
+	"github.com/reecerussell/distro-blog/libraries/result"
+	"github.com/reecerussell/distro-blog/libraries/sitemap"
+)
+
+const (
+	sitemapContentType = "application/xml; charset=utf-8"
+	robotsContentType = "text/plain; charset=utf-8"
+)
+
+type SitemapUsecase interface {
+	Sitemap(ctx context.Context, q *dto.SitemapQuery) result.Result
+	Robots(ctx context.Context) result.Result
+}
+
+type sitemapUsecase struct {
+	pages repository.PageRepository
+	settings repository.SettingRepository
+	siteURL string
+}
+
+// NewSitemapUsecase returns a new SitemapUsecase. The siteURL is the absolute
+// url of the public site, used to build the locations in the sitemap.
+func NewSitemapUsecase(pages repository.PageRepository, settings repository.SettingRepository, siteURL string) SitemapUsecase {
+	return &sitemapUsecase{
+		pages: pages,
+		settings: settings,
+		siteURL: strings.TrimRight(siteURL, "/"),
+	}
+}
+
+// Sitemap returns a *dto.Document containing the sitemap of all active pages which are
+// not excluded from indexing by their SEO settings. If there are more pages than a single
+// sitemap can hold, a sitemap index is returned instead, referencing each page of the sitemap.
+func (u *sitemapUsecase) Sitemap(ctx context.Context, q *dto.SitemapQuery) result.Result {
+	success, status, value, err := u.pages.ListSitemapEntries(ctx).Deconstruct()
+	if !success {
+		return result.Failure(err).WithStatusCode(status)
+	}
+
+	entries := value.([]*dto.SitemapEntry)
+	urls := make([]sitemap.URL, len(entries))
+
+	for i, e := range entries {
+		urls[i] = sitemap.URL{
+			Loc: u.siteURL + "/" + e.Path,
+			LastMod: e.UpdatedAt,
+		}
+	}
+
+	chunks := sitemap.Split(urls, sitemap.MaxURLs)
+	if q.Page < 0 || q.Page > len(chunks) {
+		msg := fmt.Sprintf("sitemap page %d does not exist", q.Page)
+		return result.Failure(msg).WithStatusCode(http.StatusNotFound)
+	}
+
+	var body []byte
+	switch {
+	case q.Page > 0:
+		urls = chunks[q.Page-1]
+		body, err = sitemap.Build(urls)
+	case len(chunks) > 1:
+		sitemaps := make([]sitemap.Sitemap, len(chunks))
+		for i, c := range chunks {
+			sitemaps[i] = sitemap.Sitemap{
+				Loc: fmt.Sprintf("%s/sitemap.xml?page=%d", u.siteURL, i+1),
+				LastMod: sitemap.LastModified(c),
+			}
+		}
+
+		body, err = sitemap.BuildIndex(sitemaps)
+	default:
+		body, err = sitemap.Build(urls)
+	}
+
+	if err != nil {
+		logging.Errorf("failed to build the sitemap: %v", err)
+		return result.Failure(err)
+	}
+
+	return result.Ok().WithValue(&dto.Document{
+		ContentType: sitemapContentType,
+		Body: body,
+		LastModified: sitemap.LastModified(urls),
+	})
+}
+
+// Robots returns a *dto.Document containing the site's robots.txt, which
+// disallows the paths in the robots setting and references the sitemap.
+func (u *sitemapUsecase) Robots(ctx context.Context) result.Result {
+	var disallow []string
+
+	success, status, value, err := u.settings.Get(ctx, model.SettingRobotsDisallow).Deconstruct()
+	if success {
+		disallow = value.(*model.Setting).RobotsDisallow()
+	} else if status != http.StatusNotFound {
+		return result.Failure(err).WithStatusCode(status)
+	}
+
+	var b strings.Builder
+	b.WriteString("User-agent: *\n")
+
+	if len(disallow) < 1 {
+		// an empty disallow allows crawlers to visit everything.
+		b.WriteString("Disallow:\n")
+	}
+
+	for _, p := range disallow {
+		b.WriteString("Disallow: " + p + "\n")
+	}
+
+	b.WriteString("\nSitemap: " + u.siteURL + "/sitemap.xml\n")
+
+	return result.Ok().WithValue(&dto.Document{
+		ContentType: robotsContentType,
+		Body: []byte(b.String()),
+	})
+}
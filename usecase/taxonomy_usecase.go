@@ -0,0 +1,166 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/reecerussell/distro-blog/domain/dto"
+	"github.com/reecerussell/distro-blog/domain/model"
+	"github.com/reecerussell/distro-blog/domain/repository"
+	"github.com/reecerussell/distro-blog/domain/service"
+	"github.com/reecerussell/distro-blog/libraries/logging"
+	"github.com/reecerussell/distro-blog/libraries/result"
+)
+
+// TaxonomyUsecase is used to manage tags and categories, and the pages assigned to them.
+type TaxonomyUsecase interface {
+	List(ctx context.Context, termType string) result.Result
+	Create(ctx context.Context, d *dto.CreateTerm) result.Result
+	Rename(ctx context.Context, d *dto.RenameTerm) result.Result
+	Merge(ctx context.Context, sourceID string, d *dto.MergeTerms) result.Result
+	Delete(ctx context.Context, id string) result.Result
+	GetPageTerms(ctx context.Context, pageID string) result.Result
+	SetPageTerms(ctx context.Context, pageID string, d *dto.PageTerms) result.Result
+	ListBlogsByTerm(ctx context.Context, termType, slug string) result.Result
+}
+
+type taxonomyUsecase struct {
+	repo repository.TermRepository
+	pages repository.PageRepository
+	svc *service.TaxonomyService
+}
+
+func NewTaxonomyUsecase(repo repository.TermRepository, pages repository.PageRepository) TaxonomyUsecase {
+	return &taxonomyUsecase{
+		repo: repo,
+		pages: pages,
+		svc: service.NewTaxonomyService(repo),
+	}
+}
+
+func (u *taxonomyUsecase) List(ctx context.Context, termType string) result.Result {
+	termType = strings.ToLower(termType)
+	if termType != "" && !model.IsValidTermType(termType) {
+		msg := fmt.Sprintf("type '%s' is not valid", termType)
+		return result.Failure(msg).WithStatusCode(http.StatusBadRequest)
+	}
+
+	return u.repo.List(ctx, termType)
+}
+
+func (u *taxonomyUsecase) Create(ctx context.Context, d *dto.CreateTerm) result.Result {
+	t, err := model.NewTerm(d)
+	if err != nil {
+		return result.Failure(err).WithStatusCode(http.StatusBadRequest)
+	}
+
+	res := u.svc.EnsureSlugIsUnique(ctx, t)
+	if !res.IsOk() {
+		return res
+	}
+
+	success, status, _, err := u.repo.Create(ctx, t).Deconstruct()
+	if !success {
+		return result.Failure(err).WithStatusCode(status)
+	}
+
+	return result.Ok().WithValue(t.GetID())
+}
+
+func (u *taxonomyUsecase) Rename(ctx context.Context, d *dto.RenameTerm) result.Result {
+	t, res := u.get(ctx, d.ID)
+	if !res.IsOk() {
+		return res
+	}
+
+	err := t.Rename(d.Name)
+	if err != nil {
+		return result.Failure(err).WithStatusCode(http.StatusBadRequest)
+	}
+
+	res = u.svc.EnsureSlugIsUnique(ctx, t)
+	if !res.IsOk() {
+		return res
+	}
+
+	return u.repo.Update(ctx, t)
+}
+
+// Merge moves all pages from the source term onto the target term,
+// then deletes the source term. Both terms must be of the same type.
+func (u *taxonomyUsecase) Merge(ctx context.Context, sourceID string, d *dto.MergeTerms) result.Result {
+	logging.Debugf("Attempting to merge term '%s' into '%s'...\n", sourceID, d.TargetID)
+	source, res := u.get(ctx, sourceID)
+	if !res.IsOk() {
+		return res
+	}
+
+	target, res := u.get(ctx, d.TargetID)
+	if !res.IsOk() {
+		return res
+	}
+
+	err := source.CanMergeInto(target)
+	if err != nil {
+		return result.Failure(err).WithStatusCode(http.StatusBadRequest)
+	}
+
+	return u.repo.Merge(ctx, source, target)
+}
+
+func (u *taxonomyUsecase) Delete(ctx context.Context, id string) result.Result {
+	return u.repo.Delete(ctx, id)
+}
+
+func (u *taxonomyUsecase) GetPageTerms(ctx context.Context, pageID string) result.Result {
+	return u.repo.GetPageTerms(ctx, pageID)
+}
+
+// SetPageTerms replaces the tags and categories the page is assigned to.
+func (u *taxonomyUsecase) SetPageTerms(ctx context.Context, pageID string, d *dto.PageTerms) result.Result {
+	success, status, _, err := u.pages.Get(ctx, pageID).Deconstruct()
+	if !success {
+		return result.Failure(err).WithStatusCode(status)
+	}
+
+	ids := make([]string, 0, len(d.TermIDs))
+	seen := make(map[string]bool)
+
+	for _, id := range d.TermIDs {
+		if seen[id] {
+			continue
+		}
+
+		success, status, _, err := u.repo.Get(ctx, id).Deconstruct()
+		if !success {
+			if status == http.StatusNotFound {
+				msg := fmt.Sprintf("The term '%s' does not exist.", id)
+				return result.Failure(msg).WithStatusCode(http.StatusBadRequest)
+			}
+
+			return result.Failure(err).WithStatusCode(status)
+		}
+
+		seen[id] = true
+		ids = append(ids, id)
+	}
+
+	return u.repo.SetPageTerms(ctx, pageID, ids)
+}
+
+// ListBlogsByTerm returns a list of active blogs assigned to the term with the given type and slug.
+func (u *taxonomyUsecase) ListBlogsByTerm(ctx context.Context, termType, slug string) result.Result {
+	return u.repo.ListBlogsByTerm(ctx, termType, strings.ToLower(slug))
+}
+
+// get gets a term from the repository, returning it as a *model.Term.
+func (u *taxonomyUsecase) get(ctx context.Context, id string) (*model.Term, result.Result) {
+	success, status, value, err := u.repo.Get(ctx, id).Deconstruct()
+	if !success {
+		return nil, result.Failure(err).WithStatusCode(status)
+	}
+
+	return value.(*model.Term), result.Ok()
+}
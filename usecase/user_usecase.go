@@ -25,7 +25,6 @@ type UserUsecase interface {
 	Update(ctx context.Context, uu *dto.UpdateUser) result.Result
 	Delete(ctx context.Context, id string) result.Result
 	ChangePassword(ctx context.Context, d *dto.ChangePassword) result.Result
-	ResetPassword(ctx context.Context, id string) result.Result
 }
 
 // userUsecase is an implementation of the UserUsecase interface.
@@ -162,21 +161,3 @@ func (u *userUsecase) ChangePassword(ctx context.Context, d *dto.ChangePassword)
 
 	return result.Ok()
 }
-
-// ResetPassword resets the password for the user with the given id.
-func (u *userUsecase) ResetPassword(ctx context.Context, id string) result.Result {
-	success, status, value, err := u.repo.Get(ctx, id).Deconstruct()
-	if !success{
-		return result.Failure(err).WithStatusCode(status)
-	}
-
-	user := value.(*model.User)
-	pwd := user.ResetPassword(ctx, u.pwdServ)
-
-	success, status, _, err = u.repo.Update(ctx, user).Deconstruct()
-	if !success {
-		return result.Failure(err).WithStatusCode(status)
-	}
-
-	return result.Ok().WithValue(pwd)
-}
\ No newline at end of file
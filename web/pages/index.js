@@ -26,11 +26,14 @@ const Index = ({ status, data }) => {
 	);
 };
 
-const getServerSideProps = async ({ req }) => {
+const getServerSideProps = async ({ req, res: serverRes }) => {
 	const { pathname } = parseUrl(req.url, true);
 	let url = pathname.substr(1);
 	if (url.substr(0, 5) === "blog/") {
 		url = "blog-" + url.substr(5);
+	} else {
+		// child pages have nested paths, i.e. "services/consulting".
+		url = encodeURIComponent(url);
 	}
 
 	console.log(pathname);
@@ -55,6 +58,17 @@ const getServerSideProps = async ({ req }) => {
 		console.error(resData.error);
 	}
 
+	// the page has moved, so redirect the client to its new location.
+	if (res.status === 200 && resData.data && resData.data.redirect) {
+		const { location, statusCode } = resData.data.redirect;
+		serverRes.writeHead(statusCode, { Location: location });
+		serverRes.end();
+
+		return {
+			props: { status: statusCode },
+		};
+	}
+
 	return {
 		props: {
 			status: res.status,